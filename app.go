@@ -21,7 +21,6 @@ import (
 	"flamingo.me/flamingo/v3/framework/opencensus"
 	"flamingo.me/flamingo/v3/framework/web"
 	"github.com/spf13/cobra"
-	"go.opencensus.io/plugin/ochttp"
 )
 
 type (
@@ -210,7 +209,7 @@ func App(modules []dingo.Module, options ...ApplicationOption) {
 	}
 }
 
-// Run runs the Root Cmd and triggers the standard event
+// Run starts the modules, triggers the standard event and runs the Root Cmd
 func (app *Application) Run() error {
 	injector, err := app.area.GetInitializedInjector()
 	if err != nil {
@@ -225,13 +224,21 @@ func (app *Application) Run() error {
 	rootCmd := i.(*cobra.Command)
 	rootCmd.SetArgs(app.flagset.Args())
 
+	i, err = injector.GetInstance(new(cmd.Lifecycle))
+	if err != nil {
+		return fmt.Errorf("app: get cmd.Lifecycle: %w", err)
+	}
+	if err := i.(*cmd.Lifecycle).Start(context.Background(), app.area); err != nil {
+		return fmt.Errorf("app: start: %w", err)
+	}
+
 	i, err = injector.GetInstance(new(eventRouterProvider))
 	if err != nil {
 		return fmt.Errorf("app: get eventRouterProvider: %w", err)
 	}
 	i.(eventRouterProvider)().Dispatch(context.Background(), new(flamingo.StartupEvent))
 
-	return rootCmd.Execute()
+	return cmd.Execute(rootCmd)
 }
 
 func typeName(of reflect.Type) string {
@@ -321,6 +328,7 @@ type servemodule struct {
 	eventRouter       flamingo.EventRouter
 	logger            flamingo.Logger
 	configuredSampler *opencensus.ConfiguredURLPrefixSampler
+	propagation       *opencensus.Propagation
 	certFile, keyFile string
 }
 
@@ -330,6 +338,7 @@ func (a *servemodule) Inject(
 	eventRouter flamingo.EventRouter,
 	logger flamingo.Logger,
 	configuredSampler *opencensus.ConfiguredURLPrefixSampler,
+	propagation *opencensus.Propagation,
 ) {
 	a.router = router
 	a.eventRouter = eventRouter
@@ -338,6 +347,7 @@ func (a *servemodule) Inject(
 		Addr: ":3322",
 	}
 	a.configuredSampler = configuredSampler
+	a.propagation = propagation
 }
 
 // Configure dependency injection
@@ -355,7 +365,7 @@ func serveProvider(a *servemodule, logger flamingo.Logger) *cobra.Command {
 		Short: "Default serve command - starts on Port 3322",
 		Run: func(cmd *cobra.Command, args []string) {
 			logger.Info(fmt.Sprintf("Starting HTTP Server at %s .....", a.server.Addr))
-			a.server.Handler = &ochttp.Handler{IsPublicEndpoint: true, Handler: a.router.Handler(), GetStartOptions: a.configuredSampler.GetStartOptions()}
+			a.server.Handler = a.propagation.Handler(a.router.Handler(), a.configuredSampler.GetStartOptions())
 
 			err := a.listenAndServe()
 			if err != nil {
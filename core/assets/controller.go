@@ -0,0 +1,101 @@
+package assets
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"flamingo.me/flamingo/v3/framework/web"
+)
+
+type (
+	// Controller serves assets from the asset file system
+	Controller struct {
+		responder       *web.Responder
+		manifest        *Manifest
+		fs              http.FileSystem
+		immutableMaxAge int
+		spaFallback     string
+	}
+
+	fileResult struct {
+		request      *http.Request
+		file         http.File
+		info         os.FileInfo
+		cacheControl string
+	}
+)
+
+// Inject dependencies
+func (c *Controller) Inject(responder *web.Responder, manifest *Manifest, config *struct {
+	FileSystem      http.FileSystem `inject:"core.assets,optional"`
+	Dir             string          `inject:"config:core.assets.dir"`
+	ImmutableMaxAge float64         `inject:"config:core.assets.immutableMaxAge"`
+	SpaFallback     string          `inject:"config:core.assets.spaFallback"`
+}) *Controller {
+	c.responder = responder
+	c.manifest = manifest
+	c.fs = fileSystem(config.FileSystem, config.Dir)
+	c.immutableMaxAge = int(config.ImmutableMaxAge)
+	c.spaFallback = config.SpaFallback
+	return c
+}
+
+// Serve an asset file, directories are never listed
+func (c *Controller) Serve(ctx context.Context, r *web.Request) web.Result {
+	name, err := cleanPath(r.Params["name"])
+	if err != nil {
+		return c.responder.NotFound(err)
+	}
+
+	file, info, err := c.open(name)
+	if err != nil {
+		if c.spaFallback != "" && strings.Contains(r.Request().Header.Get("Accept"), "text/html") {
+			if file, info, err := c.open(path.Clean("/" + c.spaFallback)); err == nil {
+				return &fileResult{request: r.Request(), file: file, info: info, cacheControl: "no-cache"}
+			}
+		}
+		return c.responder.NotFound(err)
+	}
+
+	cacheControl := "no-cache"
+	if isFingerprinted(name) || c.manifest.Contains(name) {
+		cacheControl = fmt.Sprintf("public, max-age=%d, immutable", c.immutableMaxAge)
+	}
+
+	return &fileResult{request: r.Request(), file: file, info: info, cacheControl: cacheControl}
+}
+
+func (c *Controller) open(name string) (http.File, os.FileInfo, error) {
+	file, err := c.fs.Open(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, nil, err
+	}
+
+	if info.IsDir() {
+		_ = file.Close()
+		return nil, nil, fmt.Errorf("%q is a directory: %w", name, os.ErrNotExist)
+	}
+
+	return file, info, nil
+}
+
+// Apply serves the file content, http.ServeContent takes care of ranges and conditional requests
+func (f *fileResult) Apply(ctx context.Context, rw http.ResponseWriter) error {
+	defer f.file.Close()
+
+	rw.Header().Set("Cache-Control", f.cacheControl)
+	rw.Header().Set("X-Content-Type-Options", "nosniff")
+	http.ServeContent(rw, f.request, f.info.Name(), f.info.ModTime(), f.file)
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package assets
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCleanPath(t *testing.T) {
+	for name, want := range map[string]string{
+		"main.js":        "/main.js",
+		"js/main.js":     "/js/main.js",
+		"js//main.js":    "/js/main.js",
+		"../etc/passwd":  "",
+		"js/../../x":     "",
+		".env":           "",
+		"js/.git/config": "",
+		"js\\..\\x":      "",
+		"a\x00b":         "",
+		"":               "",
+	} {
+		got, err := cleanPath(name)
+		if want == "" {
+			assert.Equal(t, ErrInvalidPath, err, name)
+			continue
+		}
+		assert.NoError(t, err, name)
+		assert.Equal(t, want, got, name)
+	}
+}
+
+func TestIsFingerprinted(t *testing.T) {
+	assert.True(t, isFingerprinted("/js/main.3f2a9c1b.js"))
+	assert.True(t, isFingerprinted("/css/app.3f2a9c1b5d.css"))
+	assert.True(t, isFingerprinted("/js/main.3f2a9c1b.js.map"))
+	assert.False(t, isFingerprinted("/js/main.js"))
+	assert.False(t, isFingerprinted("/js/main.min.js"))
+	assert.False(t, isFingerprinted("/js/app-20201231.js"))
+	assert.False(t, isFingerprinted("/js/app.20201231.js"))
+	assert.False(t, isFingerprinted("/js/app-3f2a9c1b.js"))
+	assert.False(t, isFingerprinted("/js/app.3F2A9C1B.js"))
+	assert.False(t, isFingerprinted("/js/app.3f2a9c1b3f2a9c1b3f2a9c1b3f2a9c1b3.js"))
+}
+
+func TestManifest_Parse(t *testing.T) {
+	m := &Manifest{entries: make(map[string]string), files: make(map[string]struct{})}
+	m.once.Do(func() {})
+
+	require.NoError(t, m.parse([]byte(`{"main.js": "/assets/main.abc.js", "src/app.ts": {"file": "app.def.js"}, "broken": 1}`)))
+
+	file, ok := m.Lookup("main.js")
+	assert.True(t, ok)
+	assert.Equal(t, "/assets/main.abc.js", file)
+
+	file, ok = m.Lookup("src/app.ts")
+	assert.True(t, ok)
+	assert.Equal(t, "app.def.js", file)
+
+	_, ok = m.Lookup("broken")
+	assert.False(t, ok)
+
+	assert.True(t, m.Contains("/app.def.js"))
+}
+
+func TestController_Serve(t *testing.T) {
+	dir, err := ioutil.TempDir("", "assets")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "main.3f2a9c1b.js"), []byte("fingerprinted"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "robots.txt"), []byte("plain"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "index.html"), []byte("spa"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "manifest.json"), []byte(`{"main.js": "main.3f2a9c1b.js"}`), 0644))
+
+	manifest := new(Manifest).Inject(flamingo.NullLogger{}, &struct {
+		FileSystem http.FileSystem `inject:"core.assets,optional"`
+		Dir        string          `inject:"config:core.assets.dir"`
+		Manifest   string          `inject:"config:core.assets.manifest"`
+	}{Dir: dir, Manifest: "manifest.json"})
+
+	controller := new(Controller).Inject(new(web.Responder), manifest, &struct {
+		FileSystem      http.FileSystem `inject:"core.assets,optional"`
+		Dir             string          `inject:"config:core.assets.dir"`
+		ImmutableMaxAge float64         `inject:"config:core.assets.immutableMaxAge"`
+		SpaFallback     string          `inject:"config:core.assets.spaFallback"`
+	}{Dir: dir, ImmutableMaxAge: 3600, SpaFallback: "index.html"})
+
+	serve := func(name string, accept string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/assets/"+name, nil)
+		r.Header.Set("Accept", accept)
+		req := web.CreateRequest(r, nil)
+		req.Params["name"] = name
+		recorder := httptest.NewRecorder()
+		ctx := web.ContextWithRequest(context.Background(), req)
+		assert.NoError(t, controller.Serve(ctx, req).Apply(ctx, recorder))
+		return recorder
+	}
+
+	recorder := serve("main.3f2a9c1b.js", "*/*")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "fingerprinted", recorder.Body.String())
+	assert.Equal(t, "public, max-age=3600, immutable", recorder.Header().Get("Cache-Control"))
+
+	recorder = serve("robots.txt", "*/*")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "no-cache", recorder.Header().Get("Cache-Control"))
+
+	recorder = serve("../controller.go", "*/*")
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	recorder = serve("missing.js", "*/*")
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	recorder = serve("some/client/route", "text/html,application/xhtml+xml")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "spa", recorder.Body.String())
+
+	file, ok := manifest.Lookup("main.js")
+	assert.True(t, ok)
+	assert.Equal(t, "main.3f2a9c1b.js", file)
+}
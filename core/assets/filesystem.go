@@ -0,0 +1,45 @@
+package assets
+
+import (
+	"errors"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidPath is returned for asset names which try to escape the asset root or access hidden files
+var ErrInvalidPath = errors.New("invalid asset path")
+
+// fingerprintPattern matches file names like main.3f2a9c1b.js, the hash is captured
+var fingerprintPattern = regexp.MustCompile(`\.([0-9a-f]{8,32})\.[^/]+$`)
+
+// fileSystem returns the bound virtual file system, or the configured directory as a fallback
+func fileSystem(fs http.FileSystem, dir string) http.FileSystem {
+	if fs != nil {
+		return fs
+	}
+	return http.Dir(dir)
+}
+
+// cleanPath validates a requested asset name and returns a rooted, cleaned path
+func cleanPath(name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, "\x00\\") {
+		return "", ErrInvalidPath
+	}
+
+	for _, segment := range strings.Split(name, "/") {
+		if strings.HasPrefix(segment, ".") {
+			return "", ErrInvalidPath
+		}
+	}
+
+	return path.Clean("/" + name), nil
+}
+
+// isFingerprinted checks if a file name contains a content hash.
+// Hashes without letters are not accepted, so dates or versions like app.20201231.js are not cached as immutable.
+func isFingerprinted(name string) bool {
+	match := fingerprintPattern.FindStringSubmatch(path.Base(name))
+	return match != nil && strings.ContainsAny(match[1], "abcdef")
+}
@@ -0,0 +1,106 @@
+package assets
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+)
+
+type (
+	// Manifest resolves logical asset names to their fingerprinted file names, e.g. from a webpack manifest.json
+	Manifest struct {
+		fs       http.FileSystem
+		filename string
+		logger   flamingo.Logger
+
+		once    sync.Once
+		entries map[string]string
+		files   map[string]struct{}
+	}
+
+	viteEntry struct {
+		File string `json:"file"`
+	}
+)
+
+// Inject dependencies
+func (m *Manifest) Inject(logger flamingo.Logger, config *struct {
+	FileSystem http.FileSystem `inject:"core.assets,optional"`
+	Dir        string          `inject:"config:core.assets.dir"`
+	Manifest   string          `inject:"config:core.assets.manifest"`
+}) *Manifest {
+	m.logger = logger.WithField(flamingo.LogKeyModule, "core.assets").WithField(flamingo.LogKeyCategory, "manifest")
+	m.fs = fileSystem(config.FileSystem, config.Dir)
+	m.filename = config.Manifest
+	return m
+}
+
+// load reads the manifest once, a missing manifest results in an empty mapping
+func (m *Manifest) load() {
+	m.entries = make(map[string]string)
+	m.files = make(map[string]struct{})
+
+	if m.filename == "" || m.fs == nil {
+		return
+	}
+
+	f, err := m.fs.Open(path.Clean("/" + m.filename))
+	if err != nil {
+		m.logger.Debug("no asset manifest found: ", err)
+		return
+	}
+	defer f.Close()
+
+	b, err := ioutil.ReadAll(f)
+	if err != nil {
+		m.logger.Error("unable to read asset manifest: ", err)
+		return
+	}
+
+	if err := m.parse(b); err != nil {
+		m.logger.Error("unable to parse asset manifest: ", err)
+	}
+}
+
+// parse supports flat name->file manifests (webpack) as well as name->{file: ...} manifests (vite)
+func (m *Manifest) parse(b []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	for name, value := range raw {
+		var file string
+		if err := json.Unmarshal(value, &file); err != nil {
+			var entry viteEntry
+			if err := json.Unmarshal(value, &entry); err != nil || entry.File == "" {
+				continue
+			}
+			file = entry.File
+		}
+
+		m.entries[name] = file
+		m.files[path.Clean("/"+strings.TrimLeft(file, "/"))] = struct{}{}
+	}
+
+	return nil
+}
+
+// Lookup returns the fingerprinted file for a logical asset name
+func (m *Manifest) Lookup(name string) (string, bool) {
+	m.once.Do(m.load)
+	file, ok := m.entries[name]
+	return file, ok
+}
+
+// Contains checks if the given file is referenced by the manifest
+func (m *Manifest) Contains(file string) bool {
+	m.once.Do(m.load)
+	_, ok := m.files[file]
+	return ok
+}
@@ -0,0 +1,69 @@
+// Package assets serves static frontend assets in production.
+//
+// Assets are served from a directory or from a bound http.FileSystem, content-hash fingerprinted files
+// are sent with long-lived immutable cache headers and a build manifest is used to resolve
+// logical asset names via the `asset` template function.
+package assets
+
+import (
+	"path"
+
+	"flamingo.me/dingo"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/web"
+)
+
+type (
+	// Module for core/assets
+	Module struct {
+		path string
+	}
+
+	routes struct {
+		controller *Controller
+		path       string
+	}
+)
+
+// Inject dependencies
+func (m *Module) Inject(config *struct {
+	Path string `inject:"config:core.assets.path"`
+}) *Module {
+	m.path = config.Path
+	return m
+}
+
+// Configure DI
+func (m *Module) Configure(injector *dingo.Injector) {
+	injector.Bind(new(Manifest)).In(dingo.ChildSingleton)
+	injector.Bind(new(Controller)).In(dingo.ChildSingleton)
+
+	web.BindRoutes(injector, &routes{path: m.path})
+	flamingo.BindTemplateFunc(injector, "asset", new(assetFunc))
+}
+
+// CueConfig schema and defaults
+func (m *Module) CueConfig() string {
+	// language=cue
+	return `
+core: assets: {
+	path: string | *"/assets"
+	dir: string | *"frontend/dist"
+	manifest: string | *"manifest.json"
+	immutableMaxAge: int | *31536000
+	spaFallback: string | *""
+}
+`
+}
+
+// Inject dependencies
+func (r *routes) Inject(controller *Controller) {
+	r.controller = controller
+}
+
+// Routes registers the asset controller
+func (r *routes) Routes(registry *web.RouterRegistry) {
+	registry.HandleGet("core.assets.serve", r.controller.Serve)
+	registry.HandleHead("core.assets.serve", r.controller.Serve)
+	registry.MustRoute(path.Join("/", r.path, "*name"), "core.assets.serve")
+}
@@ -0,0 +1,14 @@
+package assets_test
+
+import (
+	"testing"
+
+	"flamingo.me/flamingo/v3/core/assets"
+	"flamingo.me/flamingo/v3/framework/config"
+)
+
+func TestModule_Configure(t *testing.T) {
+	if err := config.TryModules(nil, new(assets.Module)); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,54 @@
+package assets
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/web"
+)
+
+// assetFunc resolves asset names via the manifest to public URLs
+type assetFunc struct {
+	manifest *Manifest
+	router   web.ReverseRouter
+	path     string
+}
+
+var _ flamingo.TemplateFunc = new(assetFunc)
+
+// Inject dependencies
+func (a *assetFunc) Inject(manifest *Manifest, router web.ReverseRouter, config *struct {
+	Path string `inject:"config:core.assets.path"`
+}) *assetFunc {
+	a.manifest = manifest
+	a.router = router
+	a.path = config.Path
+	return a
+}
+
+// Func returns the asset template function
+func (a *assetFunc) Func(context.Context) interface{} {
+	return func(name string) string {
+		file, ok := a.manifest.Lookup(name)
+		if !ok {
+			file = name
+		}
+
+		if strings.Contains(file, "://") || strings.HasPrefix(file, "//") {
+			return file
+		}
+
+		if !strings.HasPrefix(file, "/") {
+			file = path.Join("/", a.path, file)
+		}
+
+		u, err := a.router.Relative(file, nil)
+		if err != nil {
+			return file
+		}
+
+		return u.String()
+	}
+}
@@ -0,0 +1,65 @@
+package featureflag
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"flamingo.me/flamingo/v3/framework/config"
+)
+
+type (
+	// Endpoint shows the configured flags of all areas on the system endpoint
+	Endpoint struct {
+		root *config.Area
+	}
+
+	endpointFlag struct {
+		Flag
+		Always bool `json:"always"`
+	}
+)
+
+// Inject dependencies
+func (e *Endpoint) Inject(area *config.Area) *Endpoint {
+	e.root = area
+	for e.root != nil && e.root.Parent != nil {
+		e.root = e.root.Parent
+	}
+	return e
+}
+
+// ServeHTTP returns the flags by area as JSON, always marks flags which are on for everyone
+func (e *Endpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if e.root == nil {
+		http.Error(w, "no config area available", http.StatusInternalServerError)
+		return
+	}
+
+	flat, err := e.root.Flat()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	areas := make(map[string]map[string]endpointFlag, len(flat))
+	for name, area := range flat {
+		var cfg config.Map
+		if value, ok := area.Configuration.Get("core.featureflag.flags"); ok {
+			cfg, _ = value.(config.Map)
+		}
+
+		flags, err := parseFlags(cfg)
+		if err != nil {
+			http.Error(w, name+": "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		areas[name] = make(map[string]endpointFlag, len(flags))
+		for flagName, flag := range flags {
+			areas[name][flagName] = endpointFlag{Flag: *flag, Always: flag.always()}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(areas)
+}
@@ -0,0 +1,72 @@
+package featureflag
+
+import (
+	"fmt"
+	"sort"
+
+	"flamingo.me/flamingo/v3/framework/config"
+)
+
+type (
+	// Flag is the configuration of a feature flag
+	Flag struct {
+		// Description documents the flag on the system endpoint
+		Description string `json:"description"`
+		// Enabled switches the feature on for everyone
+		Enabled bool `json:"enabled"`
+		// Rollout is the percentage of sessions which get the feature, the assignment is sticky per session
+		Rollout float64 `json:"rollout"`
+		// Subjects get the feature regardless of the rollout, matched against the subjects of all request identities
+		Subjects []string `json:"subjects"`
+		// Sessions get the feature regardless of the rollout, matched against the session id
+		Sessions []string `json:"sessions"`
+	}
+)
+
+// parseFlags decodes the flags of the area configuration and validates them
+func parseFlags(cfg config.Map) (map[string]*Flag, error) {
+	flags := make(map[string]*Flag)
+	if len(cfg) == 0 {
+		return flags, nil
+	}
+
+	if err := cfg.MapInto(&flags); err != nil {
+		return nil, err
+	}
+
+	for _, name := range flagNames(flags) {
+		flag := flags[name]
+		if flag == nil {
+			flags[name] = new(Flag)
+			continue
+		}
+		if flag.Rollout < 0 || flag.Rollout > 100 {
+			return nil, fmt.Errorf("flag %s: rollout %v is not a percentage between 0 and 100", name, flag.Rollout)
+		}
+	}
+
+	return flags, nil
+}
+
+// always checks if the flag is on without looking at the request
+func (f *Flag) always() bool {
+	return f.Enabled || f.Rollout >= 100
+}
+
+func flagNames(flags map[string]*Flag) []string {
+	names := make([]string, 0, len(flags))
+	for name := range flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+// Package featureflag provides feature flags with percentage rollouts, allow-lists and per-area overrides.
+package featureflag
+
+import (
+	"flamingo.me/dingo"
+	"flamingo.me/flamingo/v3/core/security/application/voter"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/systemendpoint"
+	"flamingo.me/flamingo/v3/framework/systemendpoint/domain"
+)
+
+// Module for core/featureflag
+type Module struct {
+	endpoint string
+}
+
+// Inject dependencies
+func (m *Module) Inject(config *struct {
+	Endpoint string `inject:"config:core.featureflag.endpoint"`
+}) *Module {
+	m.endpoint = config.Endpoint
+	return m
+}
+
+// Configure DI
+func (m *Module) Configure(injector *dingo.Injector) {
+	injector.Bind(new(Service)).In(dingo.ChildSingleton)
+	flamingo.BindTemplateFunc(injector, "featureEnabled", new(FeatureEnabledFunc))
+	injector.BindMulti(new(voter.SecurityVoter)).To(Voter{})
+
+	if m.endpoint != "" {
+		injector.BindMap((*domain.Handler)(nil), m.endpoint).To(&Endpoint{})
+	}
+}
+
+// CueConfig schema and defaults, flags are defined by name:
+//
+//	core: featureflag: flags: newCheckout: {rollout: 10, subjects: ["tester@example.com"]}
+func (m *Module) CueConfig() string {
+	// language=cue
+	return `
+core: featureflag: {
+	endpoint: string | *"/featureflags"
+	flags: [string]: {
+		description: string | *""
+		enabled: bool | *false
+		rollout: number & >=0 & <=100 | *0
+		subjects: [...string]
+		sessions: [...string]
+	}
+}
+`
+}
+
+// Depends on the systemendpoint module
+func (*Module) Depends() []dingo.Module {
+	return []dingo.Module{
+		new(systemendpoint.Module),
+	}
+}
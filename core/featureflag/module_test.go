@@ -0,0 +1,23 @@
+package featureflag_test
+
+import (
+	"testing"
+
+	"flamingo.me/flamingo/v3/core/featureflag"
+	"flamingo.me/flamingo/v3/framework/config"
+)
+
+func TestModule_Configure(t *testing.T) {
+	cfg := config.Map{
+		"core.featureflag.flags": config.Map{
+			"newCheckout": config.Map{
+				"rollout":  10,
+				"subjects": config.Slice{"tester@example.com"},
+			},
+		},
+	}
+
+	if err := config.TryModules(cfg, new(featureflag.Module)); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,147 @@
+package featureflag
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+
+	"flamingo.me/flamingo/v3/core/auth"
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/web"
+)
+
+type (
+	// Service evaluates the feature flags of an area per request
+	Service struct {
+		logger      flamingo.Logger
+		flags       map[string]*Flag
+		identifiers []auth.RequestIdentifier
+		unknown     sync.Map
+		random      func() float64
+	}
+
+	// requestCacheKey of a flag result in the request values, every service caches its own results
+	requestCacheKey struct {
+		service *Service
+		name    string
+	}
+)
+
+// sessionBucketPrefix is the session key prefix of the sticky rollout bucket of a flag
+const sessionBucketPrefix = "core.featureflag.bucket."
+
+// Inject dependencies
+func (s *Service) Inject(
+	logger flamingo.Logger,
+	cfg *struct {
+		Flags config.Map `inject:"config:core.featureflag.flags,optional"`
+	},
+	identifiers *struct {
+		Identifiers []auth.RequestIdentifier `inject:",optional"`
+	},
+) *Service {
+	s.logger = logger.WithField(flamingo.LogKeyModule, "core.featureflag").WithField(flamingo.LogKeyCategory, "service")
+	s.identifiers = identifiers.Identifiers
+	s.random = rand.Float64
+
+	flags, err := parseFlags(cfg.Flags)
+	if err != nil {
+		s.logger.Error("invalid feature flags: ", err)
+		flags = make(map[string]*Flag)
+	}
+	s.flags = flags
+
+	return s
+}
+
+// Enabled checks if the feature is enabled for the request of the context.
+// Without request only flags which are on for everyone are enabled.
+func (s *Service) Enabled(ctx context.Context, name string) bool {
+	return s.EnabledFor(ctx, web.RequestFromContext(ctx), name)
+}
+
+// EnabledFor checks if the feature is enabled for the request, the result of the service is cached for the request
+func (s *Service) EnabledFor(ctx context.Context, r *web.Request, name string) bool {
+	flag, ok := s.flags[name]
+	if !ok {
+		if _, logged := s.unknown.LoadOrStore(name, true); !logged {
+			s.logger.WithContext(ctx).Warn("unknown feature flag ", name)
+		}
+		return false
+	}
+
+	if flag.always() {
+		return true
+	}
+
+	if r == nil {
+		return false
+	}
+
+	key := requestCacheKey{service: s, name: name}
+	if enabled, ok := r.Values.Load(key); ok {
+		return enabled.(bool)
+	}
+
+	enabled := s.evaluate(ctx, r, name, flag)
+	r.Values.Store(key, enabled)
+
+	return enabled
+}
+
+// States evaluates all flags for the request of the context
+func (s *Service) States(ctx context.Context) map[string]bool {
+	states := make(map[string]bool, len(s.flags))
+	for name := range s.flags {
+		states[name] = s.Enabled(ctx, name)
+	}
+	return states
+}
+
+// Flags returns the configured flags of the area
+func (s *Service) Flags() map[string]Flag {
+	flags := make(map[string]Flag, len(s.flags))
+	for name, flag := range s.flags {
+		flags[name] = *flag
+	}
+	return flags
+}
+
+func (s *Service) evaluate(ctx context.Context, r *web.Request, name string, flag *Flag) bool {
+	session := r.Session()
+
+	if id := session.ID(); id != "" && contains(flag.Sessions, id) {
+		return true
+	}
+
+	if len(flag.Subjects) > 0 {
+		for _, identifier := range s.identifiers {
+			if identity, _ := identifier.Identify(ctx, r); identity != nil && contains(flag.Subjects, identity.Subject()) {
+				return true
+			}
+		}
+	}
+
+	if flag.Rollout <= 0 {
+		return false
+	}
+
+	return s.bucket(session, name) < flag.Rollout
+}
+
+// bucket returns the sticky rollout bucket of the session for a flag, a number in [0, 100).
+// Raising the rollout keeps the feature for all sessions which already had it.
+func (s *Service) bucket(session *web.Session, name string) float64 {
+	key := sessionBucketPrefix + name
+	if bucket, ok := session.Load(key); ok {
+		if bucket, ok := bucket.(float64); ok {
+			return bucket
+		}
+	}
+
+	bucket := s.random() * 100
+	session.Store(key, bucket)
+
+	return bucket
+}
@@ -0,0 +1,139 @@
+package featureflag
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"flamingo.me/flamingo/v3/core/auth"
+	"flamingo.me/flamingo/v3/core/security/application"
+	"flamingo.me/flamingo/v3/core/security/application/role"
+	"flamingo.me/flamingo/v3/core/security/application/voter"
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/web"
+)
+
+type (
+	testIdentifier struct {
+		subject string
+	}
+
+	testIdentity struct {
+		subject string
+	}
+)
+
+func (*testIdentifier) Broker() string {
+	return "test"
+}
+
+func (i *testIdentifier) Identify(context.Context, *web.Request) (auth.Identity, error) {
+	return &testIdentity{subject: i.subject}, nil
+}
+
+func (i *testIdentity) Subject() string {
+	return i.subject
+}
+
+func (*testIdentity) Broker() string {
+	return "test"
+}
+
+func testService(t *testing.T, subject string, random float64) *Service {
+	t.Helper()
+
+	s := new(Service).Inject(
+		flamingo.NullLogger{},
+		&struct {
+			Flags config.Map `inject:"config:core.featureflag.flags,optional"`
+		}{Flags: config.Map{
+			"everyone": config.Map{"enabled": true},
+			"nobody":   config.Map{},
+			"rollout":  config.Map{"rollout": 30.0},
+			"testers":  config.Map{"subjects": config.Slice{"tester"}},
+		}},
+		&struct {
+			Identifiers []auth.RequestIdentifier `inject:",optional"`
+		}{Identifiers: []auth.RequestIdentifier{&testIdentifier{subject: subject}}},
+	)
+	s.random = func() float64 { return random }
+
+	return s
+}
+
+func TestService_Enabled(t *testing.T) {
+	s := testService(t, "tester", 0.1)
+
+	assert.True(t, s.Enabled(context.Background(), "everyone"))
+	assert.False(t, s.Enabled(context.Background(), "rollout"), "without request only flags on for everyone")
+	assert.False(t, s.Enabled(context.Background(), "unknown"))
+
+	r := web.CreateRequest(nil, nil)
+	ctx := web.ContextWithRequest(context.Background(), r)
+
+	assert.True(t, s.Enabled(ctx, "everyone"))
+	assert.False(t, s.Enabled(ctx, "nobody"))
+	assert.True(t, s.Enabled(ctx, "rollout"))
+	assert.True(t, s.Enabled(ctx, "testers"))
+	assert.Equal(t, map[string]bool{"everyone": true, "nobody": false, "rollout": true, "testers": true}, s.States(ctx))
+
+	s.identifiers = nil
+	assert.True(t, s.Enabled(ctx, "testers"), "result is cached per request")
+	assert.False(t, s.Enabled(web.ContextWithRequest(context.Background(), web.CreateRequest(nil, nil)), "testers"))
+
+	other := testService(t, "customer", 0.5)
+	assert.False(t, other.Enabled(ctx, "testers"), "the result of another service is not shared")
+}
+
+func TestService_StickyRollout(t *testing.T) {
+	session := web.EmptySession()
+
+	s := testService(t, "", 0.2)
+	assert.True(t, s.EnabledFor(context.Background(), web.CreateRequest(nil, session), "rollout"))
+
+	s.random = func() float64 { return 0.9 }
+	assert.True(t, s.EnabledFor(context.Background(), web.CreateRequest(nil, session), "rollout"), "bucket is stored in the session")
+	assert.False(t, s.EnabledFor(context.Background(), web.CreateRequest(nil, web.EmptySession()), "rollout"))
+}
+
+func TestVoter_Vote(t *testing.T) {
+	v := new(Voter).Inject(testService(t, "tester", 0.9))
+	ctx := web.ContextWithRequest(context.Background(), web.CreateRequest(nil, nil))
+
+	assert.Equal(t, voter.AccessAbstained, v.Vote(nil, "SomePermission", ctx))
+	assert.Equal(t, voter.AccessGranted, v.Vote(nil, PermissionPrefix+"everyone", nil))
+	assert.Equal(t, voter.AccessGranted, v.Vote(nil, PermissionPrefix+"testers", ctx))
+	assert.Equal(t, voter.AccessDenied, v.Vote(nil, PermissionPrefix+"rollout", ctx))
+	assert.Equal(t, voter.AccessDenied, v.Vote(nil, PermissionPrefix+"testers", nil))
+}
+
+func TestVoter_SecurityService(t *testing.T) {
+	roleService := new(role.ServiceImpl)
+	roleService.Inject(nil, &struct {
+		PermissionHierarchy config.Map `inject:"config:core.security.roles.permissionHierarchy"`
+	}{})
+
+	securityService := new(application.SecurityServiceImpl)
+	securityService.Inject(
+		[]voter.SecurityVoter{new(voter.PermissionVoter), new(Voter).Inject(testService(t, "tester", 0.9))},
+		roleService,
+		&struct {
+			VoterStrategy     string `inject:"config:core.security.roles.voters.strategy"`
+			AllowIfAllAbstain bool   `inject:"config:core.security.roles.voters.allowIfAllAbstain"`
+		}{VoterStrategy: application.VoterStrategyAffirmative},
+	)
+
+	session := web.EmptySession()
+	ctx := web.ContextWithRequest(context.Background(), web.CreateRequest(nil, session))
+
+	assert.True(t, securityService.IsGranted(ctx, session, PermissionPrefix+"testers", nil), "the request context is passed to the voter")
+	assert.False(t, securityService.IsGranted(ctx, session, PermissionPrefix+"rollout", nil))
+	assert.False(t, securityService.IsGranted(context.Background(), session, PermissionPrefix+"testers", nil))
+}
+
+func TestParseFlags(t *testing.T) {
+	_, err := parseFlags(config.Map{"broken": config.Map{"rollout": 150.0}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,25 @@
+package featureflag
+
+import (
+	"context"
+)
+
+type (
+	// FeatureEnabledFunc is exported as the featureEnabled template function
+	FeatureEnabledFunc struct {
+		service *Service
+	}
+)
+
+// Inject dependencies
+func (f *FeatureEnabledFunc) Inject(service *Service) *FeatureEnabledFunc {
+	f.service = service
+	return f
+}
+
+// Func returns the featureEnabled func, which checks if a feature is enabled for the current request
+func (f *FeatureEnabledFunc) Func(ctx context.Context) interface{} {
+	return func(name string) bool {
+		return f.service.Enabled(ctx, name)
+	}
+}
@@ -0,0 +1,50 @@
+package featureflag
+
+import (
+	"context"
+	"strings"
+
+	"flamingo.me/flamingo/v3/core/security/application/voter"
+)
+
+type (
+	// Voter grants the permission "featureflag.<name>" if the feature is enabled for the request of the context.
+	// Without request only flags which are on for everyone grant access.
+	Voter struct {
+		service *Service
+	}
+)
+
+// PermissionPrefix is the prefix of feature flag permissions
+const PermissionPrefix = "featureflag."
+
+var _ voter.ContextVoter = new(Voter)
+
+// Inject dependencies
+func (v *Voter) Inject(service *Service) *Voter {
+	v.service = service
+	return v
+}
+
+// Vote on feature flag permissions, a context can be passed as object
+func (v *Voter) Vote(allAssignedPermissions []string, desiredPermission string, forObject interface{}) voter.AccessDecision {
+	ctx, ok := forObject.(context.Context)
+	if !ok {
+		ctx = context.Background()
+	}
+
+	return v.VoteContext(ctx, allAssignedPermissions, desiredPermission, forObject)
+}
+
+// VoteContext on feature flag permissions for the request of the context
+func (v *Voter) VoteContext(ctx context.Context, _ []string, desiredPermission string, _ interface{}) voter.AccessDecision {
+	if !strings.HasPrefix(desiredPermission, PermissionPrefix) {
+		return voter.AccessAbstained
+	}
+
+	if v.service.Enabled(ctx, strings.TrimPrefix(desiredPermission, PermissionPrefix)) {
+		return voter.AccessGranted
+	}
+
+	return voter.AccessDenied
+}
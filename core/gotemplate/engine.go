@@ -25,6 +25,7 @@ type (
 	templateFuncProvider func() map[string]flamingo.TemplateFunc
 
 	engine struct {
+		mu                 sync.Mutex
 		templatesBasePath  string
 		layoutTemplatesDir string
 		debug              bool
@@ -35,14 +36,11 @@ type (
 
 	urlRouter interface {
 		Relative(name string, params map[string]string) (*url.URL, error)
+		RelativeLocalized(locale, name string, params map[string]string) (*url.URL, error)
 		Data(ctx context.Context, handler string, params map[interface{}]interface{}) interface{}
 	}
 )
 
-var (
-	lock = &sync.Mutex{}
-)
-
 // Inject engine dependencies
 func (e *engine) Inject(
 	tplFuncs templateFuncProvider,
@@ -64,23 +62,24 @@ func (e *engine) Render(ctx context.Context, name string, data interface{}) (io.
 	ctx, span := trace.StartSpan(ctx, "gotemplate/Render")
 	defer span.End()
 
-	lock.Lock()
+	e.mu.Lock()
 	if e.debug || e.templates == nil {
 		err := e.loadTemplates(ctx)
 		if err != nil {
-			lock.Unlock()
+			e.mu.Unlock()
 			return nil, err
 		}
 	}
-	lock.Unlock()
+	templates := e.templates
+	e.mu.Unlock()
 
 	_, span = trace.StartSpan(ctx, "gotemplate/Execute")
 	buf := &bytes.Buffer{}
 
-	if _, ok := e.templates[name+".html"]; !ok {
+	if _, ok := templates[name+".html"]; !ok {
 		return nil, errors.New("Could not find the template " + name + ".html")
 	}
-	tpl, err := e.templates[name+".html"].Clone()
+	tpl, err := templates[name+".html"].Clone()
 	if err != nil {
 		return nil, err
 	}
@@ -5,6 +5,7 @@ import (
 	"html/template"
 
 	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/web"
 )
 
 type (
@@ -74,14 +75,14 @@ func (u *urlFunc) Inject(router urlRouter) *urlFunc {
 	return u
 }
 
-// Func as implementation of url method
-func (u *urlFunc) Func(context.Context) interface{} {
+// Func as implementation of url method, localized routes are resolved for the locale of the current request
+func (u *urlFunc) Func(ctx context.Context) interface{} {
 	return func(where string, params ...string) template.URL {
 		var p = make(map[string]string)
 		for i := 0; i < len(params) && len(params)%2 == 0; i += 2 {
 			p[params[i]] = params[i+1]
 		}
-		url, _ := u.router.Relative(where, p)
+		url, _ := u.router.RelativeLocalized(web.LocaleFromContext(ctx), where, p)
 		return template.URL(url.String())
 	}
 }
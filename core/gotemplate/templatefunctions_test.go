@@ -17,6 +17,13 @@ func (m mockRouter) Relative(name string, params map[string]string) (*url.URL, e
 	return url.Parse(fmt.Sprintf("http://name-%v.com/param-amount-%d/", name, len(params)))
 }
 
+func (m mockRouter) RelativeLocalized(locale, name string, params map[string]string) (*url.URL, error) {
+	if locale != "" {
+		return url.Parse(fmt.Sprintf("http://name-%v.com/%v/param-amount-%d/", name, locale, len(params)))
+	}
+	return m.Relative(name, params)
+}
+
 func (m mockRouter) Data(ctx context.Context, handler string, params map[interface{}]interface{}) interface{} {
 	var stringParams []string
 	for key, value := range params {
@@ -0,0 +1,316 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"flamingo.me/flamingo/v3/core/cache"
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/opencensus"
+)
+
+type (
+	// Factory creates the configured clients, every client is created once and shares its connections
+	Factory struct {
+		mu          sync.Mutex
+		configs     map[string]clientConfig
+		clients     map[string]*http.Client
+		transports  []*http.Transport
+		propagation *opencensus.Propagation
+		logger      flamingo.Logger
+	}
+
+	clientConfig struct {
+		Name                  string            `json:"name"`
+		BaseURL               string            `json:"baseURL"`
+		Timeout               string            `json:"timeout"`
+		DialTimeout           string            `json:"dialTimeout"`
+		TLSHandshakeTimeout   string            `json:"tlsHandshakeTimeout"`
+		ResponseHeaderTimeout string            `json:"responseHeaderTimeout"`
+		IdleConnTimeout       string            `json:"idleConnTimeout"`
+		MaxConnsPerHost       int               `json:"maxConnsPerHost"`
+		MaxIdleConnsPerHost   int               `json:"maxIdleConnsPerHost"`
+		Proxy                 string            `json:"proxy"`
+		Headers               map[string]string `json:"headers"`
+		TLS                   struct {
+			InsecureSkipVerify bool   `json:"insecureSkipVerify"`
+			CAFile             string `json:"caFile"`
+			CertFile           string `json:"certFile"`
+			KeyFile            string `json:"keyFile"`
+			ServerName         string `json:"serverName"`
+			MinVersion         string `json:"minVersion"`
+		} `json:"tls"`
+		Retry struct {
+			Attempts    int      `json:"attempts"`
+			Backoff     string   `json:"backoff"`
+			MaxBackoff  string   `json:"maxBackoff"`
+			StatusCodes []int    `json:"statusCodes"`
+			Methods     []string `json:"methods"`
+		} `json:"retry"`
+		Cache struct {
+			Enabled   bool   `json:"enabled"`
+			Lifetime  string `json:"lifetime"`
+			Gracetime string `json:"gracetime"`
+		} `json:"cache"`
+	}
+)
+
+var (
+	// ErrUnknownClient is returned for a client name which is not configured
+	ErrUnknownClient = errors.New("unknown http client")
+
+	tlsVersions = map[string]uint16{
+		"1.0": tls.VersionTLS10,
+		"1.1": tls.VersionTLS11,
+		"1.2": tls.VersionTLS12,
+		"1.3": tls.VersionTLS13,
+	}
+)
+
+// Inject dependencies and the client configuration
+func (f *Factory) Inject(propagation *opencensus.Propagation, logger flamingo.Logger, cfg *struct {
+	Clients config.Slice `inject:"config:core.httpclient.clients,optional"`
+}) *Factory {
+	f.propagation = propagation
+	f.logger = logger.WithField(flamingo.LogKeyModule, "httpclient")
+	f.configs = make(map[string]clientConfig)
+	f.clients = make(map[string]*http.Client)
+
+	if cfg == nil {
+		return f
+	}
+
+	for _, client := range cfg.Clients {
+		clientMap, ok := client.(config.Map)
+		if !ok {
+			panic(fmt.Sprintf("httpclient: invalid client config %v", client))
+		}
+
+		c := defaultClientConfig()
+		if err := clientMap.MapInto(&c); err != nil {
+			panic(err)
+		}
+		f.configs[c.Name] = c
+	}
+
+	return f
+}
+
+// defaultClientConfig is used if the module's cue defaults are not applied
+func defaultClientConfig() clientConfig {
+	c := clientConfig{
+		Timeout:             "30s",
+		DialTimeout:         "5s",
+		TLSHandshakeTimeout: "10s",
+		IdleConnTimeout:     "90s",
+		MaxIdleConnsPerHost: 10,
+	}
+	c.TLS.MinVersion = "1.2"
+	c.Retry.Backoff = "100ms"
+	c.Retry.MaxBackoff = "2s"
+	c.Retry.StatusCodes = []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+	c.Retry.Methods = []string{http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete}
+	c.Cache.Lifetime = "30s"
+	c.Cache.Gracetime = "10m"
+
+	return c
+}
+
+// Client returns the configured client, ErrUnknownClient if there is no client with the name
+func (f *Factory) Client(name string) (*http.Client, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if client, ok := f.clients[name]; ok {
+		return client, nil
+	}
+
+	cfg, ok := f.configs[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownClient, name)
+	}
+
+	client, base, err := f.build(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("http client %q: %w", name, err)
+	}
+	f.clients[name] = client
+	f.transports = append(f.transports, base)
+
+	return client, nil
+}
+
+// build the client, the transports are chained from the outermost to the innermost:
+// defaults, cache, retries, metrics, tracing and the connection pool
+func (f *Factory) build(cfg clientConfig) (*http.Client, *http.Transport, error) {
+	base, err := baseTransport(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	timeout, err := duration(cfg.Timeout)
+	if err != nil {
+		return nil, nil, fmt.Errorf("timeout: %w", err)
+	}
+
+	var transport http.RoundTripper = f.propagation.ClientTransport(base)
+	transport = &metricsTransport{next: transport, client: cfg.Name}
+
+	if cfg.Retry.Attempts > 0 {
+		transport, err = newRetryTransport(transport, cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if cfg.Cache.Enabled {
+		transport, err = newCacheTransport(transport, cfg, new(cache.HTTPFrontend).Inject(cache.NewInMemoryCache(), f.logger.WithField(flamingo.LogKeyCategory, cfg.Name)))
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	transport, err = newDefaultsTransport(transport, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &http.Client{Transport: transport, Timeout: timeout}, base, nil
+}
+
+// baseTransport with the connection pool of a client
+func baseTransport(cfg clientConfig) (*http.Transport, error) {
+	dialTimeout, err := duration(cfg.DialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("dialTimeout: %w", err)
+	}
+	tlsHandshakeTimeout, err := duration(cfg.TLSHandshakeTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("tlsHandshakeTimeout: %w", err)
+	}
+	responseHeaderTimeout, err := duration(cfg.ResponseHeaderTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("responseHeaderTimeout: %w", err)
+	}
+	idleConnTimeout, err := duration(cfg.IdleConnTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("idleConnTimeout: %w", err)
+	}
+
+	proxy, err := proxyFunc(cfg.Proxy)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := clientTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Transport{
+		Proxy: proxy,
+		DialContext: (&net.Dialer{
+			Timeout:   dialTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSClientConfig:       tlsConfig,
+		TLSHandshakeTimeout:   tlsHandshakeTimeout,
+		ResponseHeaderTimeout: responseHeaderTimeout,
+		IdleConnTimeout:       idleConnTimeout,
+		MaxConnsPerHost:       cfg.MaxConnsPerHost,
+		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
+		MaxIdleConns:          100,
+		ExpectContinueTimeout: time.Second,
+		ForceAttemptHTTP2:     true,
+	}, nil
+}
+
+// proxyFunc uses the environment by default, "none" disables proxies
+func proxyFunc(proxy string) (func(*http.Request) (*url.URL, error), error) {
+	switch proxy {
+	case "":
+		return http.ProxyFromEnvironment, nil
+	case "none":
+		return nil, nil
+	}
+
+	proxyURL, err := url.Parse(proxy)
+	if err != nil {
+		return nil, fmt.Errorf("proxy: %w", err)
+	}
+
+	return http.ProxyURL(proxyURL), nil
+}
+
+func clientTLSConfig(cfg clientConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: cfg.TLS.InsecureSkipVerify,
+		ServerName:         cfg.TLS.ServerName,
+	}
+
+	if cfg.TLS.MinVersion != "" {
+		version, ok := tlsVersions[cfg.TLS.MinVersion]
+		if !ok {
+			return nil, fmt.Errorf("tls: unknown minVersion %q", cfg.TLS.MinVersion)
+		}
+		tlsConfig.MinVersion = version
+	}
+
+	if cfg.TLS.CAFile != "" {
+		pem, err := ioutil.ReadFile(cfg.TLS.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("tls: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("tls: no certificates in %q", cfg.TLS.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.TLS.CertFile != "" || cfg.TLS.KeyFile != "" {
+		certificate, err := tls.LoadX509KeyPair(cfg.TLS.CertFile, cfg.TLS.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("tls: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return tlsConfig, nil
+}
+
+// duration parses a configured duration, "" is no duration
+func duration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	return time.ParseDuration(s)
+}
+
+// Notify closes the idle connections of the clients on shutdown
+func (f *Factory) Notify(_ context.Context, event flamingo.Event) {
+	if _, ok := event.(*flamingo.ShutdownEvent); !ok {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, transport := range f.transports {
+		transport.CloseIdleConnections()
+	}
+}
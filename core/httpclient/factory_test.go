@@ -0,0 +1,77 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+)
+
+func TestFactory_Client(t *testing.T) {
+	factory := new(Factory).Inject(nil, flamingo.NullLogger{}, &struct {
+		Clients config.Slice `inject:"config:core.httpclient.clients,optional"`
+	}{
+		Clients: config.Slice{
+			config.Map{
+				"name":                "products",
+				"timeout":             "2s",
+				"maxConnsPerHost":     5.0,
+				"maxIdleConnsPerHost": 2.0,
+				"proxy":               "none",
+				"tls":                 config.Map{"minVersion": "1.3", "serverName": "products.local"},
+			},
+			config.Map{"name": "invalid timeout", "timeout": "soon"},
+			config.Map{"name": "invalid tls", "tls": config.Map{"minVersion": "0.9"}},
+			config.Map{"name": "missing ca", "tls": config.Map{"caFile": "testdata/missing.pem"}},
+		},
+	})
+
+	client, err := factory.Client("products")
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Second, client.Timeout)
+
+	same, err := factory.Client("products")
+	require.NoError(t, err)
+	assert.True(t, client == same, "clients are created once")
+
+	require.Len(t, factory.transports, 1)
+	transport := factory.transports[0]
+	assert.Equal(t, 5, transport.MaxConnsPerHost)
+	assert.Equal(t, 2, transport.MaxIdleConnsPerHost)
+	assert.Nil(t, transport.Proxy)
+	assert.Equal(t, uint16(tls.VersionTLS13), transport.TLSClientConfig.MinVersion)
+	assert.Equal(t, "products.local", transport.TLSClientConfig.ServerName)
+
+	_, err = factory.Client("unknown")
+	assert.True(t, errors.Is(err, ErrUnknownClient))
+
+	for _, name := range []string{"invalid timeout", "invalid tls", "missing ca"} {
+		_, err = factory.Client(name)
+		assert.Error(t, err, name)
+	}
+
+	factory.Notify(context.Background(), new(flamingo.ShutdownEvent))
+}
+
+func TestProxyFunc(t *testing.T) {
+	proxy, err := proxyFunc("")
+	require.NoError(t, err)
+	assert.NotNil(t, proxy)
+
+	proxy, err = proxyFunc("http://proxy.local:3128")
+	require.NoError(t, err)
+	proxyURL, err := proxy(&http.Request{})
+	require.NoError(t, err)
+	assert.Equal(t, "proxy.local:3128", proxyURL.Host)
+
+	_, err = proxyFunc("://")
+	assert.Error(t, err)
+}
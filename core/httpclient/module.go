@@ -0,0 +1,100 @@
+package httpclient
+
+import (
+	"net/http"
+
+	"flamingo.me/dingo"
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+)
+
+// Module binds the Factory and the configured clients, annotated with "httpclient.<name>"
+type Module struct {
+	names []string
+}
+
+// Inject configuration
+func (m *Module) Inject(cfg *struct {
+	Clients config.Slice `inject:"config:core.httpclient.clients,optional"`
+}) *Module {
+	if cfg == nil {
+		return m
+	}
+
+	var clients []struct {
+		Name string `json:"name"`
+	}
+	if err := cfg.Clients.MapInto(&clients); err != nil {
+		panic(err)
+	}
+	for _, client := range clients {
+		m.names = append(m.names, client.Name)
+	}
+
+	return m
+}
+
+// Configure DI
+func (m *Module) Configure(injector *dingo.Injector) {
+	injector.Bind(new(Factory)).In(dingo.Singleton)
+	flamingo.BindEventSubscriber(injector).ToProvider(func(f *Factory) *Factory { return f })
+
+	for _, name := range m.names {
+		name := name
+		injector.Bind(new(http.Client)).AnnotatedWith(Annotation(name)).ToProvider(func(f *Factory) *http.Client {
+			client, err := f.Client(name)
+			if err != nil {
+				panic(err)
+			}
+
+			return client
+		})
+	}
+}
+
+// Annotation of the binding of a configured client, e.g. `inject:"httpclient.products"`
+func Annotation(name string) string {
+	return "httpclient." + name
+}
+
+// CueConfig schema and defaults
+func (m *Module) CueConfig() string {
+	// language=cue
+	return `
+core: httpclient: {
+	clients: [...{
+		name: string
+		baseURL: string | *""
+		timeout: string | *"30s"
+		dialTimeout: string | *"5s"
+		tlsHandshakeTimeout: string | *"10s"
+		responseHeaderTimeout: string | *""
+		idleConnTimeout: string | *"90s"
+		maxConnsPerHost: int | *0
+		maxIdleConnsPerHost: int | *10
+		proxy: string | *""
+		headers: {[string]: string}
+		tls: {
+			insecureSkipVerify: bool | *false
+			caFile: string | *""
+			certFile: string | *""
+			keyFile: string | *""
+			serverName: string | *""
+			minVersion: *"1.2" | "1.0" | "1.1" | "1.3"
+		}
+		retry: {
+			attempts: int | *0
+			backoff: string | *"100ms"
+			maxBackoff: string | *"2s"
+			statusCodes: [...int] | *[502, 503, 504]
+			methods: [...string] | *["GET", "HEAD", "OPTIONS", "PUT", "DELETE"]
+		}
+		cache: {
+			enabled: bool | *false
+			lifetime: string | *"30s"
+			gracetime: string | *"10m"
+		}
+	}]
+}
+`
+}
@@ -0,0 +1,18 @@
+package httpclient_test
+
+import (
+	"testing"
+
+	"flamingo.me/flamingo/v3/core/httpclient"
+	"flamingo.me/flamingo/v3/framework/config"
+)
+
+func TestModule_Configure(t *testing.T) {
+	if err := config.TryModules(config.Map{
+		"core.httpclient.clients": config.Slice{
+			config.Map{"name": "products", "baseURL": "https://products.local/api/"},
+		},
+	}, new(httpclient.Module)); err != nil {
+		t.Error(err)
+	}
+}
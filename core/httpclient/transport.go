@@ -0,0 +1,329 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"flamingo.me/flamingo/v3/core/cache"
+	"flamingo.me/flamingo/v3/framework/opencensus"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+type (
+	// defaultsTransport resolves relative URLs against the base URL and adds the default headers
+	defaultsTransport struct {
+		next    http.RoundTripper
+		baseURL *url.URL
+		headers http.Header
+	}
+
+	// cacheTransport serves GET requests from a cache.HTTPFrontend
+	cacheTransport struct {
+		next      http.RoundTripper
+		client    string
+		frontend  *cache.HTTPFrontend
+		lifetime  time.Duration
+		gracetime time.Duration
+	}
+
+	// detachedContext has the values of a context, but neither its deadline nor its cancellation
+	detachedContext struct {
+		values context.Context
+	}
+
+	// retryTransport retries idempotent requests with exponential backoff
+	retryTransport struct {
+		next       http.RoundTripper
+		client     string
+		attempts   int
+		backoff    time.Duration
+		maxBackoff time.Duration
+		statuses   map[int]struct{}
+		methods    map[string]struct{}
+	}
+
+	// metricsTransport records the duration until the response headers are received
+	metricsTransport struct {
+		next   http.RoundTripper
+		client string
+	}
+)
+
+// statusError is the status_code tag of requests without response
+const statusError = "error"
+
+var (
+	// durationMeasure records the duration of all requests, the request count is derived from it
+	durationMeasure = stats.Float64("flamingo/httpclient/duration", "Duration of outgoing requests", stats.UnitMilliseconds)
+	// retriesMeasure counts the retried requests
+	retriesMeasure = stats.Int64("flamingo/httpclient/retries", "Count of retried outgoing requests", stats.UnitDimensionless)
+
+	keyClient, _     = tag.NewKey("client")
+	keyHost, _       = tag.NewKey("host")
+	keyStatusCode, _ = tag.NewKey("status_code")
+)
+
+func init() {
+	if err := opencensus.View("flamingo/httpclient/requests", durationMeasure, view.Count(), keyClient, keyHost, keyStatusCode); err != nil {
+		panic(err)
+	}
+	if err := opencensus.View("flamingo/httpclient/duration", durationMeasure, view.Distribution(5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000), keyClient, keyHost, keyStatusCode); err != nil {
+		panic(err)
+	}
+	if err := opencensus.View("flamingo/httpclient/retries", retriesMeasure, view.Count(), keyClient, keyHost, keyStatusCode); err != nil {
+		panic(err)
+	}
+}
+
+func newDefaultsTransport(next http.RoundTripper, cfg clientConfig) (*defaultsTransport, error) {
+	t := &defaultsTransport{next: next, headers: make(http.Header)}
+	for k, v := range cfg.Headers {
+		t.headers.Set(k, v)
+	}
+
+	if cfg.BaseURL != "" {
+		baseURL, err := url.Parse(cfg.BaseURL)
+		if err != nil {
+			return nil, fmt.Errorf("baseURL: %w", err)
+		}
+		t.baseURL = baseURL
+	}
+
+	return t, nil
+}
+
+// RoundTrip a copy of the request, headers of the request take precedence over the default headers
+func (t *defaultsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if (t.baseURL == nil || req.URL.Host != "") && len(t.headers) == 0 {
+		return t.next.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	if t.baseURL != nil && req.URL.Host == "" {
+		req.URL = t.baseURL.ResolveReference(req.URL)
+		req.Host = ""
+	}
+	for k, v := range t.headers {
+		if _, ok := req.Header[k]; !ok {
+			req.Header[k] = v
+		}
+	}
+
+	return t.next.RoundTrip(req)
+}
+
+func newCacheTransport(next http.RoundTripper, cfg clientConfig, frontend *cache.HTTPFrontend) (*cacheTransport, error) {
+	lifetime, err := duration(cfg.Cache.Lifetime)
+	if err != nil {
+		return nil, fmt.Errorf("cache lifetime: %w", err)
+	}
+	gracetime, err := duration(cfg.Cache.Gracetime)
+	if err != nil {
+		return nil, fmt.Errorf("cache gracetime: %w", err)
+	}
+
+	return &cacheTransport{
+		next:      next,
+		client:    cfg.Name,
+		frontend:  frontend,
+		lifetime:  lifetime,
+		gracetime: gracetime,
+	}, nil
+}
+
+// RoundTrip GET requests through the cache, server errors are returned but not reused.
+// The request is loaded with the values of its context, e.g. the trace parent and the baggage, and is cancelled with
+// the request until it is answered, so a refresh in the background during the gracetime is not cancelled.
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("Cache-Control") == "no-cache" {
+		return t.next.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithCancel(detachedContext{values: req.Context()})
+	answered := make(chan struct{})
+	defer close(answered)
+	go func() {
+		select {
+		case <-req.Context().Done():
+			cancel()
+		case <-answered:
+		}
+	}()
+
+	return t.frontend.Get(req.Context(), t.key(req), func(context.Context) (*http.Response, *cache.Meta, error) {
+		response, err := t.next.RoundTrip(req.WithContext(ctx))
+		if err != nil {
+			return nil, new(cache.Meta), err
+		}
+		if response.StatusCode >= http.StatusInternalServerError {
+			return response, new(cache.Meta), nil
+		}
+
+		return response, &cache.Meta{Lifetime: t.lifetime, Gracetime: t.gracetime}, nil
+	})
+}
+
+// key of a request, requests with different headers, e.g. an Authorization, are cached separately
+func (t *cacheTransport) key(req *http.Request) string {
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	hash := sha256.New()
+	for _, name := range names {
+		for _, value := range req.Header[name] {
+			_, _ = fmt.Fprintf(hash, "%s: %s\n", name, value)
+		}
+	}
+
+	return t.client + " " + req.URL.String() + " " + hex.EncodeToString(hash.Sum(nil))
+}
+
+// Deadline is never set
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+
+// Done is never closed
+func (detachedContext) Done() <-chan struct{} { return nil }
+
+// Err is always nil
+func (detachedContext) Err() error { return nil }
+
+// Value of the detached context
+func (c detachedContext) Value(key interface{}) interface{} { return c.values.Value(key) }
+
+func newRetryTransport(next http.RoundTripper, cfg clientConfig) (*retryTransport, error) {
+	backoff, err := duration(cfg.Retry.Backoff)
+	if err != nil {
+		return nil, fmt.Errorf("retry backoff: %w", err)
+	}
+	maxBackoff, err := duration(cfg.Retry.MaxBackoff)
+	if err != nil {
+		return nil, fmt.Errorf("retry maxBackoff: %w", err)
+	}
+
+	t := &retryTransport{
+		next:       next,
+		client:     cfg.Name,
+		attempts:   cfg.Retry.Attempts,
+		backoff:    backoff,
+		maxBackoff: maxBackoff,
+		statuses:   make(map[int]struct{}, len(cfg.Retry.StatusCodes)),
+		methods:    make(map[string]struct{}, len(cfg.Retry.Methods)),
+	}
+	for _, status := range cfg.Retry.StatusCodes {
+		t.statuses[status] = struct{}{}
+	}
+	for _, method := range cfg.Retry.Methods {
+		t.methods[method] = struct{}{}
+	}
+
+	return t, nil
+}
+
+// RoundTrip retries failed requests, requests with a body are only retried if it can be read again
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if _, ok := t.methods[req.Method]; !ok {
+		return t.next.RoundTrip(req)
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return t.next.RoundTrip(req)
+	}
+
+	attempt := req
+	for retry := 0; ; retry++ {
+		response, err := t.next.RoundTrip(attempt)
+		if retry >= t.attempts || !t.retryable(response, err) || req.Context().Err() != nil {
+			return response, err
+		}
+
+		wait := t.wait(retry, response)
+		status := statusError
+		if response != nil {
+			status = strconv.Itoa(response.StatusCode)
+			_, _ = io.Copy(ioutil.Discard, response.Body)
+			_ = response.Body.Close()
+		}
+		ctx, _ := tag.New(req.Context(), tag.Upsert(keyClient, t.client), tag.Upsert(keyHost, req.URL.Host), tag.Upsert(keyStatusCode, status))
+		stats.Record(ctx, retriesMeasure.M(1))
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+
+		attempt = req.Clone(req.Context())
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attempt.Body = body
+		}
+	}
+}
+
+func (t *retryTransport) retryable(response *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	_, ok := t.statuses[response.StatusCode]
+
+	return ok
+}
+
+// wait before the next attempt: the Retry-After of the response, or the exponential backoff with jitter,
+// both are limited by the maximum backoff
+func (t *retryTransport) wait(retry int, response *http.Response) time.Duration {
+	if response != nil {
+		if seconds, err := strconv.Atoi(response.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			return t.limit(time.Duration(seconds) * time.Second)
+		}
+	}
+
+	backoff := t.limit(t.backoff << uint(retry))
+	if backoff <= 0 {
+		return 0
+	}
+
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
+func (t *retryTransport) limit(d time.Duration) time.Duration {
+	if t.maxBackoff > 0 && (d > t.maxBackoff || d < 0) {
+		return t.maxBackoff
+	}
+
+	return d
+}
+
+// RoundTrip the request and record the duration by client, host and status code
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	response, err := t.next.RoundTrip(req)
+
+	status := statusError
+	if err == nil {
+		status = strconv.Itoa(response.StatusCode)
+	}
+	ctx, _ := tag.New(req.Context(), tag.Upsert(keyClient, t.client), tag.Upsert(keyHost, req.URL.Host), tag.Upsert(keyStatusCode, status))
+	stats.Record(ctx, durationMeasure.M(float64(time.Since(start))/float64(time.Millisecond)))
+
+	return response, err
+}
@@ -0,0 +1,246 @@
+package httpclient
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"go.opencensus.io/trace"
+
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/opencensus"
+)
+
+func testClient(t *testing.T, clientConfig config.Map) *http.Client {
+	t.Helper()
+
+	factory := new(Factory).Inject(nil, flamingo.NullLogger{}, &struct {
+		Clients config.Slice `inject:"config:core.httpclient.clients,optional"`
+	}{
+		Clients: config.Slice{clientConfig},
+	})
+
+	client, err := factory.Client(clientConfig["name"].(string))
+	require.NoError(t, err)
+
+	return client
+}
+
+func body(t *testing.T, response *http.Response) string {
+	t.Helper()
+
+	defer response.Body.Close()
+	b, err := ioutil.ReadAll(response.Body)
+	require.NoError(t, err)
+
+	return string(b)
+}
+
+func TestDefaultsTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.Path + " " + r.Header.Get("X-Api-Key") + " " + r.Header.Get("Accept")))
+	}))
+	defer server.Close()
+
+	client := testClient(t, config.Map{
+		"name":    "defaults",
+		"baseURL": server.URL + "/api/",
+		"headers": config.Map{"x-api-key": "secret", "Accept": "application/json"},
+	})
+
+	request, err := http.NewRequest(http.MethodGet, "products/1", nil)
+	require.NoError(t, err)
+	request.Header.Set("Accept", "text/plain")
+
+	response, err := client.Do(request)
+	require.NoError(t, err)
+	assert.Equal(t, "/api/products/1 secret text/plain", body(t, response))
+	assert.Equal(t, "products/1", request.URL.String(), "the request is not modified")
+
+	response, err = client.Get(server.URL + "/other")
+	require.NoError(t, err)
+	assert.Equal(t, "/other secret application/json", body(t, response), "absolute URLs are not resolved")
+}
+
+func TestRetryTransport(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write(b)
+	}))
+	defer server.Close()
+
+	client := testClient(t, config.Map{
+		"name":  "retry",
+		"retry": config.Map{"attempts": 2.0},
+	})
+
+	request, err := http.NewRequest(http.MethodPut, server.URL, strings.NewReader("payload"))
+	require.NoError(t, err)
+	response, err := client.Do(request)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "payload", body(t, response), "the body is sent again")
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	atomic.StoreInt32(&calls, 0)
+	response, err = client.Post(server.URL, "text/plain", strings.NewReader("payload"))
+	require.NoError(t, err)
+	body(t, response)
+	assert.Equal(t, http.StatusServiceUnavailable, response.StatusCode, "POST is not retried")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	rows, err := view.RetrieveData("flamingo/httpclient/retries")
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, int64(2), rows[0].Data.(*view.CountData).Value)
+}
+
+func TestRetryTransport_Wait(t *testing.T) {
+	transport := &retryTransport{backoff: 100 * time.Millisecond, maxBackoff: 300 * time.Millisecond}
+
+	for retry, max := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond} {
+		wait := transport.wait(retry, nil)
+		assert.True(t, wait >= max/2 && wait <= max, "%d: %s is not in [%s, %s]", retry, wait, max/2, max)
+	}
+
+	response := &http.Response{Header: http.Header{"Retry-After": []string{"120"}}}
+	assert.Equal(t, 300*time.Millisecond, transport.wait(0, response), "Retry-After is limited")
+}
+
+func TestCacheTransport(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := atomic.AddInt32(&calls, 1)
+		if r.URL.Path == "/failing" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		_, _ = w.Write([]byte(r.Header.Get("Authorization") + " " + string('0'+call)))
+	}))
+	defer server.Close()
+
+	client := testClient(t, config.Map{
+		"name":    "cached",
+		"baseURL": server.URL,
+		"cache":   config.Map{"enabled": true},
+	})
+
+	get := func(path, authorization string) string {
+		request, err := http.NewRequest(http.MethodGet, path, nil)
+		require.NoError(t, err)
+		request.Header.Set("Authorization", authorization)
+		response, err := client.Do(request)
+		require.NoError(t, err)
+
+		return body(t, response)
+	}
+
+	assert.Equal(t, "a 1", get("/", "a"))
+	assert.Equal(t, "a 1", get("/", "a"))
+	assert.Equal(t, "b 2", get("/", "b"), "other headers are cached separately")
+
+	assert.Equal(t, " 3", get("/failing", ""))
+	assert.Equal(t, " 4", get("/failing", ""), "server errors are not reused")
+
+	response, err := client.Post(server.URL, "text/plain", nil)
+	require.NoError(t, err)
+	assert.Equal(t, " 5", body(t, response), "only GET requests are cached")
+}
+
+func TestCacheTransport_Context(t *testing.T) {
+	headers := make(chan http.Header, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			<-r.Context().Done()
+			return
+		}
+		headers <- r.Header
+	}))
+	defer server.Close()
+
+	propagation := new(opencensus.Propagation).Inject(&struct {
+		Format         string `inject:"config:flamingo.opencensus.tracing.propagation,optional"`
+		Baggage        bool   `inject:"config:flamingo.opencensus.tracing.baggage,optional"`
+		PublicEndpoint bool   `inject:"config:flamingo.opencensus.tracing.publicEndpoint,optional"`
+	}{Format: opencensus.PropagationW3C})
+	factory := new(Factory).Inject(propagation, flamingo.NullLogger{}, &struct {
+		Clients config.Slice `inject:"config:core.httpclient.clients,optional"`
+	}{
+		Clients: config.Slice{config.Map{"name": "traced", "baseURL": server.URL, "cache": config.Map{"enabled": true}}},
+	})
+	client, err := factory.Client("traced")
+	require.NoError(t, err)
+
+	t.Run("trace parent", func(t *testing.T) {
+		ctx, span := trace.StartSpan(context.Background(), "test", trace.WithSampler(trace.AlwaysSample()))
+		defer span.End()
+
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, "/traced", nil)
+		require.NoError(t, err)
+		response, err := client.Do(request)
+		require.NoError(t, err)
+		body(t, response)
+
+		traceparent := (<-headers).Get("traceparent")
+		assert.Contains(t, traceparent, span.SpanContext().TraceID.String(), "a cache miss continues the trace of the request")
+	})
+
+	t.Run("cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, "/slow", nil)
+		require.NoError(t, err)
+		_, err = client.Do(request)
+		assert.Error(t, err, "a cache miss is cancelled with the request")
+	})
+}
+
+func TestMetricsTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := testClient(t, config.Map{"name": "metrics"})
+	response, err := client.Get(server.URL)
+	require.NoError(t, err)
+	body(t, response)
+
+	_, err = client.Get("http://127.0.0.1:1")
+	require.Error(t, err)
+
+	rows, err := view.RetrieveData("flamingo/httpclient/requests")
+	require.NoError(t, err)
+
+	counts := make(map[string]int64)
+	for _, row := range rows {
+		values := make(map[tag.Key]string)
+		for _, t := range row.Tags {
+			values[t.Key] = t.Value
+		}
+		if values[keyClient] == "metrics" {
+			counts[values[keyHost]+" "+values[keyStatusCode]] = row.Data.(*view.CountData).Value
+		}
+	}
+
+	assert.Equal(t, map[string]int64{
+		strings.TrimPrefix(server.URL, "http://") + " 404": 1,
+		"127.0.0.1:1 error": 1,
+	}, counts)
+}
@@ -0,0 +1,98 @@
+package jobqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Cmd inspects the jobs of the store and replays dead letters
+func Cmd(queue *Queue) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "jobqueue",
+		Short: "Inspect the job queue and replay failed jobs",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list [pending|running|dead]",
+		Short: "List the jobs in a state, by default the dead letters",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			state := StateDead
+			if len(args) == 1 {
+				state = State(args[0])
+			}
+			switch state {
+			case StatePending, StateRunning, StateDead:
+			default:
+				return fmt.Errorf("unknown state %q", state)
+			}
+
+			jobs, err := queue.Store().List(context.Background(), state)
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tTYPE\tATTEMPTS\tRUN AT\tLAST ERROR")
+			for _, job := range jobs {
+				fmt.Fprintf(w, "%s\t%s\t%d/%d\t%s\t%s\n", job.ID, job.Type, job.Attempts, job.MaxAttempts, job.RunAt.Format(time.RFC3339), job.LastError)
+			}
+			return w.Flush()
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "show <id>",
+		Short: "Show a job with its payload",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			job, err := queue.Store().Get(context.Background(), args[0])
+			if err != nil {
+				return err
+			}
+
+			encoder := json.NewEncoder(cmd.OutOrStdout())
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(job)
+		},
+	})
+
+	var all bool
+	replay := &cobra.Command{
+		Use:   "replay [id...]",
+		Short: "Replay dead letters, they are processed by the running instances",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if all {
+				jobs, err := queue.Store().List(context.Background(), StateDead)
+				if err != nil {
+					return err
+				}
+				args = args[:0]
+				for _, job := range jobs {
+					args = append(args, job.ID)
+				}
+			}
+			if len(args) == 0 {
+				return errors.New("no jobs to replay, pass job ids or --all")
+			}
+
+			for _, id := range args {
+				if err := queue.Replay(context.Background(), id); err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), "replayed", id)
+			}
+			return nil
+		},
+	}
+	replay.Flags().BoolVar(&all, "all", false, "replay all dead letters")
+	cmd.AddCommand(replay)
+
+	return cmd
+}
@@ -0,0 +1,288 @@
+package jobqueue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileStore persists the jobs as JSON files, in one directory per state.
+// Jobs are claimed by moving their file, so all processes of a host can share the directory.
+type FileStore struct {
+	dir  string
+	once sync.Once
+	err  error
+}
+
+var (
+	_ Store = new(FileStore)
+
+	validID = regexp.MustCompile(`^[a-zA-Z0-9-]+$`)
+)
+
+// NewFileStore returns a FileStore operating in the given directory
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+// init creates the directories of the states
+func (s *FileStore) init() error {
+	s.once.Do(func() {
+		for _, state := range []State{StatePending, StateRunning, StateDead} {
+			if err := os.MkdirAll(filepath.Join(s.dir, string(state)), 0755); err != nil {
+				s.err = fmt.Errorf("jobqueue file store: %w", err)
+				return
+			}
+		}
+	})
+	return s.err
+}
+
+// Add a job
+func (s *FileStore) Add(_ context.Context, job *Job) error {
+	if err := s.init(); err != nil {
+		return err
+	}
+	if !validID.MatchString(job.ID) {
+		return fmt.Errorf("invalid job id %q", job.ID)
+	}
+
+	return s.write(s.path(job), job)
+}
+
+// Claim the job which is due first, pending files are named by their due time
+func (s *FileStore) Claim(_ context.Context, now time.Time) (*Job, error) {
+	if err := s.init(); err != nil {
+		return nil, err
+	}
+
+	names, err := s.names(StatePending)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		parts := strings.SplitN(strings.TrimSuffix(name, ".json"), "_", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		due, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		if due > now.UnixNano() {
+			break
+		}
+
+		running := filepath.Join(s.dir, string(StateRunning), parts[1]+".json")
+		if err := os.Rename(filepath.Join(s.dir, string(StatePending), name), running); err != nil {
+			if os.IsNotExist(err) {
+				// claimed by another worker
+				continue
+			}
+			return nil, err
+		}
+
+		job, err := s.read(running)
+		if err != nil {
+			return nil, err
+		}
+		job.State = StateRunning
+		job.ClaimedAt = now
+
+		return job, s.write(running, job)
+	}
+
+	return nil, nil
+}
+
+// Update a job, its file is moved to the directory of its state
+func (s *FileStore) Update(_ context.Context, job *Job) error {
+	if err := s.init(); err != nil {
+		return err
+	}
+
+	previous, err := s.locate(job.ID)
+	if err != nil {
+		return err
+	}
+
+	path := s.path(job)
+	if err := s.write(path, job); err != nil {
+		return err
+	}
+	for _, p := range previous {
+		if p != path {
+			if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Delete a job
+func (s *FileStore) Delete(_ context.Context, id string) error {
+	if err := s.init(); err != nil {
+		return err
+	}
+
+	paths, err := s.locate(id)
+	if err == ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, p := range paths {
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Get a job
+func (s *FileStore) Get(_ context.Context, id string) (*Job, error) {
+	if err := s.init(); err != nil {
+		return nil, err
+	}
+
+	paths, err := s.locate(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.read(paths[0])
+}
+
+// List the jobs in a state
+func (s *FileStore) List(_ context.Context, state State) ([]*Job, error) {
+	if err := s.init(); err != nil {
+		return nil, err
+	}
+
+	names, err := s.names(state)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]*Job, 0, len(names))
+	for _, name := range names {
+		job, err := s.read(filepath.Join(s.dir, string(state), name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	sortJobs(jobs)
+
+	return jobs, nil
+}
+
+func (s *FileStore) path(job *Job) string {
+	if job.State == StatePending {
+		return filepath.Join(s.dir, string(StatePending), fmt.Sprintf("%020d_%s.json", job.RunAt.UnixNano(), job.ID))
+	}
+	return filepath.Join(s.dir, string(job.State), job.ID+".json")
+}
+
+// locate returns the files of a job, usually there is only one
+func (s *FileStore) locate(id string) ([]string, error) {
+	if !validID.MatchString(id) {
+		return nil, ErrNotFound
+	}
+
+	paths, err := filepath.Glob(filepath.Join(s.dir, string(StatePending), "*_"+id+".json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, state := range []State{StateRunning, StateDead} {
+		path := filepath.Join(s.dir, string(state), id+".json")
+		if _, err := os.Stat(path); err == nil {
+			paths = append(paths, path)
+		}
+	}
+
+	if len(paths) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return paths, nil
+}
+
+// names of the job files in the directory of a state, sorted
+func (s *FileStore) names(state State) ([]string, error) {
+	f, err := os.Open(filepath.Join(s.dir, string(state)))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	all, err := f.Readdirnames(-1)
+	if err != nil {
+		return nil, err
+	}
+
+	names := all[:0]
+	for _, name := range all {
+		if strings.HasSuffix(name, ".json") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+func (s *FileStore) read(path string) (*Job, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	job := new(Job)
+	if err := json.Unmarshal(b, job); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return job, nil
+}
+
+// write the job atomically via a temporary file
+func (s *FileStore) write(path string, job *Job) error {
+	b, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(s.dir, ".job-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
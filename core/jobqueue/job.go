@@ -0,0 +1,123 @@
+package jobqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"flamingo.me/dingo"
+)
+
+type (
+	// Job is an enqueued unit of work, processed by the Handler of its type
+	Job struct {
+		ID          string          `json:"id"`
+		Type        string          `json:"type"`
+		Payload     json.RawMessage `json:"payload"`
+		State       State           `json:"state"`
+		Attempts    int             `json:"attempts"`
+		MaxAttempts int             `json:"maxAttempts"`
+		LastError   string          `json:"lastError,omitempty"`
+		CreatedAt   time.Time       `json:"createdAt"`
+		RunAt       time.Time       `json:"runAt"`
+		ClaimedAt   time.Time       `json:"claimedAt"`
+	}
+
+	// State of a job
+	State string
+
+	// Handler processes the jobs of a type, register it with BindHandler
+	Handler interface {
+		// Type of the jobs processed by the handler, e.g. "checkout.confirmationMail"
+		Type() string
+		// Handle processes a job, failed jobs are retried unless the error is Permanent
+		Handle(ctx context.Context, job *Job) error
+	}
+
+	// HandlerFunc adapts a function to a Handler
+	HandlerFunc struct {
+		JobType string
+		Func    func(ctx context.Context, job *Job) error
+	}
+
+	// EnqueueOption changes a job before it is enqueued
+	EnqueueOption func(job *Job)
+
+	permanentError struct {
+		err error
+	}
+)
+
+// States of a job, done jobs are deleted
+const (
+	StatePending State = "pending"
+	StateRunning State = "running"
+	StateDead    State = "dead"
+)
+
+var _ Handler = new(HandlerFunc)
+
+// BindHandler registers a job handler:
+//
+//	jobqueue.BindHandler(injector).To(new(confirmationMailHandler))
+func BindHandler(injector *dingo.Injector) *dingo.Binding {
+	return injector.BindMulti(new(Handler))
+}
+
+// Decode the payload of the job into v
+func (j *Job) Decode(v interface{}) error {
+	return json.Unmarshal(j.Payload, v)
+}
+
+func (j *Job) clone() *Job {
+	c := *j
+	c.Payload = append(json.RawMessage(nil), j.Payload...)
+	return &c
+}
+
+// Type of the handled jobs
+func (h *HandlerFunc) Type() string {
+	return h.JobType
+}
+
+// Handle calls the function
+func (h *HandlerFunc) Handle(ctx context.Context, job *Job) error {
+	return h.Func(ctx, job)
+}
+
+// WithDelay runs the job after the delay instead of immediately
+func WithDelay(delay time.Duration) EnqueueOption {
+	return func(job *Job) {
+		job.RunAt = job.RunAt.Add(delay)
+	}
+}
+
+// WithMaxAttempts overrides core.jobqueue.maxAttempts for the job
+func WithMaxAttempts(attempts int) EnqueueOption {
+	return func(job *Job) {
+		job.MaxAttempts = attempts
+	}
+}
+
+// Permanent marks an error as permanent, the job is moved to the dead letters without further retries
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent checks if the error is marked as permanent
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
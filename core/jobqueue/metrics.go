@@ -0,0 +1,36 @@
+package jobqueue
+
+import (
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+
+	"flamingo.me/flamingo/v3/framework/opencensus"
+)
+
+// job states of the jobs metric
+const (
+	statusEnqueued = "enqueued"
+	statusDone     = "done"
+	statusRetry    = "retry"
+	statusDead     = "dead"
+)
+
+var (
+	// jobsMeasure counts enqueued and processed jobs by status
+	jobsMeasure = stats.Int64("flamingo/jobqueue/jobs", "Count of enqueued and processed jobs", stats.UnitDimensionless)
+	// durationMeasure records the processing time of jobs
+	durationMeasure = stats.Int64("flamingo/jobqueue/duration", "Processing time of jobs", stats.UnitMilliseconds)
+
+	keyType, _   = tag.NewKey("job_type")
+	keyStatus, _ = tag.NewKey("status")
+)
+
+func init() {
+	if err := opencensus.View("flamingo/jobqueue/jobs", jobsMeasure, view.Count(), keyType, keyStatus); err != nil {
+		panic(err)
+	}
+	if err := opencensus.View("flamingo/jobqueue/duration", durationMeasure, view.Distribution(10, 100, 500, 1000, 5000, 10000, 30000, 60000), keyType); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,84 @@
+// Package jobqueue processes enqueued background jobs with retries, dead letters and persistence.
+package jobqueue
+
+import (
+	"context"
+
+	"flamingo.me/dingo"
+	"github.com/spf13/cobra"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+)
+
+type (
+	// Module for core/jobqueue, the workers are started with the application and stopped on shutdown
+	Module struct {
+		store string
+		queue queueProvider
+	}
+
+	queueProvider func() *Queue
+)
+
+var (
+	_ flamingo.Starter = new(Module)
+	_ flamingo.Stopper = new(Module)
+)
+
+// Inject dependencies
+func (m *Module) Inject(queue queueProvider, config *struct {
+	Store string `inject:"config:core.jobqueue.store"`
+}) *Module {
+	m.store = config.Store
+	m.queue = queue
+	return m
+}
+
+// Configure DI
+func (m *Module) Configure(injector *dingo.Injector) {
+	switch m.store {
+	case "file":
+		injector.Bind(new(Store)).ToProvider(func(cfg *struct {
+			Dir string `inject:"config:core.jobqueue.file.dir"`
+		}) Store {
+			return NewFileStore(cfg.Dir)
+		}).In(dingo.Singleton)
+	default:
+		injector.Bind(new(Store)).To(new(MemoryStore)).In(dingo.Singleton)
+	}
+
+	injector.Bind(new(Queue)).In(dingo.Singleton)
+	injector.BindMulti(new(cobra.Command)).ToProvider(Cmd)
+}
+
+// Start the workers
+func (m *Module) Start(ctx context.Context) error {
+	m.queue().Start(ctx)
+	return nil
+}
+
+// Stop the workers, running jobs are cancelled when ctx is done
+func (m *Module) Stop(ctx context.Context) error {
+	m.queue().Stop(ctx)
+	return nil
+}
+
+// CueConfig schema and defaults
+func (m *Module) CueConfig() string {
+	// language=cue
+	return `
+core: jobqueue: {
+	enabled: bool | *true
+	store: *"memory" | "file"
+	file: dir: string | *"jobqueue"
+	workers: int | *4
+	pollInterval: string | *"1s"
+	timeout: string | *"1m"
+	maxAttempts: int | *5
+	backoff: {
+		initial: string | *"1s"
+		max: string | *"10m"
+	}
+}
+`
+}
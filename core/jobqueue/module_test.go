@@ -0,0 +1,18 @@
+package jobqueue_test
+
+import (
+	"testing"
+
+	"flamingo.me/flamingo/v3/core/jobqueue"
+	"flamingo.me/flamingo/v3/framework/config"
+)
+
+func TestModule_Configure(t *testing.T) {
+	if err := config.TryModules(nil, new(jobqueue.Module)); err != nil {
+		t.Error(err)
+	}
+
+	if err := config.TryModules(config.Map{"core.jobqueue.store": "file"}, new(jobqueue.Module)); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,371 @@
+package jobqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"go.opencensus.io/trace"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+)
+
+type (
+	// Queue enqueues jobs and processes them with a pool of workers while the application is running
+	Queue struct {
+		logger         flamingo.Logger
+		store          Store
+		handlers       map[string]Handler
+		enabled        bool
+		workers        int
+		pollInterval   time.Duration
+		timeout        time.Duration
+		maxAttempts    int
+		backoffInitial time.Duration
+		backoffMax     time.Duration
+		wake           chan struct{}
+		now            func() time.Time
+
+		mu     sync.Mutex
+		stop   chan struct{}
+		cancel context.CancelFunc
+		wg     sync.WaitGroup
+	}
+)
+
+const (
+	logKeyJobType flamingo.LogKey = "job_type"
+	logKeyJobID   flamingo.LogKey = "job_id"
+)
+
+var (
+	// ErrUnknownType is returned by Enqueue for job types without handler
+	ErrUnknownType = errors.New("no handler for job type")
+	// ErrNotDead is returned by Replay for jobs which did not fail permanently
+	ErrNotDead = errors.New("job is not a dead letter")
+)
+
+// Inject dependencies
+func (q *Queue) Inject(
+	logger flamingo.Logger,
+	store Store,
+	handlers *struct {
+		Handlers []Handler `inject:",optional"`
+	},
+	cfg *struct {
+		Enabled        bool    `inject:"config:core.jobqueue.enabled"`
+		Workers        float64 `inject:"config:core.jobqueue.workers"`
+		PollInterval   string  `inject:"config:core.jobqueue.pollInterval"`
+		Timeout        string  `inject:"config:core.jobqueue.timeout"`
+		MaxAttempts    float64 `inject:"config:core.jobqueue.maxAttempts"`
+		BackoffInitial string  `inject:"config:core.jobqueue.backoff.initial"`
+		BackoffMax     string  `inject:"config:core.jobqueue.backoff.max"`
+	},
+) *Queue {
+	q.logger = logger.WithField(flamingo.LogKeyModule, "core.jobqueue").WithField(flamingo.LogKeyCategory, "queue")
+	q.store = store
+	q.enabled = cfg.Enabled
+	q.workers = int(cfg.Workers)
+	q.maxAttempts = int(cfg.MaxAttempts)
+	q.now = time.Now
+
+	q.pollInterval = q.duration("pollInterval", cfg.PollInterval, time.Second)
+	q.timeout = q.duration("timeout", cfg.Timeout, time.Minute)
+	q.backoffInitial = q.duration("backoff.initial", cfg.BackoffInitial, time.Second)
+	q.backoffMax = q.duration("backoff.max", cfg.BackoffMax, 10*time.Minute)
+
+	if q.workers < 1 {
+		q.workers = 1
+	}
+	if q.maxAttempts < 1 {
+		q.maxAttempts = 1
+	}
+	q.wake = make(chan struct{}, q.workers)
+
+	q.handlers = make(map[string]Handler)
+	for _, handler := range handlers.Handlers {
+		if _, ok := q.handlers[handler.Type()]; ok {
+			q.logger.Error("duplicate handler for job type ", handler.Type())
+			continue
+		}
+		q.handlers[handler.Type()] = handler
+	}
+
+	return q
+}
+
+func (q *Queue) duration(key, value string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		q.logger.Error(fmt.Sprintf("invalid core.jobqueue.%s %q, using %s", key, value, fallback))
+		return fallback
+	}
+	return d
+}
+
+// Enqueue a job, the payload is stored as JSON and passed to the handler of the job type
+func (q *Queue) Enqueue(ctx context.Context, jobType string, payload interface{}, options ...EnqueueOption) (*Job, error) {
+	if _, ok := q.handlers[jobType]; !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownType, jobType)
+	}
+
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("job %s: invalid payload: %w", jobType, err)
+	}
+
+	now := q.now()
+	job := &Job{
+		ID:          uuid.NewV4().String(),
+		Type:        jobType,
+		Payload:     b,
+		State:       StatePending,
+		MaxAttempts: q.maxAttempts,
+		CreatedAt:   now,
+		RunAt:       now,
+	}
+	for _, option := range options {
+		option(job)
+	}
+
+	if err := q.store.Add(ctx, job); err != nil {
+		return nil, fmt.Errorf("job %s: %w", jobType, err)
+	}
+	record(ctx, jobType, statusEnqueued)
+	q.notify()
+
+	return job, nil
+}
+
+// Replay a dead letter, it is processed again with a fresh number of attempts
+func (q *Queue) Replay(ctx context.Context, id string) error {
+	job, err := q.store.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", id, err)
+	}
+	if job.State != StateDead {
+		return fmt.Errorf("%s: %w", id, ErrNotDead)
+	}
+
+	job.State = StatePending
+	job.Attempts = 0
+	job.RunAt = q.now()
+	if err := q.store.Update(ctx, job); err != nil {
+		return fmt.Errorf("%s: %w", id, err)
+	}
+	q.notify()
+
+	return nil
+}
+
+// Store of the queue
+func (q *Queue) Store() Store {
+	return q.store
+}
+
+// Start the workers, unless the queue is disabled or already started.
+// Running jobs which were claimed longer than the timeout ago are abandoned, e.g. after a crash, and are retried.
+func (q *Queue) Start(ctx context.Context) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if !q.enabled || q.stop != nil {
+		return
+	}
+
+	q.recoverAbandoned(ctx)
+
+	var workerCtx context.Context
+	workerCtx, q.cancel = context.WithCancel(context.Background())
+	q.stop = make(chan struct{})
+
+	for i := 0; i < q.workers; i++ {
+		q.wg.Add(1)
+		go q.work(workerCtx, q.stop)
+	}
+
+	q.logger.Info(fmt.Sprintf("job queue started with %d workers", q.workers))
+}
+
+// Stop the workers and wait for the running jobs, they are cancelled when ctx is done and retried later
+func (q *Queue) Stop(ctx context.Context) {
+	q.mu.Lock()
+	if q.stop == nil {
+		q.mu.Unlock()
+		return
+	}
+	close(q.stop)
+	cancel := q.cancel
+	q.stop, q.cancel = nil, nil
+	q.mu.Unlock()
+
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		q.logger.Warn("cancelling running jobs")
+		cancel()
+		<-done
+	}
+
+	q.logger.Info("job queue stopped")
+}
+
+func (q *Queue) recoverAbandoned(ctx context.Context) {
+	running, err := q.store.List(ctx, StateRunning)
+	if err != nil {
+		q.logger.Error("listing running jobs failed: ", err)
+		return
+	}
+
+	for _, job := range running {
+		if q.now().Sub(job.ClaimedAt) < q.timeout {
+			continue
+		}
+		job.State = StatePending
+		job.RunAt = q.now()
+		if err := q.store.Update(ctx, job); err != nil {
+			q.logger.Error(fmt.Sprintf("recovering job %s failed: %v", job.ID, err))
+			continue
+		}
+		q.logger.Warn(fmt.Sprintf("recovered abandoned job %s", job.ID))
+	}
+}
+
+func (q *Queue) notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (q *Queue) work(ctx context.Context, stop <-chan struct{}) {
+	defer q.wg.Done()
+
+	for {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
+		job, err := q.store.Claim(ctx, q.now())
+		if err != nil {
+			q.logger.Error("claiming a job failed: ", err)
+		}
+		if job != nil {
+			q.process(ctx, job)
+			continue
+		}
+
+		timer := time.NewTimer(q.pollInterval)
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-q.wake:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// process a claimed job, successful jobs are deleted, failed jobs are retried or moved to the dead letters
+func (q *Queue) process(ctx context.Context, job *Job) {
+	ctx, span := trace.StartSpan(ctx, "flamingo/jobqueue/"+job.Type)
+	defer span.End()
+	span.AddAttributes(trace.StringAttribute("job_id", job.ID))
+
+	logger := q.logger.WithContext(ctx).WithFields(map[flamingo.LogKey]interface{}{
+		logKeyJobType: job.Type,
+		logKeyJobID:   job.ID,
+	})
+
+	job.Attempts++
+	start := time.Now()
+	err := q.handle(ctx, job)
+	took := time.Since(start)
+
+	if err == nil {
+		if err := q.store.Delete(ctx, job.ID); err != nil {
+			logger.Error("deleting the done job failed: ", err)
+		}
+		record(ctx, job.Type, statusDone)
+		recordDuration(ctx, job.Type, took)
+		logger.WithField(flamingo.LogKeyResponseTime, took.Milliseconds()).Info("job done")
+		return
+	}
+
+	span.SetStatus(trace.Status{Code: trace.StatusCodeUnknown, Message: err.Error()})
+	recordDuration(ctx, job.Type, took)
+	job.LastError = err.Error()
+
+	if IsPermanent(err) || job.Attempts >= job.MaxAttempts {
+		job.State = StateDead
+		record(ctx, job.Type, statusDead)
+		logger.Error(fmt.Sprintf("job failed after %d attempts, moved to the dead letters: %v", job.Attempts, err))
+	} else {
+		job.State = StatePending
+		job.RunAt = q.now().Add(q.backoff(job.Attempts))
+		record(ctx, job.Type, statusRetry)
+		logger.Warn(fmt.Sprintf("job failed, attempt %d of %d, retry at %s: %v", job.Attempts, job.MaxAttempts, job.RunAt.Format(time.RFC3339), err))
+	}
+
+	if err := q.store.Update(ctx, job); err != nil {
+		logger.Error("updating the failed job failed: ", err)
+	}
+}
+
+// handle calls the handler with the job timeout and turns panics into errors
+func (q *Queue) handle(ctx context.Context, job *Job) (err error) {
+	handler, ok := q.handlers[job.Type]
+	if !ok {
+		return Permanent(fmt.Errorf("%w %q", ErrUnknownType, job.Type))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, q.timeout)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return handler.Handle(ctx, job)
+}
+
+// backoff doubles the delay with every attempt, up to the maximum
+func (q *Queue) backoff(attempt int) time.Duration {
+	d := q.backoffInitial
+	for i := 1; i < attempt && d < q.backoffMax; i++ {
+		d *= 2
+	}
+	if d > q.backoffMax {
+		d = q.backoffMax
+	}
+	return d
+}
+
+func record(ctx context.Context, jobType, status string) {
+	ctx, _ = tag.New(ctx, tag.Upsert(keyType, jobType), tag.Upsert(keyStatus, status))
+	stats.Record(ctx, jobsMeasure.M(1))
+}
+
+func recordDuration(ctx context.Context, jobType string, took time.Duration) {
+	ctx, _ = tag.New(ctx, tag.Upsert(keyType, jobType))
+	stats.Record(ctx, durationMeasure.M(took.Milliseconds()))
+}
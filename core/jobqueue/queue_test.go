@@ -0,0 +1,195 @@
+package jobqueue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+)
+
+type mail struct {
+	To string `json:"to"`
+}
+
+func testQueue(t *testing.T, handlers ...Handler) *Queue {
+	t.Helper()
+
+	q := new(Queue).Inject(
+		flamingo.NullLogger{},
+		new(MemoryStore),
+		&struct {
+			Handlers []Handler `inject:",optional"`
+		}{Handlers: handlers},
+		&struct {
+			Enabled        bool    `inject:"config:core.jobqueue.enabled"`
+			Workers        float64 `inject:"config:core.jobqueue.workers"`
+			PollInterval   string  `inject:"config:core.jobqueue.pollInterval"`
+			Timeout        string  `inject:"config:core.jobqueue.timeout"`
+			MaxAttempts    float64 `inject:"config:core.jobqueue.maxAttempts"`
+			BackoffInitial string  `inject:"config:core.jobqueue.backoff.initial"`
+			BackoffMax     string  `inject:"config:core.jobqueue.backoff.max"`
+		}{
+			Enabled:        true,
+			Workers:        2,
+			PollInterval:   "10ms",
+			Timeout:        "1s",
+			MaxAttempts:    3,
+			BackoffInitial: "1s",
+			BackoffMax:     "3s",
+		},
+	)
+
+	now := time.Date(2020, time.March, 1, 12, 0, 0, 0, time.UTC)
+	q.now = func() time.Time { return now }
+
+	return q
+}
+
+func TestQueue_Process(t *testing.T) {
+	ctx := context.Background()
+	var err error
+	q := testQueue(t,
+		&HandlerFunc{JobType: "mail", Func: func(_ context.Context, job *Job) error {
+			var m mail
+			if err := job.Decode(&m); err != nil {
+				return Permanent(err)
+			}
+			if m.To == "" {
+				return Permanent(errors.New("no recipient"))
+			}
+			return err
+		}},
+		&HandlerFunc{JobType: "panic", Func: func(context.Context, *Job) error { panic("oops") }},
+	)
+
+	_, enqueueErr := q.Enqueue(ctx, "unknown", nil)
+	assert.True(t, errors.Is(enqueueErr, ErrUnknownType))
+
+	// a successful job is deleted
+	job, enqueueErr := q.Enqueue(ctx, "mail", mail{To: "a@example.com"})
+	require.NoError(t, enqueueErr)
+	assert.Equal(t, 3, job.MaxAttempts)
+	q.processNext(t)
+	_, getErr := q.store.Get(ctx, job.ID)
+	assert.Equal(t, ErrNotFound, getErr)
+
+	// failed jobs are retried with backoff until they are dead
+	err = errors.New("smtp unavailable")
+	job, _ = q.Enqueue(ctx, "mail", mail{To: "b@example.com"})
+	for attempt, backoff := range []time.Duration{time.Second, 2 * time.Second} {
+		q.processNext(t)
+		job, _ = q.store.Get(ctx, job.ID)
+		assert.Equal(t, StatePending, job.State)
+		assert.Equal(t, attempt+1, job.Attempts)
+		assert.Equal(t, q.now().Add(backoff), job.RunAt)
+		assert.Equal(t, "smtp unavailable", job.LastError)
+
+		job.RunAt = q.now()
+		require.NoError(t, q.store.Update(ctx, job))
+	}
+	q.processNext(t)
+	job, _ = q.store.Get(ctx, job.ID)
+	assert.Equal(t, StateDead, job.State)
+	assert.Equal(t, 3, job.Attempts)
+
+	// dead letters can be replayed
+	err = nil
+	assert.True(t, errors.Is(q.Replay(ctx, "unknown"), ErrNotFound))
+	require.NoError(t, q.Replay(ctx, job.ID))
+	assert.True(t, errors.Is(q.Replay(ctx, job.ID), ErrNotDead))
+	q.processNext(t)
+	_, getErr = q.store.Get(ctx, job.ID)
+	assert.Equal(t, ErrNotFound, getErr)
+
+	// permanent errors and panics
+	job, _ = q.Enqueue(ctx, "mail", mail{}, WithMaxAttempts(10))
+	q.processNext(t)
+	job, _ = q.store.Get(ctx, job.ID)
+	assert.Equal(t, StateDead, job.State)
+	assert.Equal(t, "no recipient", job.LastError)
+
+	job, _ = q.Enqueue(ctx, "panic", nil, WithMaxAttempts(1))
+	q.processNext(t)
+	job, _ = q.store.Get(ctx, job.ID)
+	assert.Equal(t, StateDead, job.State)
+	assert.Equal(t, "panic: oops", job.LastError)
+
+	// delayed jobs are not due yet
+	_, _ = q.Enqueue(ctx, "mail", mail{To: "c@example.com"}, WithDelay(time.Minute))
+	none, claimErr := q.store.Claim(ctx, q.now())
+	require.NoError(t, claimErr)
+	assert.Nil(t, none)
+}
+
+func TestQueue_Backoff(t *testing.T) {
+	q := testQueue(t)
+
+	assert.Equal(t, time.Second, q.backoff(1))
+	assert.Equal(t, 2*time.Second, q.backoff(2))
+	assert.Equal(t, 3*time.Second, q.backoff(3))
+	assert.Equal(t, 3*time.Second, q.backoff(100))
+}
+
+func TestQueue_Workers(t *testing.T) {
+	processed := make(chan string, 1)
+	q := testQueue(t, &HandlerFunc{JobType: "mail", Func: func(_ context.Context, job *Job) error {
+		var m mail
+		_ = job.Decode(&m)
+		processed <- m.To
+		return nil
+	}})
+	q.now = time.Now
+
+	q.Start(context.Background())
+	defer q.Stop(context.Background())
+
+	_, err := q.Enqueue(context.Background(), "mail", mail{To: "a@example.com"})
+	require.NoError(t, err)
+
+	select {
+	case to := <-processed:
+		assert.Equal(t, "a@example.com", to)
+	case <-time.After(5 * time.Second):
+		t.Fatal("job was not processed")
+	}
+}
+
+func TestQueue_RecoverAbandoned(t *testing.T) {
+	q := testQueue(t, &HandlerFunc{JobType: "mail", Func: func(context.Context, *Job) error { return nil }})
+	ctx := context.Background()
+
+	abandoned, _ := q.Enqueue(ctx, "mail", mail{})
+	claimed, err := q.store.Claim(ctx, q.now())
+	require.NoError(t, err)
+	require.Equal(t, abandoned.ID, claimed.ID)
+
+	// claimed recently, the job might still be running
+	q.recoverAbandoned(ctx)
+	job, err := q.store.Get(ctx, abandoned.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StateRunning, job.State)
+
+	claimed.ClaimedAt = q.now().Add(-time.Hour)
+	require.NoError(t, q.store.Update(ctx, claimed))
+
+	q.recoverAbandoned(ctx)
+
+	job, err = q.store.Get(ctx, abandoned.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatePending, job.State)
+}
+
+// processNext processes the next due job
+func (q *Queue) processNext(t *testing.T) {
+	t.Helper()
+
+	job, err := q.store.Claim(context.Background(), q.now())
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	q.process(context.Background(), job)
+}
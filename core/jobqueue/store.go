@@ -0,0 +1,137 @@
+package jobqueue
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+type (
+	// Store persists the jobs of the queue
+	Store interface {
+		// Add a new job
+		Add(ctx context.Context, job *Job) error
+		// Claim the pending job which is due first and mark it as running, nil if no job is due.
+		// A job must only be claimed once, also by concurrent workers.
+		Claim(ctx context.Context, now time.Time) (*Job, error)
+		// Update a job after its state changed
+		Update(ctx context.Context, job *Job) error
+		// Delete a job
+		Delete(ctx context.Context, id string) error
+		// Get a job, ErrNotFound if it does not exist
+		Get(ctx context.Context, id string) (*Job, error)
+		// List the jobs in a state, ordered by RunAt
+		List(ctx context.Context, state State) ([]*Job, error)
+	}
+
+	// MemoryStore keeps the jobs in memory, they are lost on restart
+	MemoryStore struct {
+		mu   sync.Mutex
+		jobs map[string]*Job
+	}
+)
+
+// ErrNotFound is returned for unknown jobs
+var ErrNotFound = errors.New("job not found")
+
+var _ Store = new(MemoryStore)
+
+// Add a job
+func (s *MemoryStore) Add(_ context.Context, job *Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.jobs == nil {
+		s.jobs = make(map[string]*Job)
+	}
+	s.jobs[job.ID] = job.clone()
+
+	return nil
+}
+
+// Claim the job which is due first
+func (s *MemoryStore) Claim(_ context.Context, now time.Time) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var next *Job
+	for _, job := range s.jobs {
+		if job.State != StatePending || job.RunAt.After(now) {
+			continue
+		}
+		if next == nil || job.RunAt.Before(next.RunAt) {
+			next = job
+		}
+	}
+	if next == nil {
+		return nil, nil
+	}
+
+	next.State = StateRunning
+	next.ClaimedAt = now
+
+	return next.clone(), nil
+}
+
+// Update a job
+func (s *MemoryStore) Update(_ context.Context, job *Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.jobs[job.ID]; !ok {
+		return ErrNotFound
+	}
+	s.jobs[job.ID] = job.clone()
+
+	return nil
+}
+
+// Delete a job
+func (s *MemoryStore) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.jobs, id)
+
+	return nil
+}
+
+// Get a job
+func (s *MemoryStore) Get(_ context.Context, id string) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return job.clone(), nil
+}
+
+// List the jobs in a state
+func (s *MemoryStore) List(_ context.Context, state State) ([]*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var jobs []*Job
+	for _, job := range s.jobs {
+		if job.State == state {
+			jobs = append(jobs, job.clone())
+		}
+	}
+	sortJobs(jobs)
+
+	return jobs, nil
+}
+
+func sortJobs(jobs []*Job) {
+	sort.Slice(jobs, func(i, k int) bool {
+		if jobs[i].RunAt.Equal(jobs[k].RunAt) {
+			return jobs[i].ID < jobs[k].ID
+		}
+		return jobs[i].RunAt.Before(jobs[k].RunAt)
+	})
+}
@@ -0,0 +1,106 @@
+package jobqueue_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"flamingo.me/flamingo/v3/core/jobqueue"
+)
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, new(jobqueue.MemoryStore))
+}
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jobqueue")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	testStore(t, jobqueue.NewFileStore(dir))
+
+	// the jobs are persisted
+	jobs, err := jobqueue.NewFileStore(dir).List(context.Background(), jobqueue.StateDead)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "job-2", jobs[0].ID)
+}
+
+func testStore(t *testing.T, store jobqueue.Store) {
+	t.Helper()
+
+	ctx := context.Background()
+	now := time.Date(2020, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	for i, runAt := range []time.Time{now.Add(time.Minute), now.Add(-time.Minute), now} {
+		require.NoError(t, store.Add(ctx, &jobqueue.Job{
+			ID:      "job-" + string(rune('1'+i)),
+			Type:    "test",
+			Payload: []byte(`{"n":1}`),
+			State:   jobqueue.StatePending,
+			RunAt:   runAt,
+		}))
+	}
+
+	pending, err := store.List(ctx, jobqueue.StatePending)
+	require.NoError(t, err)
+	require.Len(t, pending, 3)
+	assert.Equal(t, []string{"job-2", "job-3", "job-1"}, []string{pending[0].ID, pending[1].ID, pending[2].ID})
+
+	// due jobs are claimed in order, every job only once
+	job, err := store.Claim(ctx, now)
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	assert.Equal(t, "job-2", job.ID)
+	assert.Equal(t, jobqueue.StateRunning, job.State)
+	assert.Equal(t, now, job.ClaimedAt.UTC())
+	assert.JSONEq(t, `{"n":1}`, string(job.Payload))
+
+	next, err := store.Claim(ctx, now)
+	require.NoError(t, err)
+	require.NotNil(t, next)
+	assert.Equal(t, "job-3", next.ID)
+
+	none, err := store.Claim(ctx, now)
+	require.NoError(t, err)
+	assert.Nil(t, none)
+
+	running, err := store.List(ctx, jobqueue.StateRunning)
+	require.NoError(t, err)
+	assert.Len(t, running, 2)
+
+	job.State = jobqueue.StateDead
+	job.LastError = "boom"
+	require.NoError(t, store.Update(ctx, job))
+
+	dead, err := store.Get(ctx, "job-2")
+	require.NoError(t, err)
+	assert.Equal(t, jobqueue.StateDead, dead.State)
+	assert.Equal(t, "boom", dead.LastError)
+
+	require.NoError(t, store.Delete(ctx, "job-3"))
+	_, err = store.Get(ctx, "job-3")
+	assert.Equal(t, jobqueue.ErrNotFound, err)
+	assert.Equal(t, jobqueue.ErrNotFound, store.Update(ctx, &jobqueue.Job{ID: "job-3", State: jobqueue.StatePending}))
+
+	// a retried job is due again later
+	pendingJob, err := store.Get(ctx, "job-1")
+	require.NoError(t, err)
+	pendingJob.RunAt = now.Add(time.Hour)
+	require.NoError(t, store.Update(ctx, pendingJob))
+
+	none, err = store.Claim(ctx, now.Add(30*time.Minute))
+	require.NoError(t, err)
+	assert.Nil(t, none)
+
+	claimed, err := store.Claim(ctx, now.Add(time.Hour))
+	require.NoError(t, err)
+	require.NotNil(t, claimed)
+	assert.Equal(t, "job-1", claimed.ID)
+	require.NoError(t, store.Delete(ctx, "job-1"))
+}
@@ -0,0 +1,104 @@
+package maintenance
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+// Cmd switches the maintenance mode of running instances by writing the flag file
+func Cmd(cfg *struct {
+	FlagFile string `inject:"config:core.maintenance.flagFile"`
+}) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "maintenance",
+		Short: "Switch the maintenance mode via the flag file",
+	}
+
+	flagFile := func() (string, error) {
+		if cfg.FlagFile == "" {
+			return "", errors.New("core.maintenance.flagFile is not configured")
+		}
+		return cfg.FlagFile, nil
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "on [area...]",
+		Short: "Enable the maintenance mode for the given areas, or for all areas",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, err := flagFile()
+			if err != nil {
+				return err
+			}
+
+			areas := readFlagFile(file)
+			if len(args) == 0 || areas[AllAreas] {
+				// an empty flag file affects all areas
+				return writeFlagFile(file, map[string]bool{AllAreas: true})
+			}
+			if areas == nil {
+				areas = make(map[string]bool)
+			}
+			for _, area := range args {
+				areas[area] = true
+			}
+			return writeFlagFile(file, areas)
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "off [area...]",
+		Short: "Disable the maintenance mode for the given areas, or for all areas",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, err := flagFile()
+			if err != nil {
+				return err
+			}
+
+			areas := readFlagFile(file)
+			if len(args) == 0 {
+				return writeFlagFile(file, nil)
+			}
+			if areas[AllAreas] {
+				return errors.New("the flag file affects all areas, use off without areas")
+			}
+			for _, area := range args {
+				delete(areas, area)
+			}
+			return writeFlagFile(file, areas)
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "status",
+		Short: "Show the areas of the flag file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, err := flagFile()
+			if err != nil {
+				return err
+			}
+
+			areas := readFlagFile(file)
+			switch {
+			case areas == nil:
+				fmt.Fprintln(cmd.OutOrStdout(), "maintenance mode off")
+			case areas[AllAreas]:
+				fmt.Fprintln(cmd.OutOrStdout(), "maintenance mode on for all areas")
+			default:
+				names := make([]string, 0, len(areas))
+				for area := range areas {
+					names = append(names, area)
+				}
+				sort.Strings(names)
+				for _, area := range names {
+					fmt.Fprintln(cmd.OutOrStdout(), "maintenance mode on for area", area)
+				}
+			}
+			return nil
+		},
+	})
+
+	return cmd
+}
@@ -0,0 +1,60 @@
+package maintenance
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+type (
+	// Endpoint shows and switches the maintenance mode on the system endpoint.
+	// GET returns the state, POST switches with the query parameters active (true, false or reset) and the optional area.
+	Endpoint struct {
+		maintenanceSwitch *Switch
+	}
+
+	endpointStatus struct {
+		Runtime  map[string]bool `json:"runtime"`
+		FlagFile []string        `json:"flagFile"`
+	}
+)
+
+// Inject dependencies
+func (e *Endpoint) Inject(maintenanceSwitch *Switch) *Endpoint {
+	e.maintenanceSwitch = maintenanceSwitch
+	return e
+}
+
+// ServeHTTP handles the status and switch requests
+func (e *Endpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		area := r.URL.Query().Get("area")
+		switch active := r.URL.Query().Get("active"); active {
+		case "reset":
+			e.maintenanceSwitch.Reset(r.Context(), area)
+		default:
+			on, err := strconv.ParseBool(active)
+			if err != nil {
+				http.Error(w, "active must be true, false or reset", http.StatusBadRequest)
+				return
+			}
+			if on {
+				e.maintenanceSwitch.Enable(r.Context(), area)
+			} else {
+				e.maintenanceSwitch.Disable(r.Context(), area)
+			}
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	status := new(endpointStatus)
+	status.Runtime, status.FlagFile = e.maintenanceSwitch.Status()
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(status)
+}
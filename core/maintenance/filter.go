@@ -0,0 +1,170 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"flamingo.me/flamingo/v3/core/auth"
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/web"
+)
+
+type (
+	filter struct {
+		maintenanceSwitch *Switch
+		responder         *web.Responder
+		logger            flamingo.Logger
+		area              string
+		enabled           bool
+		template          string
+		retryAfter        int
+		allowedNets       []*net.IPNet
+		allowedSubjects   map[string]bool
+		allowedPaths      []string
+		trustForwardedFor bool
+		identifiers       []auth.RequestIdentifier
+	}
+)
+
+// ErrMaintenance is the error of the 503 response served during maintenance
+var ErrMaintenance = errors.New("maintenance mode")
+
+// Inject dependencies
+func (f *filter) Inject(
+	maintenanceSwitch *Switch,
+	responder *web.Responder,
+	logger flamingo.Logger,
+	area *config.Area,
+	cfg *struct {
+		Enabled           bool         `inject:"config:core.maintenance.enabled"`
+		Template          string       `inject:"config:flamingo.template.err503,optional"`
+		RetryAfter        float64      `inject:"config:core.maintenance.retryAfter"`
+		AllowIPs          config.Slice `inject:"config:core.maintenance.allow.ips"`
+		AllowIdentities   config.Slice `inject:"config:core.maintenance.allow.identities"`
+		AllowPaths        config.Slice `inject:"config:core.maintenance.allow.paths"`
+		TrustForwardedFor bool         `inject:"config:core.maintenance.trustForwardedFor"`
+	},
+	identifiers *struct {
+		Identifiers []auth.RequestIdentifier `inject:",optional"`
+	},
+) *filter {
+	f.maintenanceSwitch = maintenanceSwitch
+	f.responder = responder
+	f.logger = logger.WithField(flamingo.LogKeyModule, "core.maintenance").WithField(flamingo.LogKeyCategory, "filter")
+	if area != nil {
+		f.area = fullAreaName(area)
+	}
+	f.enabled = cfg.Enabled
+	f.template = cfg.Template
+	f.retryAfter = int(cfg.RetryAfter)
+	f.trustForwardedFor = cfg.TrustForwardedFor
+	f.identifiers = identifiers.Identifiers
+
+	var ips, subjects []string
+	if err := cfg.AllowIPs.MapInto(&ips); err != nil {
+		f.logger.Error("invalid ip allowlist: ", err)
+	}
+	for _, ip := range ips {
+		if !strings.Contains(ip, "/") {
+			if strings.Contains(ip, ":") {
+				ip += "/128"
+			} else {
+				ip += "/32"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(ip)
+		if err != nil {
+			f.logger.Error("invalid ip allowlist entry: ", err)
+			continue
+		}
+		f.allowedNets = append(f.allowedNets, ipNet)
+	}
+
+	if err := cfg.AllowIdentities.MapInto(&subjects); err != nil {
+		f.logger.Error("invalid identity allowlist: ", err)
+	}
+	f.allowedSubjects = make(map[string]bool, len(subjects))
+	for _, subject := range subjects {
+		f.allowedSubjects[subject] = true
+	}
+
+	if err := cfg.AllowPaths.MapInto(&f.allowedPaths); err != nil {
+		f.logger.Error("invalid path allowlist: ", err)
+	}
+
+	return f
+}
+
+// Filter serves the maintenance page if the maintenance mode is active and the request is not allowlisted
+func (f *filter) Filter(ctx context.Context, r *web.Request, w http.ResponseWriter, chain *web.FilterChain) web.Result {
+	if !f.maintenanceSwitch.Active(f.area, f.enabled) || f.allowed(ctx, r) {
+		return chain.Next(ctx, r, w)
+	}
+
+	response := f.responder.ServerErrorWithCodeAndTemplate(ErrMaintenance, f.template, http.StatusServiceUnavailable)
+	response.SetNoCache()
+	if f.retryAfter > 0 {
+		response.Header.Set("Retry-After", strconv.Itoa(f.retryAfter))
+	}
+
+	return response
+}
+
+func (f *filter) allowed(ctx context.Context, r *web.Request) bool {
+	for _, prefix := range f.allowedPaths {
+		if strings.HasPrefix(r.Request().URL.Path, prefix) {
+			return true
+		}
+	}
+
+	if len(f.allowedNets) > 0 {
+		if ip := f.clientIP(r); ip != nil {
+			for _, ipNet := range f.allowedNets {
+				if ipNet.Contains(ip) {
+					return true
+				}
+			}
+		}
+	}
+
+	if len(f.allowedSubjects) > 0 {
+		for _, identifier := range f.identifiers {
+			if identity, _ := identifier.Identify(ctx, r); identity != nil && f.allowedSubjects[identity.Subject()] {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// clientIP uses the first X-Forwarded-For address only if configured, as the header can be set by any client
+func (f *filter) clientIP(r *web.Request) net.IP {
+	address := r.Request().RemoteAddr
+	if f.trustForwardedFor {
+		if forwarded := r.Request().Header.Get("X-Forwarded-For"); forwarded != "" {
+			address = strings.TrimSpace(strings.Split(forwarded, ",")[0])
+		}
+	}
+
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		address = host
+	}
+
+	return net.ParseIP(address)
+}
+
+// fullAreaName is the name of the area including its parents, as used by the prefixrouter (e.g. "root/shop-de")
+func fullAreaName(area *config.Area) string {
+	name := area.Name
+	for parent := area.Parent; parent != nil; parent = parent.Parent {
+		name = parent.Name + "/" + name
+	}
+
+	return name
+}
@@ -0,0 +1,42 @@
+package maintenance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"flamingo.me/flamingo/v3/core/auth"
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+)
+
+func TestFilter_NestedArea(t *testing.T) {
+	root := &config.Area{Name: "root"}
+	shop := &config.Area{Name: "shop-de", Parent: root}
+	account := &config.Area{Name: "account", Parent: shop}
+
+	s := new(Switch).Inject(new(recordingEventRouter), flamingo.NullLogger{}, &struct {
+		FlagFile string `inject:"config:core.maintenance.flagFile"`
+	}{})
+	f := new(filter).Inject(s, nil, flamingo.NullLogger{}, account, &struct {
+		Enabled           bool         `inject:"config:core.maintenance.enabled"`
+		Template          string       `inject:"config:flamingo.template.err503,optional"`
+		RetryAfter        float64      `inject:"config:core.maintenance.retryAfter"`
+		AllowIPs          config.Slice `inject:"config:core.maintenance.allow.ips"`
+		AllowIdentities   config.Slice `inject:"config:core.maintenance.allow.identities"`
+		AllowPaths        config.Slice `inject:"config:core.maintenance.allow.paths"`
+		TrustForwardedFor bool         `inject:"config:core.maintenance.trustForwardedFor"`
+	}{}, &struct {
+		Identifiers []auth.RequestIdentifier `inject:",optional"`
+	}{})
+	assert.Equal(t, "root/shop-de/account", f.area)
+
+	ctx := context.Background()
+	for _, name := range []string{"root/shop-de/account", "account"} {
+		s.Enable(ctx, name)
+		assert.True(t, s.Active(f.area, false), name)
+		s.Reset(ctx, name)
+		assert.False(t, s.Active(f.area, false), name)
+	}
+}
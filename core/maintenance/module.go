@@ -0,0 +1,60 @@
+// Package maintenance provides a maintenance mode which can be switched at runtime, per area.
+package maintenance
+
+import (
+	"flamingo.me/dingo"
+	"flamingo.me/flamingo/v3/framework/systemendpoint"
+	"flamingo.me/flamingo/v3/framework/systemendpoint/domain"
+	"flamingo.me/flamingo/v3/framework/web"
+	"github.com/spf13/cobra"
+)
+
+// Module for core/maintenance
+type Module struct {
+	endpoint string
+}
+
+// Inject dependencies
+func (m *Module) Inject(config *struct {
+	Endpoint string `inject:"config:core.maintenance.endpoint"`
+}) *Module {
+	m.endpoint = config.Endpoint
+	return m
+}
+
+// Configure DI
+func (m *Module) Configure(injector *dingo.Injector) {
+	injector.Bind(new(Switch)).In(dingo.Singleton)
+	injector.BindMulti(new(web.Filter)).To(filter{})
+	injector.BindMulti(new(cobra.Command)).ToProvider(Cmd)
+
+	if m.endpoint != "" {
+		injector.BindMap((*domain.Handler)(nil), m.endpoint).To(&Endpoint{})
+	}
+}
+
+// CueConfig schema and defaults
+func (m *Module) CueConfig() string {
+	// language=cue
+	return `
+core: maintenance: {
+	enabled: bool | *false
+	flagFile: string | *""
+	endpoint: string | *"/maintenance"
+	retryAfter: int | *300
+	trustForwardedFor: bool | *false
+	allow: {
+		ips: [...string]
+		identities: [...string]
+		paths: [...string]
+	}
+}
+`
+}
+
+// Depends on the systemendpoint module
+func (*Module) Depends() []dingo.Module {
+	return []dingo.Module{
+		new(systemendpoint.Module),
+	}
+}
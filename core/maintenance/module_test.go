@@ -0,0 +1,14 @@
+package maintenance_test
+
+import (
+	"testing"
+
+	"flamingo.me/flamingo/v3/core/maintenance"
+	"flamingo.me/flamingo/v3/framework/config"
+)
+
+func TestModule_Configure(t *testing.T) {
+	if err := config.TryModules(nil, new(maintenance.Module)); err != nil {
+		t.Error(err)
+	}
+}
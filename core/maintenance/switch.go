@@ -0,0 +1,259 @@
+package maintenance
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+)
+
+type (
+	// Switch holds the maintenance state of all areas, it can be toggled at runtime.
+	// The state of an area is resolved in this order: the runtime state of the area, the runtime state of all areas,
+	// the flag file and finally the configuration of the area.
+	Switch struct {
+		mu          sync.RWMutex
+		eventRouter flamingo.EventRouter
+		logger      flamingo.Logger
+		runtime     map[string]bool
+		flagFile    string
+		flagAreas   map[string]bool
+		flagChecked time.Time
+		now         func() time.Time
+	}
+
+	// ChangedEvent is dispatched when the maintenance mode is switched, Area is empty if all areas are affected
+	ChangedEvent struct {
+		Area   string
+		Active bool
+		Source string
+	}
+)
+
+const (
+	// AllAreas is used to switch the maintenance mode of all areas
+	AllAreas = ""
+
+	// SourceRuntime marks changes via the Go API or the system endpoint
+	SourceRuntime = "runtime"
+	// SourceFlagFile marks changes of the flag file
+	SourceFlagFile = "flagFile"
+
+	flagFileCheckInterval = time.Second
+)
+
+// Inject dependencies
+func (s *Switch) Inject(eventRouter flamingo.EventRouter, logger flamingo.Logger, cfg *struct {
+	FlagFile string `inject:"config:core.maintenance.flagFile"`
+}) *Switch {
+	s.eventRouter = eventRouter
+	s.logger = logger.WithField(flamingo.LogKeyModule, "core.maintenance").WithField(flamingo.LogKeyCategory, "switch")
+	s.flagFile = cfg.FlagFile
+	return s
+}
+
+// Enable the maintenance mode for an area, or for all areas
+func (s *Switch) Enable(ctx context.Context, area string) {
+	s.set(ctx, area, true)
+}
+
+// Disable the maintenance mode for an area, or for all areas
+func (s *Switch) Disable(ctx context.Context, area string) {
+	s.set(ctx, area, false)
+}
+
+// Reset removes the runtime state of an area, the flag file and configuration apply again
+func (s *Switch) Reset(ctx context.Context, area string) {
+	s.mu.Lock()
+	_, ok := s.runtime[area]
+	delete(s.runtime, area)
+	s.mu.Unlock()
+
+	if ok {
+		s.logger.WithContext(ctx).Info("maintenance mode reset for ", areaLabel(area))
+	}
+}
+
+func (s *Switch) set(ctx context.Context, area string, active bool) {
+	s.mu.Lock()
+	if s.runtime == nil {
+		s.runtime = make(map[string]bool)
+	}
+	previous, ok := s.runtime[area]
+	s.runtime[area] = active
+	s.mu.Unlock()
+
+	if ok && previous == active {
+		return
+	}
+
+	s.logger.WithContext(ctx).Info("maintenance mode ", onOff(active), " for ", areaLabel(area))
+	s.dispatch(ctx, &ChangedEvent{Area: area, Active: active, Source: SourceRuntime})
+}
+
+// Active checks if the maintenance mode is active for the area, configured is the configured state of the area
+func (s *Switch) Active(area string, configured bool) bool {
+	s.refreshFlagFile()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, key := range areaKeys(area) {
+		if active, ok := s.runtime[key]; ok {
+			return active
+		}
+	}
+	if active, ok := s.runtime[AllAreas]; ok {
+		return active
+	}
+
+	if s.flagAreas != nil {
+		if s.flagAreas[AllAreas] {
+			return true
+		}
+		for _, key := range areaKeys(area) {
+			if s.flagAreas[key] {
+				return true
+			}
+		}
+	}
+
+	return configured
+}
+
+// Status returns the runtime states by area and the areas of the flag file
+func (s *Switch) Status() (runtime map[string]bool, flagFile []string) {
+	s.refreshFlagFile()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	runtime = make(map[string]bool, len(s.runtime))
+	for area, active := range s.runtime {
+		runtime[area] = active
+	}
+	for area := range s.flagAreas {
+		flagFile = append(flagFile, area)
+	}
+	sort.Strings(flagFile)
+
+	return runtime, flagFile
+}
+
+// refreshFlagFile reads the flag file at most once per second
+func (s *Switch) refreshFlagFile() {
+	if s.flagFile == "" {
+		return
+	}
+
+	now := time.Now()
+	if s.now != nil {
+		now = s.now()
+	}
+
+	s.mu.RLock()
+	fresh := now.Sub(s.flagChecked) < flagFileCheckInterval
+	s.mu.RUnlock()
+	if fresh {
+		return
+	}
+
+	areas := readFlagFile(s.flagFile)
+
+	s.mu.Lock()
+	previous := s.flagAreas
+	s.flagAreas = areas
+	s.flagChecked = now
+	s.mu.Unlock()
+
+	for area := range areas {
+		if !previous[area] {
+			s.logger.Info("maintenance mode on for ", areaLabel(area), " by flag file ", s.flagFile)
+			s.dispatch(context.Background(), &ChangedEvent{Area: area, Active: true, Source: SourceFlagFile})
+		}
+	}
+	for area := range previous {
+		if !areas[area] {
+			s.logger.Info("maintenance mode off for ", areaLabel(area), " by flag file ", s.flagFile)
+			s.dispatch(context.Background(), &ChangedEvent{Area: area, Active: false, Source: SourceFlagFile})
+		}
+	}
+}
+
+func (s *Switch) dispatch(ctx context.Context, event *ChangedEvent) {
+	if s.eventRouter != nil {
+		s.eventRouter.Dispatch(ctx, event)
+	}
+}
+
+// readFlagFile returns nil if the file does not exist, an empty file affects all areas, otherwise it lists one area per line
+func readFlagFile(file string) map[string]bool {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil
+	}
+
+	areas := make(map[string]bool)
+	for _, line := range strings.Split(string(content), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			areas[line] = true
+		}
+	}
+	if len(areas) == 0 {
+		areas[AllAreas] = true
+	}
+
+	return areas
+}
+
+// writeFlagFile writes the areas to the flag file, no areas removes the file
+func writeFlagFile(file string, areas map[string]bool) error {
+	if len(areas) == 0 {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	var lines []string
+	for area := range areas {
+		if area != AllAreas {
+			lines = append(lines, area)
+		}
+	}
+	sort.Strings(lines)
+
+	content := strings.Join(lines, "\n")
+	if len(lines) > 0 {
+		content += "\n"
+	}
+
+	return ioutil.WriteFile(file, []byte(content), 0644)
+}
+
+// areaKeys returns the full area name as used by the prefixrouter (e.g. "root/shop-de") and the name of the area itself
+func areaKeys(area string) []string {
+	if i := strings.LastIndex(area, "/"); i >= 0 {
+		return []string{area, area[i+1:]}
+	}
+	return []string{area}
+}
+
+func areaLabel(area string) string {
+	if area == AllAreas {
+		return "all areas"
+	}
+	return "area " + area
+}
+
+func onOff(active bool) string {
+	if active {
+		return "on"
+	}
+	return "off"
+}
@@ -0,0 +1,78 @@
+package maintenance
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+)
+
+type recordingEventRouter struct {
+	events []flamingo.Event
+}
+
+func (r *recordingEventRouter) Dispatch(_ context.Context, event flamingo.Event) {
+	r.events = append(r.events, event)
+}
+
+func TestSwitch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "maintenance")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	flagFile := filepath.Join(dir, "maintenance.flag")
+	now := time.Now()
+	eventRouter := new(recordingEventRouter)
+	s := new(Switch).Inject(eventRouter, flamingo.NullLogger{}, &struct {
+		FlagFile string `inject:"config:core.maintenance.flagFile"`
+	}{FlagFile: flagFile})
+	s.now = func() time.Time { return now }
+
+	ctx := context.Background()
+
+	assert.False(t, s.Active("root/shop-de", false))
+	assert.True(t, s.Active("root/shop-de", true), "configured state applies")
+
+	s.Enable(ctx, "shop-de")
+	assert.True(t, s.Active("root/shop-de", false))
+	assert.False(t, s.Active("root/shop-at", false))
+
+	s.Enable(ctx, "shop-de")
+	assert.Len(t, eventRouter.events, 1, "no event without change")
+	assert.Equal(t, &ChangedEvent{Area: "shop-de", Active: true, Source: SourceRuntime}, eventRouter.events[0])
+
+	s.Disable(ctx, AllAreas)
+	assert.True(t, s.Active("root/shop-de", false), "area state wins over all areas")
+	assert.False(t, s.Active("root/shop-at", true))
+
+	s.Reset(ctx, "shop-de")
+	s.Reset(ctx, AllAreas)
+	assert.False(t, s.Active("root/shop-de", false))
+
+	require.NoError(t, writeFlagFile(flagFile, map[string]bool{"shop-at": true}))
+	now = now.Add(2 * time.Second)
+	assert.True(t, s.Active("root/shop-at", false))
+	assert.False(t, s.Active("root/shop-de", false))
+	assert.Equal(t, &ChangedEvent{Area: "shop-at", Active: true, Source: SourceFlagFile}, eventRouter.events[len(eventRouter.events)-1])
+
+	require.NoError(t, writeFlagFile(flagFile, map[string]bool{AllAreas: true}))
+	assert.False(t, s.Active("root/shop-de", false), "flag file is cached")
+	now = now.Add(2 * time.Second)
+	assert.True(t, s.Active("root/shop-de", false))
+
+	runtime, areas := s.Status()
+	assert.Empty(t, runtime)
+	assert.Equal(t, []string{AllAreas}, areas)
+
+	require.NoError(t, writeFlagFile(flagFile, nil))
+	now = now.Add(2 * time.Second)
+	assert.False(t, s.Active("root/shop-de", false))
+	assert.Equal(t, &ChangedEvent{Area: AllAreas, Active: false, Source: SourceFlagFile}, eventRouter.events[len(eventRouter.events)-1])
+}
@@ -15,6 +15,7 @@ import (
 	"flamingo.me/flamingo/v3/core/oauth/domain"
 	"flamingo.me/flamingo/v3/framework/config"
 	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/redact"
 	"flamingo.me/flamingo/v3/framework/web"
 	"github.com/coreos/go-oidc"
 	"golang.org/x/oauth2"
@@ -51,6 +52,7 @@ type (
 		idTokenMapping      config.Slice
 		userInfoMapping     config.Slice
 		logger              flamingo.Logger
+		redactor            *redact.Redactor
 		router              *web.Router
 		openIDProvider      *oidc.Provider
 		tokenExtras         config.Slice
@@ -59,6 +61,7 @@ type (
 
 	loggingRoundTripper struct {
 		originalTransport http.RoundTripper
+		redactor          *redact.Redactor
 	}
 )
 
@@ -70,11 +73,11 @@ func (f *loggingRoundTripper) RoundTrip(req *http.Request) (*http.Response, erro
 	b, err := httputil.DumpRequest(req, true)
 	log.Println()
 	log.Println("############### OAUTH REQUEST:")
-	log.Printf("%v  %v ", string(b), err)
+	log.Printf("%v  %v ", f.redactor.String(string(b)), err)
 	res, err := f.originalTransport.RoundTrip(req)
 	b, _ = httputil.DumpResponse(res, true)
 	log.Println("############### OAUTH RESPONSE:")
-	log.Printf("%v  %v ", string(b), err)
+	log.Printf("%v  %v ", f.redactor.String(string(b)), err)
 	log.Println("############### OAUTH Call Stack:")
 	log.Println()
 	debug.PrintStack()
@@ -82,7 +85,7 @@ func (f *loggingRoundTripper) RoundTrip(req *http.Request) (*http.Response, erro
 }
 
 // Inject authManager dependencies
-func (am *AuthManager) Inject(logger flamingo.Logger, router *web.Router, config *struct {
+func (am *AuthManager) Inject(logger flamingo.Logger, redactor *redact.Redactor, router *web.Router, config *struct {
 	Server              string       `inject:"config:core.oauth.server"`
 	Secret              string       `inject:"config:core.oauth.secret"`
 	ClientID            string       `inject:"config:core.oauth.clientid"`
@@ -95,6 +98,7 @@ func (am *AuthManager) Inject(logger flamingo.Logger, router *web.Router, config
 	Enabled             bool         `inject:"config:core.oauth.enabled"`
 }) {
 	am.logger = logger.WithField(flamingo.LogKeyModule, "oauth")
+	am.redactor = redactor
 	am.router = router
 	if config != nil {
 		am.server = config.Server
@@ -167,6 +171,7 @@ func (am *AuthManager) OAuthCtx(ctx context.Context) context.Context {
 		oauthHTTPClient := &http.Client{
 			Transport: &loggingRoundTripper{
 				originalTransport: http.DefaultTransport,
+				redactor:          am.redactor,
 			},
 		}
 		return context.WithValue(ctx, oauth2.HTTPClient, oauthHTTPClient)
@@ -12,6 +12,7 @@ import (
 	"flamingo.me/flamingo/v3/core/security/application/role"
 	"flamingo.me/flamingo/v3/framework/config"
 	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/redact"
 	"flamingo.me/flamingo/v3/framework/web"
 )
 
@@ -121,10 +122,11 @@ func (*Module) FlamingoLegacyConfigAlias() map[string]string {
 	return alias
 }
 
-// Depends on the session module
+// Depends on the session and redact modules
 func (*Module) Depends() []dingo.Module {
 	return []dingo.Module{
 		new(flamingo.SessionModule),
+		new(redact.Module),
 	}
 }
 
@@ -0,0 +1,35 @@
+package redirects
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/spf13/cobra"
+)
+
+// Cmd tests which redirect rule a URL hits
+func Cmd(redirector *Redirector) *cobra.Command {
+	return &cobra.Command{
+		Use:   "redirects [url...]",
+		Short: "Test which redirect rule the given URLs hit",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, arg := range args {
+				u, err := url.Parse(arg)
+				if err != nil {
+					return err
+				}
+
+				match := redirector.Lookup(u)
+				if match == nil {
+					fmt.Fprintf(cmd.OutOrStdout(), "%s: no redirect\n", arg)
+					continue
+				}
+
+				fmt.Fprintf(cmd.OutOrStdout(), "%s: %d %s\n  rule: %s\n", arg, match.Code, match.Location, match.Rule)
+			}
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,63 @@
+package redirects
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"flamingo.me/flamingo/v3/framework/web"
+)
+
+type (
+	// Filter redirects matching requests before the controller is called
+	Filter struct {
+		redirector *Redirector
+		responder  *web.Responder
+	}
+
+	// OptionalHandler redirects matching requests in the prefixrouter, before any area is routed
+	OptionalHandler struct {
+		redirector *Redirector
+	}
+)
+
+// Inject dependencies
+func (f *Filter) Inject(redirector *Redirector, responder *web.Responder) *Filter {
+	f.redirector = redirector
+	f.responder = responder
+	return f
+}
+
+// Filter checks the request against the redirect rules
+func (f *Filter) Filter(ctx context.Context, r *web.Request, w http.ResponseWriter, chain *web.FilterChain) web.Result {
+	match := f.redirector.Lookup(r.Request().URL)
+	if match == nil {
+		return chain.Next(ctx, r, w)
+	}
+
+	location, err := url.Parse(match.Location)
+	if err != nil {
+		return chain.Next(ctx, r, w)
+	}
+
+	response := f.responder.URLRedirect(location)
+	response.Status = uint(match.Code)
+	return response
+}
+
+// Inject dependencies
+func (h *OptionalHandler) Inject(redirector *Redirector) *OptionalHandler {
+	h.redirector = redirector
+	return h
+}
+
+// TryServeHTTP implementation to be used in prefixrouter & co
+func (h *OptionalHandler) TryServeHTTP(rw http.ResponseWriter, req *http.Request) (bool, error) {
+	match := h.redirector.Lookup(req.URL)
+	if match == nil {
+		return true, nil
+	}
+
+	http.Redirect(rw, req, match.Location, match.Code)
+	return false, nil
+}
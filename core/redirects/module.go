@@ -0,0 +1,58 @@
+// Package redirects serves redirects for legacy URLs from rules in the configuration and in YAML or CSV files.
+package redirects
+
+import (
+	"flamingo.me/dingo"
+	"flamingo.me/flamingo/v3/framework/prefixrouter"
+	"flamingo.me/flamingo/v3/framework/web"
+	"github.com/spf13/cobra"
+)
+
+// Module for core/redirects
+type Module struct {
+	filter       bool
+	prefixrouter bool
+}
+
+// Inject dependencies
+func (m *Module) Inject(config *struct {
+	Filter       bool `inject:"config:core.redirects.filter"`
+	Prefixrouter bool `inject:"config:core.redirects.prefixrouter"`
+}) *Module {
+	m.filter = config.Filter
+	m.prefixrouter = config.Prefixrouter
+	return m
+}
+
+// Configure DI
+func (m *Module) Configure(injector *dingo.Injector) {
+	injector.Bind(new(Redirector)).In(dingo.ChildSingleton)
+	injector.BindMulti(new(cobra.Command)).ToProvider(Cmd)
+
+	if m.filter {
+		injector.BindMulti(new(web.Filter)).To(new(Filter))
+	}
+
+	if m.prefixrouter {
+		injector.BindMulti(new(prefixrouter.OptionalHandler)).AnnotatedWith("primaryHandlers").To(new(OptionalHandler))
+	}
+}
+
+// CueConfig schema and defaults
+func (m *Module) CueConfig() string {
+	// language=cue
+	return `
+core: redirects: {
+	filter: bool | *true
+	prefixrouter: bool | *false
+	files: [...string]
+	rules: [...{
+		from: string
+		to: string
+		match: *"exact" | "prefix" | "pattern"
+		code: *301 | 302 | 307 | 308
+		query: *"keep" | "drop"
+	}]
+}
+`
+}
@@ -0,0 +1,24 @@
+package redirects_test
+
+import (
+	"testing"
+
+	"flamingo.me/flamingo/v3/core/redirects"
+	"flamingo.me/flamingo/v3/framework/config"
+)
+
+func TestModule_Configure(t *testing.T) {
+	if err := config.TryModules(nil, new(redirects.Module)); err != nil {
+		t.Error(err)
+	}
+
+	if err := config.TryModules(config.Map{
+		"core.redirects.prefixrouter": true,
+		"core.redirects.rules": config.Slice{
+			config.Map{"from": "/old", "to": "/new"},
+			config.Map{"from": "/legacy/", "to": "/", "match": "prefix", "code": 308},
+		},
+	}, new(redirects.Module)); err != nil {
+		t.Error(err)
+	}
+}
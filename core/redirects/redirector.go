@@ -0,0 +1,178 @@
+package redirects
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"github.com/ghodss/yaml"
+)
+
+// Redirector holds the indexed redirect rules of an area
+type Redirector struct {
+	logger flamingo.Logger
+	index  *index
+}
+
+// Inject dependencies and load the rules from the configuration and the rule files
+func (r *Redirector) Inject(logger flamingo.Logger, cfg *struct {
+	Rules config.Slice `inject:"config:core.redirects.rules"`
+	Files config.Slice `inject:"config:core.redirects.files"`
+}) *Redirector {
+	r.logger = logger.WithField(flamingo.LogKeyModule, "core.redirects").WithField(flamingo.LogKeyCategory, "redirector")
+	r.index = newIndex()
+
+	var rules []*Rule
+	if err := cfg.Rules.MapInto(&rules); err != nil {
+		r.logger.Error("invalid redirect rules: ", err)
+	}
+	r.add(rules, "config")
+
+	var files []string
+	if err := cfg.Files.MapInto(&files); err != nil {
+		r.logger.Error("invalid redirect files: ", err)
+	}
+	for _, file := range files {
+		rules, err := LoadFile(file)
+		if err != nil {
+			r.logger.Error(err)
+			continue
+		}
+		r.add(rules, file)
+	}
+
+	r.logger.Info(fmt.Sprintf("loaded %d redirect rules", r.index.len()))
+
+	return r
+}
+
+func (r *Redirector) add(rules []*Rule, source string) {
+	for _, rule := range rules {
+		rule.source = source
+		if err := rule.compile(); err != nil {
+			r.logger.Warn(err)
+			continue
+		}
+		r.index.add(rule)
+	}
+}
+
+// Lookup returns the redirect for a request URL, or nil if no rule matches
+func (r *Redirector) Lookup(u *url.URL) *Match {
+	if r.index == nil {
+		return nil
+	}
+
+	rule := r.index.lookup(u)
+	if rule == nil {
+		return nil
+	}
+
+	location, err := rule.target(u)
+	if err != nil {
+		r.logger.Warn(fmt.Sprintf("redirect rule %s: %v", rule, err))
+		return nil
+	}
+
+	// do not redirect in a loop
+	if location == u.RequestURI() {
+		return nil
+	}
+
+	return &Match{Rule: rule, Location: location, Code: rule.Code}
+}
+
+// LoadFile loads redirect rules from a YAML (.yml, .yaml) or CSV (.csv) file.
+// CSV files need a header row with the columns from, to and optionally match, code and query.
+func LoadFile(file string) ([]*Rule, error) {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yml", ".yaml":
+		return loadYAML(file)
+	case ".csv":
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		rules, err := readCSV(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		return rules, nil
+	}
+
+	return nil, fmt.Errorf("%s: unsupported redirect file type", file)
+}
+
+func loadYAML(file string) ([]*Rule, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []*Rule
+	if err := yaml.Unmarshal(b, &rules); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	return rules, nil
+}
+
+func readCSV(r io.Reader) ([]*Rule, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["from"]; !ok {
+		return nil, errors.New("missing column from")
+	}
+	if _, ok := columns["to"]; !ok {
+		return nil, errors.New("missing column to")
+	}
+
+	var rules []*Rule
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rules, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		value := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		rule := &Rule{From: value("from"), To: value("to"), Match: value("match"), Query: value("query")}
+		if code := value("code"); code != "" {
+			if rule.Code, err = strconv.Atoi(code); err != nil {
+				return nil, fmt.Errorf("rule %s: invalid code %q", rule.From, code)
+			}
+		}
+		rules = append(rules, rule)
+	}
+}
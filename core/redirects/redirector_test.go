@@ -0,0 +1,84 @@
+package redirects
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+)
+
+func testRedirector(t *testing.T, rules config.Slice) *Redirector {
+	t.Helper()
+
+	return new(Redirector).Inject(flamingo.NullLogger{}, &struct {
+		Rules config.Slice `inject:"config:core.redirects.rules"`
+		Files config.Slice `inject:"config:core.redirects.files"`
+	}{Rules: rules})
+}
+
+func TestRedirector_Lookup(t *testing.T) {
+	redirector := testRedirector(t, config.Slice{
+		config.Map{"from": "/old-page", "to": "/new-page"},
+		config.Map{"from": "/index.php?id=5", "to": "/product/5", "code": 302.0},
+		config.Map{"from": "/index.php", "to": "/"},
+		config.Map{"from": "/shop/", "to": "/store/", "match": "prefix"},
+		config.Map{"from": "/shop/outlet/", "to": "/sale/", "match": "prefix", "query": "drop"},
+		config.Map{"from": "/old", "to": "/new", "match": "prefix"},
+		config.Map{"from": `/p/(?P<sku>[0-9]+)-.*`, "to": "/product/${sku}", "match": "pattern", "code": 308.0},
+		config.Map{"from": "/loop", "to": "/loop"},
+		config.Map{"from": "/invalid", "to": "/x", "code": 200.0},
+	})
+
+	for path, want := range map[string]*Match{
+		"/old-page":                 {Location: "/new-page", Code: 301},
+		"/old-page?utm=x":           {Location: "/new-page?utm=x", Code: 301},
+		"/index.php?id=5&utm=x":     {Location: "/product/5?utm=x", Code: 302},
+		"/index.php?id=6":           {Location: "/?id=6", Code: 301},
+		"/shop/shoes/red?size=42":   {Location: "/store/shoes/red?size=42", Code: 301},
+		"/shop/outlet/shoes?size=1": {Location: "/sale/shoes", Code: 301},
+		"/old":                      {Location: "/new", Code: 301},
+		"/old/page":                 {Location: "/new/page", Code: 301},
+		"/oldies":                   nil,
+		"/p/1234-red-shoes":         {Location: "/product/1234", Code: 308},
+		"/p/red-shoes":              nil,
+		"/loop":                     nil,
+		"/invalid":                  nil,
+		"/unknown":                  nil,
+	} {
+		u, err := url.Parse(path)
+		require.NoError(t, err)
+
+		match := redirector.Lookup(u)
+		if want == nil {
+			assert.Nil(t, match, path)
+			continue
+		}
+
+		require.NotNil(t, match, path)
+		assert.Equal(t, want.Location, match.Location, path)
+		assert.Equal(t, want.Code, match.Code, path)
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	rules, err := readCSV(strings.NewReader(`from,to,code,match
+# legacy category pages
+/old,/new,,
+/cat/,/category/,302,prefix
+`))
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	assert.Equal(t, &Rule{From: "/old", To: "/new"}, rules[0])
+	assert.Equal(t, &Rule{From: "/cat/", To: "/category/", Code: 302, Match: "prefix"}, rules[1])
+
+	_, err = readCSV(strings.NewReader("source,target\n/a,/b\n"))
+	assert.Error(t, err)
+
+	_, err = readCSV(strings.NewReader("from,to,code\n/a,/b,moved\n"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,239 @@
+package redirects
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+type (
+	// Rule defines a redirect from a path to a target
+	Rule struct {
+		// From is the path (exact), the path prefix (prefix) or a regular expression (pattern).
+		// Exact rules can contain a query, the request must then contain the given query parameters.
+		From string `json:"from"`
+		// To is the target path or URL, pattern rules can reference captures with $1 or ${name}
+		To string `json:"to"`
+		// Match is one of exact, prefix or pattern
+		Match string `json:"match"`
+		// Code is the HTTP status code, one of 301, 302, 307 or 308
+		Code int `json:"code"`
+		// Query is either keep (request query parameters are passed on) or drop
+		Query string `json:"query"`
+
+		source  string
+		path    string
+		query   url.Values
+		pattern *regexp.Regexp
+	}
+
+	// Match is the result of a successful lookup
+	Match struct {
+		Rule     *Rule
+		Location string
+		Code     int
+	}
+
+	// index allows a fast lookup of thousands of rules
+	index struct {
+		exact    map[string][]*Rule
+		prefix   map[string]*Rule
+		patterns []*Rule
+	}
+)
+
+const (
+	// MatchExact matches the full path
+	MatchExact = "exact"
+	// MatchPrefix matches a path prefix, the remaining path is appended to the target
+	MatchPrefix = "prefix"
+	// MatchPattern matches a regular expression against the path
+	MatchPattern = "pattern"
+
+	// QueryKeep passes the request query on to the target
+	QueryKeep = "keep"
+	// QueryDrop drops the request query
+	QueryDrop = "drop"
+)
+
+// ErrInvalidRule is returned for rules which can not be used
+var ErrInvalidRule = errors.New("invalid redirect rule")
+
+// compile validates the rule and sets the defaults
+func (r *Rule) compile() error {
+	if r.Match == "" {
+		r.Match = MatchExact
+	}
+	if r.Code == 0 {
+		r.Code = http.StatusMovedPermanently
+	}
+	if r.Query == "" {
+		r.Query = QueryKeep
+	}
+
+	if r.From == "" || r.To == "" {
+		return fmt.Errorf("%w %s: from and to are required", ErrInvalidRule, r)
+	}
+
+	switch r.Code {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+	default:
+		return fmt.Errorf("%w %s: unsupported code %d", ErrInvalidRule, r, r.Code)
+	}
+
+	if r.Query != QueryKeep && r.Query != QueryDrop {
+		return fmt.Errorf("%w %s: unsupported query handling %q", ErrInvalidRule, r, r.Query)
+	}
+
+	switch r.Match {
+	case MatchExact:
+		u, err := url.Parse(r.From)
+		if err != nil {
+			return fmt.Errorf("%w %s: %v", ErrInvalidRule, r, err)
+		}
+		r.path = u.Path
+		r.query = u.Query()
+	case MatchPrefix:
+		r.path = r.From
+	case MatchPattern:
+		pattern, err := regexp.Compile("^" + strings.TrimPrefix(r.From, "^"))
+		if err != nil {
+			return fmt.Errorf("%w %s: %v", ErrInvalidRule, r, err)
+		}
+		r.pattern = pattern
+	default:
+		return fmt.Errorf("%w %s: unsupported match %q", ErrInvalidRule, r, r.Match)
+	}
+
+	return nil
+}
+
+// String describes the rule for logs and the redirects command
+func (r *Rule) String() string {
+	s := fmt.Sprintf("%s %s -> %s (%d)", r.Match, r.From, r.To, r.Code)
+	if r.source != "" {
+		s += " from " + r.source
+	}
+	return s
+}
+
+// queryMatches checks that all query parameters of an exact rule are part of the request query
+func (r *Rule) queryMatches(query url.Values) bool {
+	for k, values := range r.query {
+		if len(values) == 0 || query.Get(k) != values[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// target builds the redirect location for the request URL
+func (r *Rule) target(u *url.URL) (string, error) {
+	var target string
+	switch r.Match {
+	case MatchExact:
+		target = r.To
+	case MatchPrefix:
+		target = r.To + strings.TrimPrefix(u.Path, r.path)
+	case MatchPattern:
+		submatches := r.pattern.FindStringSubmatchIndex(u.Path)
+		target = string(r.pattern.ExpandString(nil, r.To, u.Path, submatches))
+	}
+
+	location, err := url.Parse(target)
+	if err != nil {
+		return "", err
+	}
+
+	if r.Query == QueryKeep {
+		query := u.Query()
+		for k := range r.query {
+			query.Del(k)
+		}
+		for k, v := range location.Query() {
+			query[k] = v
+		}
+		location.RawQuery = query.Encode()
+	}
+
+	return location.String(), nil
+}
+
+func newIndex() *index {
+	return &index{
+		exact:  make(map[string][]*Rule),
+		prefix: make(map[string]*Rule),
+	}
+}
+
+// add a compiled rule, the first rule for a path or prefix wins
+func (i *index) add(rule *Rule) {
+	switch rule.Match {
+	case MatchExact:
+		i.exact[rule.path] = append(i.exact[rule.path], rule)
+	case MatchPrefix:
+		if _, ok := i.prefix[rule.path]; !ok {
+			i.prefix[rule.path] = rule
+		}
+	case MatchPattern:
+		i.patterns = append(i.patterns, rule)
+	}
+}
+
+// lookup finds the rule for a request URL: exact rules win over the longest prefix, patterns are checked last in order.
+// Prefixes match at path segment boundaries.
+func (i *index) lookup(u *url.URL) *Rule {
+	if rules, ok := i.exact[u.Path]; ok {
+		query := u.Query()
+		// rules with query conditions are more specific
+		var fallback *Rule
+		for _, rule := range rules {
+			if len(rule.query) == 0 {
+				if fallback == nil {
+					fallback = rule
+				}
+				continue
+			}
+			if rule.queryMatches(query) {
+				return rule
+			}
+		}
+		if fallback != nil {
+			return fallback
+		}
+	}
+
+	if len(i.prefix) > 0 {
+		for l := len(u.Path); l > 0; l-- {
+			// a prefix matches whole path segments only: /old matches /old and /old/page, but not /oldies
+			if l < len(u.Path) && u.Path[l] != '/' && u.Path[l-1] != '/' {
+				continue
+			}
+			if rule, ok := i.prefix[u.Path[:l]]; ok {
+				return rule
+			}
+		}
+	}
+
+	for _, rule := range i.patterns {
+		if prefix, _ := rule.pattern.LiteralPrefix(); !strings.HasPrefix(u.Path, prefix) {
+			continue
+		}
+		if rule.pattern.MatchString(u.Path) {
+			return rule
+		}
+	}
+
+	return nil
+}
+
+func (i *index) len() int {
+	n := len(i.prefix) + len(i.patterns)
+	for _, rules := range i.exact {
+		n += len(rules)
+	}
+	return n
+}
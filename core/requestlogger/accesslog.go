@@ -0,0 +1,71 @@
+package requestlogger
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/logfile"
+)
+
+// accessLog is the output of the access log, separate from the application log
+type accessLog struct {
+	logger  flamingo.Logger
+	output  string
+	options logfile.Options
+	once    sync.Once
+	writer  logfile.Writer
+}
+
+// Inject dependencies
+func (a *accessLog) Inject(logger flamingo.Logger, cfg *struct {
+	Output     string  `inject:"config:core.requestlogger.output"`
+	MaxSize    float64 `inject:"config:core.requestlogger.rotate.maxSize"`
+	MaxBackups float64 `inject:"config:core.requestlogger.rotate.maxBackups"`
+}) *accessLog {
+	a.logger = logger.WithField(flamingo.LogKeyModule, "core.requestlogger").WithField(flamingo.LogKeyCategory, "accesslog")
+	a.output = cfg.Output
+	a.options = logfile.Options{
+		MaxSize:    int64(cfg.MaxSize) * 1024 * 1024,
+		MaxBackups: int(cfg.MaxBackups),
+	}
+
+	return a
+}
+
+// open the output on the first write, so the legacy format does not create files
+func (a *accessLog) open() logfile.Writer {
+	a.once.Do(func() {
+		writer, err := logfile.Open(a.output, a.options)
+		if err != nil {
+			a.logger.Error(fmt.Sprintf("opening the access log %q failed, using stdout: %v", a.output, err))
+			writer, _ = logfile.Open("stdout", logfile.Options{})
+		}
+		a.writer = writer
+	})
+
+	return a.writer
+}
+
+func (a *accessLog) write(line []byte) {
+	if _, err := a.open().Write(line); err != nil {
+		// the access log must not fail requests, and must not end up in the application log
+		fmt.Fprintln(os.Stderr, "core.requestlogger: writing the access log failed:", err)
+	}
+}
+
+// Notify syncs the output on shutdown
+func (a *accessLog) Notify(_ context.Context, event flamingo.Event) {
+	if _, ok := event.(*flamingo.ShutdownEvent); !ok {
+		return
+	}
+
+	a.once.Do(func() {})
+	if a.writer != nil {
+		if err := a.writer.Sync(); err != nil {
+			a.logger.Warn("syncing the access log failed: ", err)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package requestlogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type (
+	// entry is an access log entry
+	entry struct {
+		Time            time.Time
+		Method          string
+		URI             string
+		Proto           string
+		Host            string
+		Status          int
+		Bytes           int
+		Latency         time.Duration
+		RemoteIP        string
+		Referer         string
+		UserAgent       string
+		Handler         string
+		Subject         string
+		TraceID         string
+		CacheStatus     string
+		RequestHeaders  map[string]string
+		ResponseHeaders map[string]string
+	}
+
+	// formatter renders an entry to a log line including the line break
+	formatter interface {
+		format(e *entry) []byte
+	}
+
+	clfFormatter struct {
+		combined bool
+	}
+
+	jsonFormatter struct {
+		fields []string
+	}
+)
+
+// Formats of the access log
+const (
+	FormatLegacy   = "legacy"
+	FormatCommon   = "common"
+	FormatCombined = "combined"
+	FormatJSON     = "json"
+)
+
+const (
+	clfTimeLayout = "02/Jan/2006:15:04:05 -0700"
+	redacted      = "[REDACTED]"
+)
+
+// fieldValues of the JSON format
+var fieldValues = map[string]func(e *entry) interface{}{
+	"time":             func(e *entry) interface{} { return e.Time.Format(time.RFC3339Nano) },
+	"method":           func(e *entry) interface{} { return e.Method },
+	"uri":              func(e *entry) interface{} { return e.URI },
+	"proto":            func(e *entry) interface{} { return e.Proto },
+	"host":             func(e *entry) interface{} { return e.Host },
+	"status":           func(e *entry) interface{} { return e.Status },
+	"bytes":            func(e *entry) interface{} { return e.Bytes },
+	"latency":          func(e *entry) interface{} { return float64(e.Latency.Microseconds()) / 1000 },
+	"remote_ip":        func(e *entry) interface{} { return e.RemoteIP },
+	"referer":          func(e *entry) interface{} { return e.Referer },
+	"user_agent":       func(e *entry) interface{} { return e.UserAgent },
+	"handler":          func(e *entry) interface{} { return e.Handler },
+	"subject":          func(e *entry) interface{} { return e.Subject },
+	"trace_id":         func(e *entry) interface{} { return e.TraceID },
+	"cache_status":     func(e *entry) interface{} { return e.CacheStatus },
+	"request_headers":  func(e *entry) interface{} { return e.RequestHeaders },
+	"response_headers": func(e *entry) interface{} { return e.ResponseHeaders },
+}
+
+// newFormatter returns the formatter of the format, JSON fields must be known
+func newFormatter(format string, fields []string) (formatter, error) {
+	switch format {
+	case FormatCommon:
+		return &clfFormatter{}, nil
+	case FormatCombined:
+		return &clfFormatter{combined: true}, nil
+	case FormatJSON:
+		for _, field := range fields {
+			if _, ok := fieldValues[field]; !ok {
+				return nil, fmt.Errorf("unknown access log field %q", field)
+			}
+		}
+		return &jsonFormatter{fields: fields}, nil
+	}
+
+	return nil, fmt.Errorf("unknown access log format %q", format)
+}
+
+// format a line of the Common Log Format, or the Combined Log Format with referer and user agent
+func (f *clfFormatter) format(e *entry) []byte {
+	b := new(bytes.Buffer)
+
+	fmt.Fprintf(b, "%s - %s [%s] %s %d %s",
+		dash(e.RemoteIP),
+		dash(e.Subject),
+		e.Time.Format(clfTimeLayout),
+		strconv.Quote(e.Method+" "+e.URI+" "+e.Proto),
+		e.Status,
+		dash(bytesString(e.Bytes)),
+	)
+	if f.combined {
+		fmt.Fprintf(b, " %s %s", strconv.Quote(dash(e.Referer)), strconv.Quote(dash(e.UserAgent)))
+	}
+	b.WriteByte('\n')
+
+	return b.Bytes()
+}
+
+// format a JSON object with the configured fields, empty strings are omitted
+func (f *jsonFormatter) format(e *entry) []byte {
+	b := new(bytes.Buffer)
+	b.WriteByte('{')
+
+	first := true
+	for _, field := range f.fields {
+		value := fieldValues[field](e)
+		switch v := value.(type) {
+		case string:
+			if v == "" {
+				continue
+			}
+		case map[string]string:
+			if len(v) == 0 {
+				continue
+			}
+		}
+
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			continue
+		}
+		if !first {
+			b.WriteByte(',')
+		}
+		first = false
+
+		key, _ := json.Marshal(field)
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(encoded)
+	}
+
+	b.WriteString("}\n")
+
+	return b.Bytes()
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func bytesString(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+// captureHeaders copies the configured headers, redacted headers are replaced
+func captureHeaders(header http.Header, names []string, redact map[string]bool) map[string]string {
+	if len(names) == 0 {
+		return nil
+	}
+
+	captured := make(map[string]string, len(names))
+	for _, name := range names {
+		name = http.CanonicalHeaderKey(name)
+		value := header.Get(name)
+		if value == "" {
+			continue
+		}
+		if redact[name] {
+			value = redacted
+		}
+		captured[name] = value
+	}
+
+	return captured
+}
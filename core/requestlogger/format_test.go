@@ -0,0 +1,71 @@
+package requestlogger
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testEntry() *entry {
+	return &entry{
+		Time:      time.Date(2020, time.March, 10, 13, 55, 36, 0, time.FixedZone("CET", 3600)),
+		Method:    http.MethodGet,
+		URI:       "/product/1?color=red",
+		Proto:     "HTTP/1.1",
+		Status:    http.StatusOK,
+		Bytes:     2326,
+		Latency:   1500 * time.Microsecond,
+		RemoteIP:  "127.0.0.1",
+		Referer:   "http://example.com/",
+		UserAgent: `Mozilla/4.08 "test"`,
+		Handler:   "product.view",
+		TraceID:   "4bf92f3577b34da6a3ce929d0e0e4736",
+		RequestHeaders: map[string]string{
+			"Accept":        "text/html",
+			"Authorization": redacted,
+		},
+	}
+}
+
+func TestFormatters(t *testing.T) {
+	common, err := newFormatter(FormatCommon, nil)
+	require.NoError(t, err)
+	assert.Equal(t,
+		`127.0.0.1 - - [10/Mar/2020:13:55:36 +0100] "GET /product/1?color=red HTTP/1.1" 200 2326`+"\n",
+		string(common.format(testEntry())))
+
+	combined, err := newFormatter(FormatCombined, nil)
+	require.NoError(t, err)
+	e := testEntry()
+	e.Subject = "user-1"
+	e.Bytes = 0
+	assert.Equal(t,
+		`127.0.0.1 - user-1 [10/Mar/2020:13:55:36 +0100] "GET /product/1?color=red HTTP/1.1" 200 - "http://example.com/" "Mozilla/4.08 \"test\""`+"\n",
+		string(combined.format(e)))
+
+	jsonFormat, err := newFormatter(FormatJSON, []string{"method", "status", "latency", "handler", "subject", "trace_id", "request_headers", "response_headers"})
+	require.NoError(t, err)
+	assert.JSONEq(t,
+		`{"method":"GET","status":200,"latency":1.5,"handler":"product.view","trace_id":"4bf92f3577b34da6a3ce929d0e0e4736","request_headers":{"Accept":"text/html","Authorization":"[REDACTED]"}}`,
+		string(jsonFormat.format(testEntry())))
+
+	_, err = newFormatter(FormatJSON, []string{"password"})
+	assert.Error(t, err)
+	_, err = newFormatter("apache", nil)
+	assert.Error(t, err)
+}
+
+func TestCaptureHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set("Accept", "text/html")
+	header.Set("Authorization", "Bearer secret")
+	header.Set("X-Other", "1")
+
+	assert.Equal(t,
+		map[string]string{"Accept": "text/html", "Authorization": redacted},
+		captureHeaders(header, []string{"accept", "authorization", "x-missing"}, map[string]bool{"Authorization": true}))
+	assert.Nil(t, captureHeaders(header, nil, nil))
+}
@@ -3,20 +3,38 @@ package requestlogger
 import (
 	"context"
 	"fmt"
+	"math/rand"
+	"net"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
+	"flamingo.me/flamingo/v3/core/auth"
+	"flamingo.me/flamingo/v3/framework/config"
 	"flamingo.me/flamingo/v3/framework/flamingo"
 	"flamingo.me/flamingo/v3/framework/web"
 	"github.com/labstack/gommon/color"
+	"go.opencensus.io/trace"
 )
 
 type (
 	logger struct {
-		logger    flamingo.Logger
-		responder *web.Responder
+		logger      flamingo.Logger
+		responder   *web.Responder
+		accessLog   *accessLog
+		identifiers []auth.RequestIdentifier
+		format      string
+		formatter   formatter
+		exclude     []string
+		sampleRate  float64
+		random      func() float64
+		cacheHeader string
+		headers     struct {
+			request  []string
+			response []string
+			redact   map[string]bool
+		}
 	}
 
 	loggedResponse struct {
@@ -60,9 +78,65 @@ func (l *loggedResponse) Apply(ctx context.Context, rw http.ResponseWriter) erro
 	return err
 }
 
-func (r *logger) Inject(flogger flamingo.Logger, responder *web.Responder) {
+// Inject dependencies and configuration
+func (r *logger) Inject(
+	flogger flamingo.Logger,
+	responder *web.Responder,
+	accessLog *accessLog,
+	identifiers *struct {
+		Identifiers []auth.RequestIdentifier `inject:",optional"`
+	},
+	cfg *struct {
+		Format            string       `inject:"config:core.requestlogger.format"`
+		Fields            config.Slice `inject:"config:core.requestlogger.fields"`
+		Exclude           config.Slice `inject:"config:core.requestlogger.exclude"`
+		SampleRate        float64      `inject:"config:core.requestlogger.sampleRate"`
+		CacheStatusHeader string       `inject:"config:core.requestlogger.cacheStatusHeader"`
+		RequestHeaders    config.Slice `inject:"config:core.requestlogger.headers.request"`
+		ResponseHeaders   config.Slice `inject:"config:core.requestlogger.headers.response"`
+		RedactHeaders     config.Slice `inject:"config:core.requestlogger.headers.redact"`
+	},
+) *logger {
 	r.logger = flogger
 	r.responder = responder
+	r.accessLog = accessLog
+	r.identifiers = identifiers.Identifiers
+	r.format = cfg.Format
+	r.sampleRate = cfg.SampleRate
+	r.random = rand.Float64
+	r.cacheHeader = cfg.CacheStatusHeader
+
+	var fields, redact []string
+	for _, list := range []struct {
+		slice config.Slice
+		into  *[]string
+	}{
+		{cfg.Fields, &fields},
+		{cfg.Exclude, &r.exclude},
+		{cfg.RequestHeaders, &r.headers.request},
+		{cfg.ResponseHeaders, &r.headers.response},
+		{cfg.RedactHeaders, &redact},
+	} {
+		if err := list.slice.MapInto(list.into); err != nil {
+			r.logger.Error("invalid core.requestlogger config: ", err)
+		}
+	}
+
+	r.headers.redact = make(map[string]bool, len(redact))
+	for _, name := range redact {
+		r.headers.redact[http.CanonicalHeaderKey(name)] = true
+	}
+
+	if r.format != FormatLegacy && r.format != "" {
+		formatter, err := newFormatter(r.format, fields)
+		if err != nil {
+			r.logger.Error(err, ", using the legacy format")
+			r.format = FormatLegacy
+		}
+		r.formatter = formatter
+	}
+
+	return r
 }
 
 func humanBytes(bc int) string {
@@ -89,11 +163,24 @@ func statusCodeColor(code int) func(msg interface{}, styles ...string) string {
 
 // Filter a web request
 func (r *logger) Filter(ctx context.Context, req *web.Request, w http.ResponseWriter, chain *web.FilterChain) web.Result {
+	if r.excluded(req.Request().URL.Path) {
+		return chain.Next(ctx, req, w)
+	}
+
 	start := time.Now()
 
 	webResponse := chain.Next(ctx, req, w)
 
 	logCallbackFunc := func(rwl *responseWriterLogger) {
+		if !r.sampled(rwl.statusCode) {
+			return
+		}
+
+		if r.formatter != nil {
+			r.accessLog.write(r.formatter.format(r.entry(ctx, req, rwl, start)))
+			return
+		}
+
 		cp := statusCodeColor(rwl.statusCode)
 		extra := new(strings.Builder)
 
@@ -147,3 +234,68 @@ func (r *logger) Filter(ctx context.Context, req *web.Request, w http.ResponseWr
 		logCallback: logCallbackFunc,
 	}
 }
+
+// excluded paths are not logged
+func (r *logger) excluded(path string) bool {
+	for _, prefix := range r.exclude {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// sampled decides if a request is logged, server errors are always logged
+func (r *logger) sampled(status int) bool {
+	if r.sampleRate >= 1 || status >= http.StatusInternalServerError {
+		return true
+	}
+	return r.random() < r.sampleRate
+}
+
+// entry collects the access log entry of a request
+func (r *logger) entry(ctx context.Context, req *web.Request, rwl *responseWriterLogger, start time.Time) *entry {
+	request := req.Request()
+
+	e := &entry{
+		Time:            start,
+		Method:          request.Method,
+		URI:             request.RequestURI,
+		Proto:           request.Proto,
+		Host:            request.Host,
+		Status:          rwl.statusCode,
+		Bytes:           rwl.length,
+		Latency:         time.Since(start),
+		Referer:         request.Referer(),
+		UserAgent:       request.UserAgent(),
+		RequestHeaders:  captureHeaders(request.Header, r.headers.request, r.headers.redact),
+		ResponseHeaders: captureHeaders(rwl.Header(), r.headers.response, r.headers.redact),
+	}
+
+	if e.URI == "" {
+		e.URI = request.URL.RequestURI()
+	}
+	if remote := req.RemoteAddress(); len(remote) > 0 {
+		e.RemoteIP = remote[0]
+		if host, _, err := net.SplitHostPort(e.RemoteIP); err == nil {
+			e.RemoteIP = host
+		}
+	}
+	if handler := req.Handler(); handler != nil {
+		e.Handler = handler.GetHandlerName()
+	}
+	if span := trace.FromContext(ctx); span != nil {
+		e.TraceID = span.SpanContext().TraceID.String()
+	}
+	if r.cacheHeader != "" {
+		e.CacheStatus = rwl.Header().Get(r.cacheHeader)
+	}
+	for _, identifier := range r.identifiers {
+		if identity, _ := identifier.Identify(ctx, req); identity != nil {
+			e.Subject = identity.Subject()
+			break
+		}
+	}
+
+	return e
+}
@@ -0,0 +1,97 @@
+package requestlogger
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"flamingo.me/flamingo/v3/core/auth"
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/web"
+)
+
+func TestLogger_JSONFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "requestlogger")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	output := filepath.Join(dir, "access.log")
+
+	accessLog := new(accessLog).Inject(flamingo.NullLogger{}, &struct {
+		Output     string  `inject:"config:core.requestlogger.output"`
+		MaxSize    float64 `inject:"config:core.requestlogger.rotate.maxSize"`
+		MaxBackups float64 `inject:"config:core.requestlogger.rotate.maxBackups"`
+	}{Output: output})
+
+	l := new(logger).Inject(flamingo.NullLogger{}, nil, accessLog, &struct {
+		Identifiers []auth.RequestIdentifier `inject:",optional"`
+	}{}, &struct {
+		Format            string       `inject:"config:core.requestlogger.format"`
+		Fields            config.Slice `inject:"config:core.requestlogger.fields"`
+		Exclude           config.Slice `inject:"config:core.requestlogger.exclude"`
+		SampleRate        float64      `inject:"config:core.requestlogger.sampleRate"`
+		CacheStatusHeader string       `inject:"config:core.requestlogger.cacheStatusHeader"`
+		RequestHeaders    config.Slice `inject:"config:core.requestlogger.headers.request"`
+		ResponseHeaders   config.Slice `inject:"config:core.requestlogger.headers.response"`
+		RedactHeaders     config.Slice `inject:"config:core.requestlogger.headers.redact"`
+	}{
+		Format:            FormatJSON,
+		Fields:            config.Slice{"method", "uri", "status", "bytes", "remote_ip", "cache_status", "request_headers"},
+		Exclude:           config.Slice{"/health"},
+		SampleRate:        0.5,
+		CacheStatusHeader: "X-Cache",
+		RequestHeaders:    config.Slice{"Accept", "Cookie"},
+		RedactHeaders:     config.Slice{"cookie"},
+	})
+
+	random := 0.9
+	l.random = func() float64 { return random }
+
+	serve := func(path string, status int) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept", "text/html")
+		req.Header.Set("Cookie", "session=secret")
+		r := web.CreateRequest(req, nil)
+
+		chain := web.NewFilterChain(func(ctx context.Context, r *web.Request, w http.ResponseWriter) web.Result {
+			return &web.Response{Status: uint(status), Header: http.Header{"X-Cache": []string{"HIT"}}}
+		}, l)
+		result := chain.Next(context.Background(), r, httptest.NewRecorder())
+		require.NoError(t, result.Apply(context.Background(), httptest.NewRecorder()))
+	}
+
+	serve("/health", http.StatusOK)
+	serve("/sampled-out", http.StatusOK)
+	serve("/error", http.StatusBadGateway)
+	random = 0.1
+	serve("/sampled-in?x=1", http.StatusOK)
+
+	f, err := os.Open(output)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := make(map[string]interface{})
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+
+	require.Len(t, lines, 2)
+	assert.Equal(t, "/error", lines[0]["uri"])
+	assert.Equal(t, float64(http.StatusBadGateway), lines[0]["status"])
+	assert.Equal(t, "/sampled-in?x=1", lines[1]["uri"])
+	assert.Equal(t, "192.0.2.1", lines[1]["remote_ip"])
+	assert.Equal(t, "HIT", lines[1]["cache_status"])
+	assert.Equal(t, map[string]interface{}{"Accept": "text/html", "Cookie": redacted}, lines[1]["request_headers"])
+}
@@ -2,6 +2,7 @@ package requestlogger
 
 import (
 	"flamingo.me/dingo"
+	"flamingo.me/flamingo/v3/framework/flamingo"
 	"flamingo.me/flamingo/v3/framework/web"
 )
 
@@ -12,5 +13,31 @@ type (
 
 // Configure DI
 func (m *Module) Configure(injector *dingo.Injector) {
+	injector.Bind(new(accessLog)).In(dingo.Singleton)
+	flamingo.BindEventSubscriber(injector).ToProvider(func(a *accessLog) *accessLog { return a })
 	injector.BindMulti(new(web.Filter)).To(logger{})
 }
+
+// CueConfig schema and defaults
+func (m *Module) CueConfig() string {
+	// language=cue
+	return `
+core: requestlogger: {
+	format: *"legacy" | "common" | "combined" | "json"
+	output: string | *"stdout"
+	rotate: {
+		maxSize: int | *100
+		maxBackups: int | *5
+	}
+	fields: [...string] | *["time", "method", "uri", "status", "bytes", "latency", "remote_ip", "referer", "user_agent", "handler", "subject", "trace_id", "cache_status", "request_headers", "response_headers"]
+	headers: {
+		request: [...string]
+		response: [...string]
+		redact: [...string] | *["Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key"]
+	}
+	exclude: [...string]
+	sampleRate: float | int | *1
+	cacheStatusHeader: string | *"X-Cache"
+}
+`
+}
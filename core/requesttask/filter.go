@@ -2,57 +2,75 @@ package requesttask
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"net/http"
-	"sync"
+	"time"
 
+	"flamingo.me/flamingo/v3/framework/flamingo"
 	"flamingo.me/flamingo/v3/framework/web"
-	"go.opencensus.io/trace"
 )
 
 type (
-	filter struct{}
-	rKey   string
-)
+	filter struct {
+		logger          flamingo.Logger
+		maxConcurrency  int
+		timeout         time.Duration
+		detachedTimeout time.Duration
+	}
 
-const wg rKey = "requestTaskWg"
+	rKey string
+)
 
-// Do runs a background task in the current request scope
-func Do(ctx context.Context, r *web.Request, task func(ctx context.Context, r *web.Request)) {
-	if err := TryDo(ctx, r, task); err != nil {
-		task(ctx, r)
-	}
-}
+const stateKey rKey = "requestTaskState"
 
-// TryDo tries to schedule an async task in the background
-func TryDo(ctx context.Context, r *web.Request, task func(ctx context.Context, r *web.Request)) error {
-	wg, _ := r.Values.Load(wg)
-	if wg, ok := wg.(*sync.WaitGroup); ok {
-		wg.Add(1)
+// Inject dependencies
+func (f *filter) Inject(logger flamingo.Logger, cfg *struct {
+	MaxConcurrency  float64 `inject:"config:core.requesttask.maxConcurrency"`
+	Timeout         string  `inject:"config:core.requesttask.timeout"`
+	DetachedTimeout string  `inject:"config:core.requesttask.detachedTimeout"`
+}) *filter {
+	f.logger = logger.WithField(flamingo.LogKeyModule, "core.requesttask").WithField(flamingo.LogKeyCategory, "requesttask")
+	f.maxConcurrency = int(cfg.MaxConcurrency)
+	f.timeout = f.duration("timeout", cfg.Timeout)
+	f.detachedTimeout = f.duration("detachedTimeout", cfg.DetachedTimeout)
 
-		go func() {
-			ctx, span := trace.StartSpan(ctx, "requestTask")
-			task(ctx, r)
-			span.End()
-			wg.Done()
-		}()
+	return f
+}
 
-		return nil
+func (f *filter) duration(key, value string) time.Duration {
+	if value == "" {
+		return 0
 	}
 
-	return errors.New("the current request is unable to schedule background tasks")
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		f.logger.Error(fmt.Sprintf("invalid core.requesttask.%s %q, no timeout is used", key, value))
+		return 0
+	}
+	return d
 }
 
-// Filter waits for running tasks to finish before the request processing is done
+// Filter waits for running tasks to finish before the request processing is done,
+// detached tasks are started after the response has been written
 func (f *filter) Filter(ctx context.Context, r *web.Request, w http.ResponseWriter, fc *web.FilterChain) web.Result {
-	r.Values.Store(wg, new(sync.WaitGroup))
+	s := &state{
+		logger:          f.logger,
+		timeout:         f.timeout,
+		detachedTimeout: f.detachedTimeout,
+	}
+	if f.maxConcurrency > 0 {
+		s.semaphore = make(chan struct{}, f.maxConcurrency)
+	}
+
+	r.Values.Store(stateKey, s)
+	fc.AddPostApply(func(error, web.Result) {
+		s.launch()
+	})
+
 	response := fc.Next(ctx, r, w)
 
 	// wait for possible tasks to finish
-	wg, _ := r.Values.Load(wg)
-	if wg, ok := wg.(*sync.WaitGroup); ok {
-		wg.Wait()
-	}
+	s.wg.Wait()
 
 	return response
 }
@@ -12,3 +12,15 @@ type Module struct{}
 func (m *Module) Configure(injector *dingo.Injector) {
 	injector.BindMulti(new(web.Filter)).To(new(filter))
 }
+
+// CueConfig schema and defaults
+func (m *Module) CueConfig() string {
+	// language=cue
+	return `
+core: requesttask: {
+	maxConcurrency: int | *10
+	timeout: string | *"30s"
+	detachedTimeout: string | *"1m"
+}
+`
+}
@@ -0,0 +1,292 @@
+package requesttask
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"go.opencensus.io/trace"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/web"
+)
+
+type (
+	// Group runs tasks of a request concurrently, like an errgroup.
+	// The first failing task cancels the context of the group, Wait returns its error.
+	Group struct {
+		state   *state
+		ctx     context.Context
+		cancel  context.CancelFunc
+		timeout time.Duration
+		wg      sync.WaitGroup
+		errOnce sync.Once
+		err     error
+	}
+
+	// Task is a started task, its result is available after it finished
+	Task struct {
+		done   chan struct{}
+		result interface{}
+		err    error
+	}
+
+	// Option configures the tasks of a Group or a detached task
+	Option func(*options)
+
+	options struct {
+		timeout *time.Duration
+	}
+
+	// state of the tasks of a request, created by the filter
+	state struct {
+		logger          flamingo.Logger
+		semaphore       chan struct{}
+		timeout         time.Duration
+		detachedTimeout time.Duration
+		wg              sync.WaitGroup
+
+		mu       sync.Mutex
+		detached []func()
+		launched bool
+	}
+)
+
+var (
+	// ErrPanic is returned for tasks which panicked
+	ErrPanic = errors.New("request task panicked")
+
+	errNoRequest = errors.New("the current request is unable to schedule background tasks")
+)
+
+// WithTimeout sets the deadline of every task, derived from the request context.
+// It overrides core.requesttask.timeout or core.requesttask.detachedTimeout, 0 disables the deadline.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = &timeout
+	}
+}
+
+// NewGroup creates a group for the tasks of the request, the returned context is cancelled when a task fails.
+// Outside of a request the tasks run without concurrency limit and default timeout.
+func NewGroup(ctx context.Context, r *web.Request, opts ...Option) (*Group, context.Context) {
+	s := stateFrom(r)
+	if s == nil {
+		s = new(state)
+	}
+
+	g := &Group{state: s, timeout: s.timeout}
+	if o := applyOptions(opts); o.timeout != nil {
+		g.timeout = *o.timeout
+	}
+	g.ctx, g.cancel = context.WithCancel(ctx)
+
+	return g, g.ctx
+}
+
+// Go starts a task, it blocks while the concurrency limit of the request is reached
+func (g *Group) Go(task func(ctx context.Context) error) {
+	g.GoResult(func(ctx context.Context) (interface{}, error) {
+		return nil, task(ctx)
+	})
+}
+
+// GoResult starts a task with a result, it blocks while the concurrency limit of the request is reached
+func (g *Group) GoResult(task func(ctx context.Context) (interface{}, error)) *Task {
+	t := &Task{done: make(chan struct{})}
+
+	g.wg.Add(1)
+	g.state.wg.Add(1)
+	acquireErr := g.state.acquire(g.ctx)
+
+	go func() {
+		defer g.state.wg.Done()
+		defer g.wg.Done()
+		defer close(t.done)
+
+		if acquireErr != nil {
+			t.err = acquireErr
+		} else {
+			defer g.state.release()
+			t.result, t.err = run(g.ctx, g.timeout, task)
+		}
+
+		if t.err != nil {
+			g.errOnce.Do(func() {
+				g.err = t.err
+				g.cancel()
+			})
+		}
+	}()
+
+	return t
+}
+
+// Wait for all tasks and return the first error
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.cancel()
+	return g.err
+}
+
+// Wait for the task and return its result
+func (t *Task) Wait() (interface{}, error) {
+	<-t.done
+	return t.result, t.err
+}
+
+// Done is closed when the task finished
+func (t *Task) Done() <-chan struct{} {
+	return t.done
+}
+
+// Detach runs a non-critical task after the response has been written, e.g. to warm up a cache.
+// The task gets a detached context with the request and the session, errors are logged.
+func Detach(ctx context.Context, r *web.Request, task func(ctx context.Context) error, opts ...Option) error {
+	s := stateFrom(r)
+	if s == nil {
+		return errNoRequest
+	}
+
+	timeout := s.detachedTimeout
+	if o := applyOptions(opts); o.timeout != nil {
+		timeout = *o.timeout
+	}
+
+	s.detach(func() {
+		web.RunWithDetachedContext(web.ContextWithRequest(ctx, r), func(ctx context.Context) {
+			if _, err := run(ctx, timeout, func(ctx context.Context) (interface{}, error) {
+				return nil, task(ctx)
+			}); err != nil {
+				s.logger.WithContext(ctx).Error("detached request task failed: ", err)
+			}
+		})
+	})
+
+	return nil
+}
+
+// Do runs a background task in the current request scope
+func Do(ctx context.Context, r *web.Request, task func(ctx context.Context, r *web.Request)) {
+	if err := TryDo(ctx, r, task); err != nil {
+		task(ctx, r)
+	}
+}
+
+// TryDo tries to schedule an async task in the background, the request waits for the task
+func TryDo(ctx context.Context, r *web.Request, task func(ctx context.Context, r *web.Request)) error {
+	s := stateFrom(r)
+	if s == nil {
+		return errNoRequest
+	}
+
+	s.wg.Add(1)
+	acquireErr := s.acquire(ctx)
+
+	go func() {
+		defer s.wg.Done()
+		if acquireErr != nil {
+			s.logger.WithContext(ctx).Error("request task not started: ", acquireErr)
+			return
+		}
+		defer s.release()
+
+		if _, err := run(ctx, s.timeout, func(ctx context.Context) (interface{}, error) {
+			task(ctx, r)
+			return nil, nil
+		}); err != nil {
+			s.logger.WithContext(ctx).Error("request task failed: ", err)
+		}
+	}()
+
+	return nil
+}
+
+// run a task with a deadline and turn panics into errors
+func run(ctx context.Context, timeout time.Duration, task func(ctx context.Context) (interface{}, error)) (result interface{}, err error) {
+	ctx, span := trace.StartSpan(ctx, "requestTask")
+	defer span.End()
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v\n%s", ErrPanic, r, debug.Stack())
+		}
+		if err != nil {
+			span.SetStatus(trace.Status{Code: trace.StatusCodeUnknown, Message: err.Error()})
+		}
+	}()
+
+	return task(ctx)
+}
+
+func applyOptions(opts []Option) *options {
+	o := new(options)
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+func stateFrom(r *web.Request) *state {
+	if r == nil {
+		return nil
+	}
+	s, _ := r.Values.Load(stateKey)
+	if s, ok := s.(*state); ok {
+		return s
+	}
+	return nil
+}
+
+// acquire a slot of the concurrency limit, it fails if the context is done before
+func (s *state) acquire(ctx context.Context) error {
+	if s.semaphore == nil {
+		return nil
+	}
+
+	select {
+	case s.semaphore <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *state) release() {
+	if s.semaphore != nil {
+		<-s.semaphore
+	}
+}
+
+// detach adds a task to run after the response, it starts immediately if the response has been written already
+func (s *state) detach(task func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.launched {
+		go task()
+		return
+	}
+	s.detached = append(s.detached, task)
+}
+
+// launch the detached tasks
+func (s *state) launch() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.launched = true
+	for _, task := range s.detached {
+		go task()
+	}
+	s.detached = nil
+}
@@ -0,0 +1,160 @@
+package requesttask
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/web"
+)
+
+// serve runs the handler behind the filter and returns the request state
+func serve(t *testing.T, maxConcurrency float64, handler func(ctx context.Context, r *web.Request)) *state {
+	t.Helper()
+
+	f := new(filter).Inject(flamingo.NullLogger{}, &struct {
+		MaxConcurrency  float64 `inject:"config:core.requesttask.maxConcurrency"`
+		Timeout         string  `inject:"config:core.requesttask.timeout"`
+		DetachedTimeout string  `inject:"config:core.requesttask.detachedTimeout"`
+	}{MaxConcurrency: maxConcurrency, Timeout: "1s", DetachedTimeout: "1s"})
+
+	r := web.CreateRequest(httptest.NewRequest(http.MethodGet, "/", nil), web.EmptySession())
+	chain := web.NewFilterChain(func(ctx context.Context, r *web.Request, w http.ResponseWriter) web.Result {
+		handler(ctx, r)
+		return nil
+	}, f)
+	chain.Next(context.Background(), r, httptest.NewRecorder())
+
+	return stateFrom(r)
+}
+
+func TestGroup(t *testing.T) {
+	serve(t, 10, func(ctx context.Context, r *web.Request) {
+		g, ctx := NewGroup(ctx, r)
+		first := g.GoResult(func(context.Context) (interface{}, error) { return 1, nil })
+		second := g.GoResult(func(context.Context) (interface{}, error) { return 2, nil })
+		require.NoError(t, g.Wait())
+
+		v, err := first.Wait()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, v)
+		v, _ = second.Wait()
+		assert.Equal(t, 2, v)
+		assert.Error(t, ctx.Err(), "the group context is done after Wait")
+	})
+}
+
+func TestGroup_FirstErrorCancels(t *testing.T) {
+	serve(t, 10, func(ctx context.Context, r *web.Request) {
+		g, _ := NewGroup(ctx, r)
+		cancelled := g.GoResult(func(ctx context.Context) (interface{}, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+		g.Go(func(context.Context) error { return errors.New("boom") })
+
+		assert.EqualError(t, g.Wait(), "boom")
+		_, err := cancelled.Wait()
+		assert.Equal(t, context.Canceled, err)
+	})
+}
+
+func TestGroup_PanicAndTimeout(t *testing.T) {
+	serve(t, 10, func(ctx context.Context, r *web.Request) {
+		g, _ := NewGroup(ctx, r)
+		task := g.GoResult(func(context.Context) (interface{}, error) { panic("oops") })
+		_, panicErr := task.Wait()
+		assert.True(t, errors.Is(panicErr, ErrPanic))
+		assert.Contains(t, panicErr.Error(), "oops")
+
+		g, _ = NewGroup(ctx, r, WithTimeout(10*time.Millisecond))
+		g.Go(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		assert.Equal(t, context.DeadlineExceeded, g.Wait())
+	})
+}
+
+func TestGroup_ConcurrencyLimit(t *testing.T) {
+	var running, max int32
+	serve(t, 2, func(ctx context.Context, r *web.Request) {
+		g, _ := NewGroup(ctx, r)
+		for i := 0; i < 10; i++ {
+			g.Go(func(context.Context) error {
+				n := atomic.AddInt32(&running, 1)
+				for {
+					m := atomic.LoadInt32(&max)
+					if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+				return nil
+			})
+		}
+		assert.NoError(t, g.Wait())
+	})
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&max))
+}
+
+func TestGroup_WithoutRequest(t *testing.T) {
+	g, _ := NewGroup(context.Background(), nil)
+	task := g.GoResult(func(context.Context) (interface{}, error) { return "ok", nil })
+	assert.NoError(t, g.Wait())
+	v, _ := task.Wait()
+	assert.Equal(t, "ok", v)
+}
+
+func TestTryDo(t *testing.T) {
+	var done int32
+	serve(t, 10, func(ctx context.Context, r *web.Request) {
+		require.NoError(t, TryDo(ctx, r, func(context.Context, *web.Request) {
+			time.Sleep(5 * time.Millisecond)
+			atomic.StoreInt32(&done, 1)
+		}))
+		// panics are recovered
+		require.NoError(t, TryDo(ctx, r, func(context.Context, *web.Request) { panic("oops") }))
+	})
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&done), "the filter waits for the task")
+	assert.Error(t, TryDo(context.Background(), web.CreateRequest(nil, nil), func(context.Context, *web.Request) {}))
+}
+
+func TestDetach(t *testing.T) {
+	ran := make(chan *web.Request, 2)
+	var request *web.Request
+	s := serve(t, 10, func(ctx context.Context, r *web.Request) {
+		request = r
+		require.NoError(t, Detach(ctx, r, func(ctx context.Context) error {
+			ran <- web.RequestFromContext(ctx)
+			return nil
+		}))
+	})
+
+	select {
+	case <-ran:
+		t.Fatal("detached task ran before the response was written")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	s.launch()
+	assert.Equal(t, request, <-ran)
+
+	// tasks detached after the response start immediately
+	require.NoError(t, Detach(context.Background(), request, func(ctx context.Context) error {
+		ran <- nil
+		return nil
+	}))
+	assert.Nil(t, <-ran)
+}
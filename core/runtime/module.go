@@ -1,8 +1,8 @@
 package runtime
 
 import (
+	"context"
 	"fmt"
-	"sync"
 
 	"flamingo.me/dingo"
 	"flamingo.me/flamingo/v3/framework/flamingo"
@@ -10,13 +10,17 @@ import (
 )
 
 type (
-	// Module for core.runtime
+	// Module for core.runtime sets GOMAXPROCS according to the CPU quota on start and restores it on stop
 	Module struct {
 		logger flamingo.Logger
+		undo   func()
 	}
 )
 
-var once = sync.Once{}
+var (
+	_ flamingo.Starter = new(Module)
+	_ flamingo.Stopper = new(Module)
+)
 
 // Inject Module dependencies
 func (m *Module) Inject(logger flamingo.Logger) *Module {
@@ -25,16 +29,29 @@ func (m *Module) Inject(logger flamingo.Logger) *Module {
 }
 
 // Configure runtime dependency injection
-func (m *Module) Configure(injector *dingo.Injector) {
-	once.Do(func() {
-		_, err := maxprocs.Set(maxprocs.Logger(
-			func(logMessage string, args ...interface{}) {
-				m.logger.Info(fmt.Sprintf(logMessage, args...))
-			},
-		))
-
-		if err != nil {
-			m.logger.Error(fmt.Sprintf("Failed to set maxprocs: %v", err))
-		}
-	})
+func (m *Module) Configure(_ *dingo.Injector) {}
+
+// Start sets GOMAXPROCS
+func (m *Module) Start(_ context.Context) error {
+	undo, err := maxprocs.Set(maxprocs.Logger(
+		func(logMessage string, args ...interface{}) {
+			m.logger.Info(fmt.Sprintf(logMessage, args...))
+		},
+	))
+	if err != nil {
+		m.logger.Error(fmt.Sprintf("Failed to set maxprocs: %v", err))
+	}
+	m.undo = undo
+
+	return nil
+}
+
+// Stop restores GOMAXPROCS
+func (m *Module) Stop(_ context.Context) error {
+	if m.undo != nil {
+		m.undo()
+		m.undo = nil
+	}
+
+	return nil
 }
@@ -0,0 +1,45 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// Cmd lists the jobs and runs a job once, e.g. from a cron job of the platform
+func Cmd(scheduler *Scheduler) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "jobs",
+		Short: "List and run the scheduled jobs",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List the jobs with their schedules",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tSCHEDULE\tENABLED")
+			for _, status := range scheduler.Status() {
+				fmt.Fprintf(w, "%s\t%s\t%t\n", status.Name, status.Schedule, status.Enabled)
+			}
+			return w.Flush()
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "run <name>",
+		Short: "Run a job once, disabled jobs included",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := scheduler.RunNow(context.Background(), args[0]); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "job", args[0], "finished")
+			return nil
+		},
+	})
+
+	return cmd
+}
@@ -0,0 +1,29 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Endpoint shows the status of the jobs of this instance on the system endpoint
+type Endpoint struct {
+	scheduler *Scheduler
+}
+
+// Inject dependencies
+func (e *Endpoint) Inject(scheduler *Scheduler) *Endpoint {
+	e.scheduler = scheduler
+	return e
+}
+
+// ServeHTTP returns the status of the jobs
+func (e *Endpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(e.scheduler.Status())
+}
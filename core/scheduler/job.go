@@ -0,0 +1,76 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"flamingo.me/dingo"
+)
+
+type (
+	// Job is a periodic background job, register it with BindJob
+	Job interface {
+		// Name identifies the job in the configuration, logs, metrics and the jobs command
+		Name() string
+		// Schedule is an expression accepted by ParseSchedule, e.g. "*/5 * * * *" or "@every 10m"
+		Schedule() string
+		// Run executes the job, the context is cancelled after the timeout of the job
+		Run(ctx context.Context) error
+	}
+
+	// Options control how a job is run, the configuration of core.scheduler.jobs takes precedence
+	Options struct {
+		// Timeout of a run, 0 uses core.scheduler.timeout
+		Timeout time.Duration
+		// Jitter delays every run by a random duration up to Jitter, to spread the load of many instances
+		Jitter time.Duration
+		// Local jobs run on every instance, even if a Locker is bound
+		Local bool
+	}
+
+	// OptionsProvider is implemented by jobs which need other than the default Options
+	OptionsProvider interface {
+		Options() Options
+	}
+
+	// Locker makes sure that only one instance of a cluster runs a job at a time, e.g. with a shared database or redis.
+	// The scheduler works without locks unless an implementation is bound:
+	//
+	//	injector.Bind(new(scheduler.Locker)).To(redisLocker{})
+	Locker interface {
+		// TryLock acquires the lock of the job without waiting, ok is false if another instance holds it.
+		// The lock must expire after ttl, so a crashed instance does not block the job forever.
+		TryLock(ctx context.Context, job string, ttl time.Duration) (unlock func(), ok bool, err error)
+	}
+
+	// JobFunc adapts a function to a Job
+	JobFunc struct {
+		JobName     string
+		JobSchedule string
+		Func        func(ctx context.Context) error
+	}
+)
+
+var _ Job = new(JobFunc)
+
+// BindJob registers a job:
+//
+//	scheduler.BindJob(injector).To(new(cleanupJob))
+func BindJob(injector *dingo.Injector) *dingo.Binding {
+	return injector.BindMulti(new(Job))
+}
+
+// Name of the job
+func (j *JobFunc) Name() string {
+	return j.JobName
+}
+
+// Schedule of the job
+func (j *JobFunc) Schedule() string {
+	return j.JobSchedule
+}
+
+// Run calls the function
+func (j *JobFunc) Run(ctx context.Context) error {
+	return j.Func(ctx)
+}
@@ -0,0 +1,37 @@
+package scheduler
+
+import (
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+
+	"flamingo.me/flamingo/v3/framework/opencensus"
+)
+
+// run states of the runs metric
+const (
+	statusSuccess = "success"
+	statusFailure = "failure"
+	statusTimeout = "timeout"
+	statusSkipped = "skipped"
+	statusLocked  = "locked"
+)
+
+var (
+	// runsMeasure counts the runs of a job by status
+	runsMeasure = stats.Int64("flamingo/scheduler/runs", "Count of job runs", stats.UnitDimensionless)
+	// durationMeasure records the duration of executed runs
+	durationMeasure = stats.Int64("flamingo/scheduler/duration", "Duration of job runs", stats.UnitMilliseconds)
+
+	keyJob, _    = tag.NewKey("job")
+	keyStatus, _ = tag.NewKey("status")
+)
+
+func init() {
+	if err := opencensus.View("flamingo/scheduler/runs", runsMeasure, view.Count(), keyJob, keyStatus); err != nil {
+		panic(err)
+	}
+	if err := opencensus.View("flamingo/scheduler/duration", durationMeasure, view.Distribution(10, 100, 1000, 10000, 60000, 300000, 900000, 3600000), keyJob, keyStatus); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,79 @@
+// Package scheduler runs periodic background jobs while the application is running.
+package scheduler
+
+import (
+	"context"
+
+	"flamingo.me/dingo"
+	"github.com/spf13/cobra"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/systemendpoint"
+	"flamingo.me/flamingo/v3/framework/systemendpoint/domain"
+)
+
+type (
+	// Module for core/scheduler, the scheduler is started with the application and stopped on shutdown
+	Module struct {
+		endpoint  string
+		scheduler schedulerProvider
+	}
+
+	schedulerProvider func() *Scheduler
+)
+
+var (
+	_ flamingo.Starter = new(Module)
+	_ flamingo.Stopper = new(Module)
+)
+
+// Inject dependencies
+func (m *Module) Inject(scheduler schedulerProvider, config *struct {
+	Endpoint string `inject:"config:core.scheduler.endpoint"`
+}) *Module {
+	m.endpoint = config.Endpoint
+	m.scheduler = scheduler
+	return m
+}
+
+// Configure DI
+func (m *Module) Configure(injector *dingo.Injector) {
+	injector.Bind(new(Scheduler)).In(dingo.Singleton)
+	injector.BindMulti(new(cobra.Command)).ToProvider(Cmd)
+
+	if m.endpoint != "" {
+		injector.BindMap((*domain.Handler)(nil), m.endpoint).To(&Endpoint{})
+	}
+}
+
+// Start scheduling the jobs
+func (m *Module) Start(_ context.Context) error {
+	m.scheduler().Start()
+	return nil
+}
+
+// Stop scheduling, running jobs are cancelled when ctx is done
+func (m *Module) Stop(ctx context.Context) error {
+	m.scheduler().Stop(ctx)
+	return nil
+}
+
+// CueConfig schema and defaults
+func (m *Module) CueConfig() string {
+	// language=cue
+	return `
+core: scheduler: {
+	enabled: bool | *true
+	timeout: string | *"10m"
+	endpoint: string | *"/jobs"
+	jobs: {}
+}
+`
+}
+
+// Depends on the systemendpoint module
+func (*Module) Depends() []dingo.Module {
+	return []dingo.Module{
+		new(systemendpoint.Module),
+	}
+}
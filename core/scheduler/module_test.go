@@ -0,0 +1,14 @@
+package scheduler_test
+
+import (
+	"testing"
+
+	"flamingo.me/flamingo/v3/core/scheduler"
+	"flamingo.me/flamingo/v3/framework/config"
+)
+
+func TestModule_Configure(t *testing.T) {
+	if err := config.TryModules(nil, new(scheduler.Module)); err != nil {
+		t.Error(err)
+	}
+}
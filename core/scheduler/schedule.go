@@ -0,0 +1,230 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// Schedule calculates the activation times of a job
+	Schedule interface {
+		// Next returns the next activation after t, or the zero time if there is none
+		Next(t time.Time) time.Time
+	}
+
+	// interval activates a job every d
+	interval struct {
+		d time.Duration
+	}
+
+	// cron activates a job according to a standard 5 field cron expression
+	cron struct {
+		minute, hour, dom, month, dow uint64
+		// domStar and dowStar mark unrestricted day fields, see dayMatches
+		domStar, dowStar bool
+	}
+
+	field struct {
+		name     string
+		min, max int
+		names    map[string]int
+	}
+)
+
+var (
+	macros = map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *",
+	}
+
+	minuteField = field{name: "minute", min: 0, max: 59}
+	hourField   = field{name: "hour", min: 0, max: 23}
+	domField    = field{name: "day of month", min: 1, max: 31}
+	monthField  = field{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// dowField allows 7 for sunday, it is mapped to 0
+	dowField = field{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+
+	// ErrInvalidSchedule is returned for schedules which can not be parsed
+	ErrInvalidSchedule = errors.New("invalid schedule")
+)
+
+// ParseSchedule parses a cron expression with the fields minute, hour, day of month, month and day of week
+// (e.g. "*/15 8-18 * * mon-fri"), one of the macros @yearly, @annually, @monthly, @weekly, @daily, @midnight
+// and @hourly, or an interval (e.g. "@every 5m" or just "5m").
+func ParseSchedule(expr string) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+
+	if strings.HasPrefix(expr, "@every ") {
+		return parseInterval(expr, strings.TrimSpace(strings.TrimPrefix(expr, "@every ")))
+	}
+	if _, err := time.ParseDuration(expr); err == nil {
+		return parseInterval(expr, expr)
+	}
+
+	if macro, ok := macros[strings.ToLower(expr)]; ok {
+		expr = macro
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w %q: expected 5 fields, got %d", ErrInvalidSchedule, expr, len(fields))
+	}
+
+	c := new(cron)
+	var err error
+	if c.minute, err = minuteField.parse(fields[0]); err != nil {
+		return nil, fmt.Errorf("%w %q: %v", ErrInvalidSchedule, expr, err)
+	}
+	if c.hour, err = hourField.parse(fields[1]); err != nil {
+		return nil, fmt.Errorf("%w %q: %v", ErrInvalidSchedule, expr, err)
+	}
+	if c.dom, err = domField.parse(fields[2]); err != nil {
+		return nil, fmt.Errorf("%w %q: %v", ErrInvalidSchedule, expr, err)
+	}
+	if c.month, err = monthField.parse(fields[3]); err != nil {
+		return nil, fmt.Errorf("%w %q: %v", ErrInvalidSchedule, expr, err)
+	}
+	if c.dow, err = dowField.parse(fields[4]); err != nil {
+		return nil, fmt.Errorf("%w %q: %v", ErrInvalidSchedule, expr, err)
+	}
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1
+	}
+	c.domStar = strings.HasPrefix(fields[2], "*")
+	c.dowStar = strings.HasPrefix(fields[4], "*")
+
+	return c, nil
+}
+
+func parseInterval(expr, duration string) (Schedule, error) {
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %v", ErrInvalidSchedule, expr, err)
+	}
+	if d < time.Second {
+		return nil, fmt.Errorf("%w %q: the interval must be at least 1s", ErrInvalidSchedule, expr)
+	}
+	return interval{d: d}, nil
+}
+
+// Next activation after the interval
+func (i interval) Next(t time.Time) time.Time {
+	return t.Add(i.d)
+}
+
+// Next matching minute after t, in the location of t
+func (c *cron) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, loc)
+
+	// expressions like "0 0 30 2 *" never match
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if !has(c.month, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !has(c.hour, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if !has(c.minute, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+// dayMatches follows the cron convention: if both day fields are restricted, a match of either one is enough
+func (c *cron) dayMatches(t time.Time) bool {
+	dom := has(c.dom, t.Day())
+	dow := has(c.dow, int(t.Weekday()))
+	if c.domStar || c.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+func has(set uint64, v int) bool {
+	return set&(1<<uint(v)) != 0
+}
+
+// parse a comma separated list of values, ranges (1-5) and steps (*/5, 1-30/5, 5/10) into a bit set
+func (f field) parse(s string) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(s, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step in %s %q", f.name, part)
+			}
+			part = part[:i]
+		}
+
+		var lo, hi int
+		switch i := strings.Index(part, "-"); {
+		case part == "*":
+			lo, hi = f.min, f.max
+		case i >= 0:
+			var err error
+			if lo, err = f.value(part[:i]); err != nil {
+				return 0, err
+			}
+			if hi, err = f.value(part[i+1:]); err != nil {
+				return 0, err
+			}
+		default:
+			var err error
+			if lo, err = f.value(part); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if step > 1 {
+				hi = f.max
+			}
+		}
+
+		if lo > hi {
+			return 0, fmt.Errorf("invalid range in %s %q", f.name, part)
+		}
+		for v := lo; v <= hi; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+
+	return set, nil
+}
+
+func (f field) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid %s %q, expected %d-%d", f.name, s, f.min, f.max)
+	}
+
+	return v, nil
+}
@@ -0,0 +1,59 @@
+package scheduler_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"flamingo.me/flamingo/v3/core/scheduler"
+)
+
+func TestParseSchedule(t *testing.T) {
+	from := time.Date(2020, time.January, 31, 10, 17, 30, 0, time.UTC) // a friday
+
+	for expr, want := range map[string]time.Time{
+		"* * * * *":            time.Date(2020, time.January, 31, 10, 18, 0, 0, time.UTC),
+		"*/15 * * * *":         time.Date(2020, time.January, 31, 10, 30, 0, 0, time.UTC),
+		"5 8-18 * * *":         time.Date(2020, time.January, 31, 11, 5, 0, 0, time.UTC),
+		"0 9 * * mon-fri":      time.Date(2020, time.February, 3, 9, 0, 0, 0, time.UTC),
+		"0 0 29 feb *":         time.Date(2020, time.February, 29, 0, 0, 0, 0, time.UTC),
+		"0 0 1 * 7":            time.Date(2020, time.February, 1, 0, 0, 0, 0, time.UTC),
+		"0 0 13 * 5":           time.Date(2020, time.February, 7, 0, 0, 0, 0, time.UTC),
+		"20,40 10 * * *":       time.Date(2020, time.January, 31, 10, 20, 0, 0, time.UTC),
+		"30/10 * * * *":        time.Date(2020, time.January, 31, 10, 30, 0, 0, time.UTC),
+		"@hourly":              time.Date(2020, time.January, 31, 11, 0, 0, 0, time.UTC),
+		"@monthly":             time.Date(2020, time.February, 1, 0, 0, 0, 0, time.UTC),
+		"@every 5m":            time.Date(2020, time.January, 31, 10, 22, 30, 0, time.UTC),
+		"90s":                  time.Date(2020, time.January, 31, 10, 19, 0, 0, time.UTC),
+		"0 0 30 2 *":           {},
+		"  0   12  *  *  sun ": time.Date(2020, time.February, 2, 12, 0, 0, 0, time.UTC),
+	} {
+		schedule, err := scheduler.ParseSchedule(expr)
+		require.NoError(t, err, expr)
+		assert.Equal(t, want, schedule.Next(from), expr)
+	}
+}
+
+func TestParseSchedule_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"10-5 * * * *",
+		"*/0 * * * *",
+		"* * * foo *",
+		"@every 10ms",
+		"@every soon",
+		"@sometimes",
+	} {
+		_, err := scheduler.ParseSchedule(expr)
+		assert.True(t, errors.Is(err, scheduler.ErrInvalidSchedule), expr)
+	}
+}
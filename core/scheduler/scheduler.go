@@ -0,0 +1,423 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"go.opencensus.io/trace"
+
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+)
+
+type (
+	// Scheduler runs the bound jobs while the application is running
+	Scheduler struct {
+		logger  flamingo.Logger
+		locker  Locker
+		enabled bool
+		jobs    []*job
+		byName  map[string]*job
+		random  func(n int64) int64
+
+		mu     sync.Mutex
+		stop   chan struct{}
+		cancel context.CancelFunc
+		wg     sync.WaitGroup
+	}
+
+	// Status of a job, as shown by the system endpoint and the jobs command
+	Status struct {
+		Name      string        `json:"name"`
+		Schedule  string        `json:"schedule"`
+		Enabled   bool          `json:"enabled"`
+		Running   bool          `json:"running"`
+		NextRun   time.Time     `json:"nextRun"`
+		LastRun   time.Time     `json:"lastRun"`
+		LastTook  time.Duration `json:"lastTook"`
+		LastError string        `json:"lastError,omitempty"`
+		Runs      int           `json:"runs"`
+		Failures  int           `json:"failures"`
+	}
+
+	job struct {
+		Job
+		expr     string
+		schedule Schedule
+		options  Options
+		enabled  bool
+		logger   flamingo.Logger
+
+		mu      sync.Mutex
+		running bool
+		status  Status
+	}
+
+	jobConfig struct {
+		Enabled  *bool  `json:"enabled"`
+		Schedule string `json:"schedule"`
+		Timeout  string `json:"timeout"`
+		Jitter   string `json:"jitter"`
+		Local    *bool  `json:"local"`
+	}
+)
+
+const logKeyJob flamingo.LogKey = "job"
+
+var (
+	// ErrUnknownJob is returned by RunNow for jobs which are not bound
+	ErrUnknownJob = errors.New("unknown job")
+	// ErrJobRunning is returned by RunNow if the job is already running on this instance
+	ErrJobRunning = errors.New("job is already running")
+	// ErrLocked is returned by RunNow if another instance holds the lock of the job
+	ErrLocked = errors.New("job is locked by another instance")
+)
+
+// Inject dependencies and prepare the jobs with their configuration
+func (s *Scheduler) Inject(
+	logger flamingo.Logger,
+	jobs *struct {
+		Jobs []Job `inject:",optional"`
+	},
+	locker *struct {
+		Locker Locker `inject:",optional"`
+	},
+	cfg *struct {
+		Enabled bool       `inject:"config:core.scheduler.enabled"`
+		Timeout string     `inject:"config:core.scheduler.timeout"`
+		Jobs    config.Map `inject:"config:core.scheduler.jobs,optional"`
+	},
+) *Scheduler {
+	s.logger = logger.WithField(flamingo.LogKeyModule, "core.scheduler").WithField(flamingo.LogKeyCategory, "scheduler")
+	s.locker = locker.Locker
+	s.enabled = cfg.Enabled
+	s.byName = make(map[string]*job)
+	s.random = rand.Int63n
+
+	timeout, err := time.ParseDuration(cfg.Timeout)
+	if err != nil || timeout <= 0 {
+		s.logger.Error(fmt.Sprintf("invalid core.scheduler.timeout %q, using 10m", cfg.Timeout))
+		timeout = 10 * time.Minute
+	}
+
+	configs := make(map[string]jobConfig)
+	if err := cfg.Jobs.MapInto(&configs); err != nil {
+		s.logger.Error("invalid core.scheduler.jobs: ", err)
+	}
+
+	for _, j := range jobs.Jobs {
+		name := j.Name()
+		if name == "" {
+			s.logger.Error(fmt.Sprintf("job %T has no name", j))
+			continue
+		}
+		if _, ok := s.byName[name]; ok {
+			s.logger.Error("duplicate job ", name)
+			continue
+		}
+
+		prepared, err := prepare(j, configs[name], timeout)
+		prepared.logger = s.logger.WithField(logKeyJob, name)
+		if err != nil {
+			prepared.logger.Error(err)
+		}
+
+		s.jobs = append(s.jobs, prepared)
+		s.byName[name] = prepared
+	}
+
+	sort.Slice(s.jobs, func(i, k int) bool { return s.jobs[i].Name() < s.jobs[k].Name() })
+
+	return s
+}
+
+// prepare applies the options and the configuration of a job, jobs with invalid settings are disabled
+func prepare(j Job, cfg jobConfig, timeout time.Duration) (*job, error) {
+	prepared := &job{Job: j, expr: j.Schedule(), enabled: true}
+	if provider, ok := j.(OptionsProvider); ok {
+		prepared.options = provider.Options()
+	}
+	if cfg.Enabled != nil {
+		prepared.enabled = *cfg.Enabled
+	}
+	if cfg.Schedule != "" {
+		prepared.expr = cfg.Schedule
+	}
+	if cfg.Local != nil {
+		prepared.options.Local = *cfg.Local
+	}
+	if prepared.options.Timeout <= 0 {
+		prepared.options.Timeout = timeout
+	}
+	prepared.status = Status{Name: j.Name(), Schedule: prepared.expr, Enabled: prepared.enabled}
+
+	var err error
+	if cfg.Timeout != "" {
+		if prepared.options.Timeout, err = parseDuration("timeout", cfg.Timeout); err == nil && prepared.options.Timeout == 0 {
+			prepared.options.Timeout = timeout
+		}
+	}
+	if cfg.Jitter != "" && err == nil {
+		prepared.options.Jitter, err = parseDuration("jitter", cfg.Jitter)
+	}
+
+	if err == nil {
+		prepared.schedule, err = ParseSchedule(prepared.expr)
+	}
+
+	if err != nil {
+		prepared.enabled = false
+		prepared.status.Enabled = false
+		return prepared, fmt.Errorf("job disabled: %w", err)
+	}
+
+	return prepared, nil
+}
+
+func parseDuration(key, value string) (time.Duration, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid %s %q", key, value)
+	}
+	return d, nil
+}
+
+// Start scheduling the enabled jobs, unless the scheduler is disabled or already started
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.enabled || s.stop != nil {
+		return
+	}
+
+	var ctx context.Context
+	ctx, s.cancel = context.WithCancel(context.Background())
+	s.stop = make(chan struct{})
+
+	for _, j := range s.jobs {
+		if !j.enabled {
+			continue
+		}
+		s.wg.Add(1)
+		go s.loop(ctx, s.stop, j)
+	}
+
+	s.logger.Info(fmt.Sprintf("scheduler started with %d jobs", len(s.jobs)))
+}
+
+// Stop scheduling and wait for the running jobs, they are cancelled when ctx is done
+func (s *Scheduler) Stop(ctx context.Context) {
+	s.mu.Lock()
+	if s.stop == nil {
+		s.mu.Unlock()
+		return
+	}
+	close(s.stop)
+	cancel := s.cancel
+	s.stop, s.cancel = nil, nil
+	s.mu.Unlock()
+
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.logger.Warn("cancelling running jobs")
+		cancel()
+		<-done
+	}
+
+	s.logger.Info("scheduler stopped")
+}
+
+// RunNow runs a job immediately, it respects the lock and fails if the job is already running
+func (s *Scheduler) RunNow(ctx context.Context, name string) error {
+	j, ok := s.byName[name]
+	if !ok {
+		return fmt.Errorf("%w %q", ErrUnknownJob, name)
+	}
+
+	if !j.tryStart() {
+		return fmt.Errorf("%s: %w", name, ErrJobRunning)
+	}
+	defer j.done()
+
+	return s.execute(ctx, j)
+}
+
+// Status of all jobs, ordered by name
+func (s *Scheduler) Status() []Status {
+	status := make([]Status, len(s.jobs))
+	for i, j := range s.jobs {
+		j.mu.Lock()
+		status[i] = j.status
+		status[i].Running = j.running
+		j.mu.Unlock()
+	}
+	return status
+}
+
+func (s *Scheduler) loop(ctx context.Context, stop <-chan struct{}, j *job) {
+	defer s.wg.Done()
+
+	for {
+		next := j.schedule.Next(time.Now())
+		if next.IsZero() {
+			j.logger.Error(fmt.Sprintf("schedule %q has no next activation", j.expr))
+			return
+		}
+		if j.options.Jitter > 0 {
+			next = next.Add(time.Duration(s.random(int64(j.options.Jitter))))
+		}
+		j.setNextRun(next)
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		// a run is skipped while the previous run of the job is still running
+		if !j.tryStart() {
+			j.logger.Warn("run skipped, the previous run is still running")
+			record(ctx, j.Name(), statusSkipped, 0)
+			continue
+		}
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer j.done()
+			_ = s.execute(ctx, j)
+		}()
+	}
+}
+
+// execute a run of the job, the caller must hold the running state of the job
+func (s *Scheduler) execute(ctx context.Context, j *job) error {
+	ctx, span := trace.StartSpan(ctx, "flamingo/scheduler/"+j.Name())
+	defer span.End()
+	span.AddAttributes(trace.StringAttribute("job", j.Name()))
+	logger := j.logger.WithContext(ctx)
+
+	if s.locker != nil && !j.options.Local {
+		unlock, ok, err := s.locker.TryLock(ctx, j.Name(), j.options.Timeout)
+		if err != nil {
+			err = fmt.Errorf("%s: lock: %w", j.Name(), err)
+			logger.Error(err)
+			span.SetStatus(trace.Status{Code: trace.StatusCodeUnavailable, Message: err.Error()})
+			record(ctx, j.Name(), statusFailure, 0)
+			return err
+		}
+		if !ok {
+			logger.Debug("run skipped, the job is locked by another instance")
+			record(ctx, j.Name(), statusLocked, 0)
+			return fmt.Errorf("%s: %w", j.Name(), ErrLocked)
+		}
+		defer unlock()
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, j.options.Timeout)
+	defer cancel()
+
+	logger.Info("job started")
+	start := time.Now()
+	err := run(runCtx, j)
+	took := time.Since(start)
+
+	status := statusSuccess
+	if err != nil {
+		status = statusFailure
+		if errors.Is(err, context.DeadlineExceeded) || runCtx.Err() == context.DeadlineExceeded {
+			status = statusTimeout
+			err = fmt.Errorf("timed out after %s: %w", j.options.Timeout, err)
+		}
+	}
+	j.finished(start, took, err)
+	record(ctx, j.Name(), status, took)
+
+	logger = logger.WithFields(map[flamingo.LogKey]interface{}{
+		flamingo.LogKeyResponseTime: took.Milliseconds(),
+		"status":                    status,
+	})
+	if err != nil {
+		span.SetStatus(trace.Status{Code: trace.StatusCodeUnknown, Message: err.Error()})
+		logger.Error("job failed: ", err)
+		return fmt.Errorf("%s: %w", j.Name(), err)
+	}
+
+	logger.Info("job finished")
+	return nil
+}
+
+// run the job and turn panics into errors
+func run(ctx context.Context, j *job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return j.Run(ctx)
+}
+
+func record(ctx context.Context, name, status string, took time.Duration) {
+	ctx, _ = tag.New(ctx, tag.Upsert(keyJob, name), tag.Upsert(keyStatus, status))
+	stats.Record(ctx, runsMeasure.M(1))
+	if status == statusSuccess || status == statusFailure || status == statusTimeout {
+		stats.Record(ctx, durationMeasure.M(took.Milliseconds()))
+	}
+}
+
+func (j *job) tryStart() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.running {
+		return false
+	}
+	j.running = true
+	return true
+}
+
+func (j *job) done() {
+	j.mu.Lock()
+	j.running = false
+	j.mu.Unlock()
+}
+
+func (j *job) setNextRun(next time.Time) {
+	j.mu.Lock()
+	j.status.NextRun = next
+	j.mu.Unlock()
+}
+
+func (j *job) finished(start time.Time, took time.Duration, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.status.LastRun = start
+	j.status.LastTook = took
+	j.status.LastError = ""
+	j.status.Runs++
+	if err != nil {
+		j.status.LastError = err.Error()
+		j.status.Failures++
+	}
+}
@@ -0,0 +1,198 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+)
+
+type (
+	optionsJob struct {
+		JobFunc
+		options Options
+	}
+
+	testLocker struct {
+		locked   bool
+		unlocked int32
+	}
+)
+
+func (j *optionsJob) Options() Options {
+	return j.options
+}
+
+func (l *testLocker) TryLock(_ context.Context, _ string, _ time.Duration) (func(), bool, error) {
+	if l.locked {
+		return nil, false, nil
+	}
+	return func() { atomic.AddInt32(&l.unlocked, 1) }, true, nil
+}
+
+func testScheduler(t *testing.T, locker Locker, jobs config.Map, js ...Job) *Scheduler {
+	t.Helper()
+
+	return new(Scheduler).Inject(
+		flamingo.NullLogger{},
+		&struct {
+			Jobs []Job `inject:",optional"`
+		}{Jobs: js},
+		&struct {
+			Locker Locker `inject:",optional"`
+		}{Locker: locker},
+		&struct {
+			Enabled bool       `inject:"config:core.scheduler.enabled"`
+			Timeout string     `inject:"config:core.scheduler.timeout"`
+			Jobs    config.Map `inject:"config:core.scheduler.jobs,optional"`
+		}{Enabled: true, Timeout: "1s", Jobs: jobs},
+	)
+}
+
+func TestScheduler_RunNow(t *testing.T) {
+	s := testScheduler(t, nil, nil,
+		&JobFunc{JobName: "ok", JobSchedule: "@hourly", Func: func(context.Context) error { return nil }},
+		&JobFunc{JobName: "failing", JobSchedule: "@hourly", Func: func(context.Context) error { return errors.New("boom") }},
+		&JobFunc{JobName: "panicking", JobSchedule: "@hourly", Func: func(context.Context) error { panic("oops") }},
+		&optionsJob{
+			JobFunc: JobFunc{JobName: "slow", JobSchedule: "@hourly", Func: func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			}},
+			options: Options{Timeout: 10 * time.Millisecond},
+		},
+	)
+
+	assert.NoError(t, s.RunNow(context.Background(), "ok"))
+
+	err := s.RunNow(context.Background(), "failing")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+
+	err = s.RunNow(context.Background(), "panicking")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "panic: oops")
+
+	err = s.RunNow(context.Background(), "slow")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out after 10ms")
+
+	assert.True(t, errors.Is(s.RunNow(context.Background(), "unknown"), ErrUnknownJob))
+
+	status := s.Status()
+	require.Len(t, status, 4)
+	assert.Equal(t, []string{"failing", "ok", "panicking", "slow"}, []string{status[0].Name, status[1].Name, status[2].Name, status[3].Name})
+	assert.Equal(t, 1, status[0].Runs)
+	assert.Equal(t, 1, status[0].Failures)
+	assert.Equal(t, "boom", status[0].LastError)
+	assert.Equal(t, 1, status[1].Runs)
+	assert.Equal(t, 0, status[1].Failures)
+	assert.Empty(t, status[1].LastError)
+}
+
+func TestScheduler_Overlap(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	s := testScheduler(t, nil, nil, &JobFunc{JobName: "blocking", JobSchedule: "@hourly", Func: func(context.Context) error {
+		close(started)
+		<-release
+		return nil
+	}})
+
+	done := make(chan error)
+	go func() {
+		done <- s.RunNow(context.Background(), "blocking")
+	}()
+	<-started
+
+	assert.True(t, errors.Is(s.RunNow(context.Background(), "blocking"), ErrJobRunning))
+	assert.True(t, s.Status()[0].Running)
+
+	close(release)
+	assert.NoError(t, <-done)
+	assert.False(t, s.Status()[0].Running)
+}
+
+func TestScheduler_Locker(t *testing.T) {
+	var runs int32
+	count := func(context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		return nil
+	}
+	locker := new(testLocker)
+	s := testScheduler(t, locker, nil,
+		&JobFunc{JobName: "cluster", JobSchedule: "@hourly", Func: count},
+		&optionsJob{JobFunc: JobFunc{JobName: "local", JobSchedule: "@hourly", Func: count}, options: Options{Local: true}},
+	)
+
+	assert.NoError(t, s.RunNow(context.Background(), "cluster"))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&locker.unlocked))
+
+	locker.locked = true
+	assert.True(t, errors.Is(s.RunNow(context.Background(), "cluster"), ErrLocked))
+	assert.NoError(t, s.RunNow(context.Background(), "local"))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&runs))
+}
+
+func TestScheduler_Config(t *testing.T) {
+	noop := func(context.Context) error { return nil }
+	s := testScheduler(t, nil, config.Map{
+		"disabled":   config.Map{"enabled": false},
+		"overridden": config.Map{"schedule": "@daily", "timeout": "5s", "jitter": "1m", "local": true},
+		"invalid":    config.Map{"timeout": "soon"},
+	},
+		&JobFunc{JobName: "disabled", JobSchedule: "@hourly", Func: noop},
+		&JobFunc{JobName: "overridden", JobSchedule: "@hourly", Func: noop},
+		&JobFunc{JobName: "invalid", JobSchedule: "@hourly", Func: noop},
+		&JobFunc{JobName: "broken", JobSchedule: "every now and then", Func: noop},
+		&JobFunc{JobName: "broken", JobSchedule: "@hourly", Func: noop},
+		&JobFunc{JobSchedule: "@hourly", Func: noop},
+	)
+
+	require.Len(t, s.jobs, 4)
+	assert.False(t, s.byName["disabled"].enabled)
+	assert.False(t, s.byName["invalid"].enabled)
+	assert.False(t, s.byName["broken"].enabled)
+
+	overridden := s.byName["overridden"]
+	assert.True(t, overridden.enabled)
+	assert.Equal(t, "@daily", overridden.expr)
+	assert.Equal(t, Options{Timeout: 5 * time.Second, Jitter: time.Minute, Local: true}, overridden.options)
+
+	// disabled jobs can still be run manually
+	assert.NoError(t, s.RunNow(context.Background(), "disabled"))
+}
+
+func TestScheduler_StartStop(t *testing.T) {
+	ran := make(chan struct{}, 1)
+	s := testScheduler(t, nil, nil, &optionsJob{
+		JobFunc: JobFunc{JobName: "every", JobSchedule: "@every 1s", Func: func(ctx context.Context) error {
+			select {
+			case ran <- struct{}{}:
+			default:
+			}
+			return nil
+		}},
+		options: Options{Jitter: 100 * time.Millisecond},
+	})
+
+	s.Start()
+	s.Start()
+
+	select {
+	case <-ran:
+	case <-time.After(5 * time.Second):
+		t.Fatal("job did not run")
+	}
+
+	s.Stop(context.Background())
+	assert.Nil(t, s.stop)
+	assert.False(t, s.Status()[0].NextRun.IsZero())
+}
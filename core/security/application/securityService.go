@@ -66,6 +66,10 @@ func (s *SecurityServiceImpl) IsGranted(ctx context.Context, session *web.Sessio
 
 	var results []voter.AccessDecision
 	for index := range s.voters {
+		if contextVoter, ok := s.voters[index].(voter.ContextVoter); ok {
+			results = append(results, contextVoter.VoteContext(ctx, allPermissions, desiredPermission, object))
+			continue
+		}
 		results = append(results, s.voters[index].Vote(allPermissions, desiredPermission, object))
 	}
 
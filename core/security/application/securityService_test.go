@@ -431,3 +431,19 @@ func (t *SecurityServiceTestSuite) TestIsGranted() {
 		t.Equal(testCase.decision, t.service.IsGranted(t.context, webSession, "SomePermission", nil))
 	}
 }
+
+func (t *SecurityServiceTestSuite) TestIsGranted_ContextVoter() {
+	contextVoter := &voterMocks.ContextVoter{}
+	t.service.voters = append(t.service.voters, contextVoter)
+	t.service.voterStrategy = VoterStrategyUnanimous
+
+	webSession := web.EmptySession()
+	t.firstVoter.On("Vote", []string{}, "SomePermission", nil).Return(voter.AccessGranted).Once()
+	t.secondVoter.On("Vote", []string{}, "SomePermission", nil).Return(voter.AccessAbstained).Once()
+	t.thirdVoter.On("Vote", []string{}, "SomePermission", nil).Return(voter.AccessAbstained).Once()
+	contextVoter.On("VoteContext", t.context, []string{}, "SomePermission", nil).Return(voter.AccessDenied).Once()
+	t.roleService.On("AllPermissions", t.context, webSession).Return([]string{}).Once()
+	t.False(t.service.IsGranted(t.context, webSession, "SomePermission", nil))
+
+	contextVoter.AssertExpectations(t.T())
+}
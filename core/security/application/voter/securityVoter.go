@@ -1,5 +1,7 @@
 package voter
 
+import "context"
+
 type (
 	// AccessDecision defines access decision type which represents voter result
 	AccessDecision int
@@ -8,6 +10,13 @@ type (
 	SecurityVoter interface {
 		Vote(allAssignedPermissions []string, desiredPermission string, forObject interface{}) AccessDecision
 	}
+
+	// ContextVoter is an optional extension of the SecurityVoter, the security service calls VoteContext
+	// with the context of the decision instead of Vote
+	ContextVoter interface {
+		SecurityVoter
+		VoteContext(ctx context.Context, allAssignedPermissions []string, desiredPermission string, forObject interface{}) AccessDecision
+	}
 )
 
 const (
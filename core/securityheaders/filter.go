@@ -0,0 +1,192 @@
+package securityheaders
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/web"
+)
+
+type (
+	// Headers contains the security header values, empty values are not sent
+	Headers struct {
+		ContentSecurityPolicy   string `json:"contentSecurityPolicy"`
+		StrictTransportSecurity string `json:"strictTransportSecurity"`
+		FrameOptions            string `json:"frameOptions"`
+		ReferrerPolicy          string `json:"referrerPolicy"`
+		PermissionsPolicy       string `json:"permissionsPolicy"`
+		ContentTypeOptions      string `json:"contentTypeOptions"`
+	}
+
+	// overrideHeaders distinguishes between unset headers and headers explicitly disabled by an empty value
+	overrideHeaders struct {
+		ContentSecurityPolicy   *string `json:"contentSecurityPolicy"`
+		StrictTransportSecurity *string `json:"strictTransportSecurity"`
+		FrameOptions            *string `json:"frameOptions"`
+		ReferrerPolicy          *string `json:"referrerPolicy"`
+		PermissionsPolicy       *string `json:"permissionsPolicy"`
+		ContentTypeOptions      *string `json:"contentTypeOptions"`
+	}
+
+	override struct {
+		Handler    string          `json:"handler"`
+		PathPrefix string          `json:"pathPrefix"`
+		Headers    overrideHeaders `json:"headers"`
+	}
+
+	filter struct {
+		logger     flamingo.Logger
+		headers    Headers
+		overrides  []override
+		reportOnly bool
+		reportURI  string
+	}
+
+	nonceKeyType string
+)
+
+const (
+	nonceKey         nonceKeyType = "core.securityheaders.nonce"
+	noncePlaceholder              = "{nonce}"
+)
+
+// Inject dependencies
+func (f *filter) Inject(router web.ReverseRouter, logger flamingo.Logger, cfg *struct {
+	Headers       config.Map   `inject:"config:core.securityheaders.headers"`
+	Overrides     config.Slice `inject:"config:core.securityheaders.overrides,optional"`
+	ReportOnly    bool         `inject:"config:core.securityheaders.reportOnly"`
+	ReportEnabled bool         `inject:"config:core.securityheaders.report.enabled"`
+	ReportPath    string       `inject:"config:core.securityheaders.report.path"`
+	ReportURI     string       `inject:"config:core.securityheaders.report.uri"`
+}) *filter {
+	f.logger = logger.WithField(flamingo.LogKeyModule, "core.securityheaders").WithField(flamingo.LogKeyCategory, "filter")
+
+	if err := cfg.Headers.MapInto(&f.headers); err != nil {
+		f.logger.Error("invalid header configuration: ", err)
+	}
+	if err := cfg.Overrides.MapInto(&f.overrides); err != nil {
+		f.logger.Error("invalid override configuration: ", err)
+	}
+
+	f.reportOnly = cfg.ReportOnly
+	f.reportURI = cfg.ReportURI
+	if f.reportURI == "" && cfg.ReportEnabled {
+		if u, err := router.Relative(cfg.ReportPath, nil); err == nil {
+			f.reportURI = u.String()
+		}
+	}
+
+	return f
+}
+
+// Filter sets the security headers and generates the CSP nonce before the request is processed
+func (f *filter) Filter(ctx context.Context, r *web.Request, w http.ResponseWriter, chain *web.FilterChain) web.Result {
+	nonce, err := newNonce()
+	if err != nil {
+		f.logger.WithContext(ctx).Error("unable to generate csp nonce: ", err)
+	}
+	r.Values.Store(nonceKey, nonce)
+
+	f.apply(w.Header(), f.headersFor(r), nonce, isSecure(r.Request()))
+
+	return chain.Next(ctx, r, w)
+}
+
+// headersFor merges all matching overrides, in order of their definition, into the default headers
+func (f *filter) headersFor(r *web.Request) Headers {
+	headers := f.headers
+
+	for _, o := range f.overrides {
+		if o.Handler != "" && (r.Handler() == nil || r.Handler().GetHandlerName() != o.Handler) {
+			continue
+		}
+		if o.PathPrefix != "" && !strings.HasPrefix(r.Request().URL.Path, o.PathPrefix) {
+			continue
+		}
+		if o.Handler == "" && o.PathPrefix == "" {
+			continue
+		}
+
+		o.Headers.mergeInto(&headers)
+	}
+
+	return headers
+}
+
+func (o overrideHeaders) mergeInto(h *Headers) {
+	set := func(target *string, value *string) {
+		if value != nil {
+			*target = *value
+		}
+	}
+
+	set(&h.ContentSecurityPolicy, o.ContentSecurityPolicy)
+	set(&h.StrictTransportSecurity, o.StrictTransportSecurity)
+	set(&h.FrameOptions, o.FrameOptions)
+	set(&h.ReferrerPolicy, o.ReferrerPolicy)
+	set(&h.PermissionsPolicy, o.PermissionsPolicy)
+	set(&h.ContentTypeOptions, o.ContentTypeOptions)
+}
+
+func (f *filter) apply(header http.Header, h Headers, nonce string, secure bool) {
+	if csp := h.ContentSecurityPolicy; csp != "" {
+		if nonce != "" {
+			csp = strings.Replace(csp, noncePlaceholder, "'nonce-"+nonce+"'", -1)
+		} else {
+			csp = strings.Replace(csp, noncePlaceholder, "", -1)
+		}
+		if f.reportURI != "" {
+			csp = strings.TrimRight(strings.TrimSpace(csp), ";") + "; report-uri " + f.reportURI
+		}
+
+		if f.reportOnly {
+			header.Set("Content-Security-Policy-Report-Only", csp)
+		} else {
+			header.Set("Content-Security-Policy", csp)
+		}
+	}
+
+	// browsers ignore HSTS on plain http, so we only send it for secure requests
+	if h.StrictTransportSecurity != "" && secure {
+		header.Set("Strict-Transport-Security", h.StrictTransportSecurity)
+	}
+
+	setIfNotEmpty := func(name, value string) {
+		if value != "" {
+			header.Set(name, value)
+		}
+	}
+	setIfNotEmpty("X-Frame-Options", h.FrameOptions)
+	setIfNotEmpty("Referrer-Policy", h.ReferrerPolicy)
+	setIfNotEmpty("Permissions-Policy", h.PermissionsPolicy)
+	setIfNotEmpty("X-Content-Type-Options", h.ContentTypeOptions)
+}
+
+func isSecure(r *http.Request) bool {
+	return r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https")
+}
+
+func newNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// Nonce returns the CSP nonce of the current request
+func Nonce(ctx context.Context) string {
+	r := web.RequestFromContext(ctx)
+	if r == nil {
+		return ""
+	}
+
+	nonce, _ := r.Values.Load(nonceKey)
+	s, _ := nonce.(string)
+	return s
+}
@@ -0,0 +1,96 @@
+package securityheaders
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/web"
+	"github.com/stretchr/testify/assert"
+)
+
+func runFilter(f *filter, r *http.Request) (http.Header, string) {
+	recorder := httptest.NewRecorder()
+	var nonce string
+	chain := web.NewFilterChain(func(ctx context.Context, req *web.Request, w http.ResponseWriter) web.Result {
+		nonce = Nonce(web.ContextWithRequest(ctx, req))
+		return nil
+	})
+	f.Filter(context.Background(), web.CreateRequest(r, nil), recorder, chain)
+
+	return recorder.Header(), nonce
+}
+
+func testFilter() *filter {
+	return &filter{
+		logger: flamingo.NullLogger{},
+		headers: Headers{
+			ContentSecurityPolicy:   "script-src 'self' {nonce}",
+			StrictTransportSecurity: "max-age=60",
+			FrameOptions:            "DENY",
+			ReferrerPolicy:          "no-referrer",
+			ContentTypeOptions:      "nosniff",
+		},
+	}
+}
+
+func TestFilter_Headers(t *testing.T) {
+	header, nonce := runFilter(testFilter(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.NotEmpty(t, nonce)
+	assert.Equal(t, "script-src 'self' 'nonce-"+nonce+"'", header.Get("Content-Security-Policy"))
+	assert.Equal(t, "DENY", header.Get("X-Frame-Options"))
+	assert.Equal(t, "no-referrer", header.Get("Referrer-Policy"))
+	assert.Equal(t, "nosniff", header.Get("X-Content-Type-Options"))
+	assert.Empty(t, header.Get("Permissions-Policy"))
+	assert.Empty(t, header.Get("Strict-Transport-Security"), "hsts must not be sent on plain http")
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-Forwarded-Proto", "https")
+	header, nextNonce := runFilter(testFilter(), r)
+	assert.Equal(t, "max-age=60", header.Get("Strict-Transport-Security"))
+	assert.NotEqual(t, nonce, nextNonce)
+}
+
+func TestFilter_ReportOnly(t *testing.T) {
+	f := testFilter()
+	f.reportOnly = true
+	f.reportURI = "/_security/csp-report"
+
+	header, _ := runFilter(f, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Empty(t, header.Get("Content-Security-Policy"))
+	assert.True(t, strings.HasSuffix(header.Get("Content-Security-Policy-Report-Only"), "; report-uri /_security/csp-report"))
+}
+
+func TestFilter_Overrides(t *testing.T) {
+	empty := ""
+	sameOrigin := "SAMEORIGIN"
+
+	f := testFilter()
+	f.overrides = []override{
+		{PathPrefix: "/embed", Headers: overrideHeaders{FrameOptions: &empty}},
+		{PathPrefix: "/checkout", Headers: overrideHeaders{FrameOptions: &sameOrigin}},
+		{Headers: overrideHeaders{ReferrerPolicy: &empty}},
+	}
+
+	header, _ := runFilter(f, httptest.NewRequest(http.MethodGet, "/embed/widget", nil))
+	assert.Empty(t, header.Get("X-Frame-Options"))
+	assert.Equal(t, "no-referrer", header.Get("Referrer-Policy"))
+
+	header, _ = runFilter(f, httptest.NewRequest(http.MethodGet, "/checkout", nil))
+	assert.Equal(t, "SAMEORIGIN", header.Get("X-Frame-Options"))
+
+	header, _ = runFilter(f, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, "DENY", header.Get("X-Frame-Options"))
+}
+
+func TestNonceFunc(t *testing.T) {
+	assert.Equal(t, "", new(nonceFunc).Func(context.Background()).(func() string)())
+
+	req := web.CreateRequest(nil, nil)
+	req.Values.Store(nonceKey, "abc")
+	assert.Equal(t, "abc", new(nonceFunc).Func(web.ContextWithRequest(context.Background(), req)).(func() string)())
+}
@@ -0,0 +1,88 @@
+// Package securityheaders sets configurable security headers such as the Content-Security-Policy
+// and provides per request CSP nonces via the `cspNonce` template function.
+package securityheaders
+
+import (
+	"flamingo.me/dingo"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/web"
+)
+
+type (
+	// Module for core/securityheaders
+	Module struct {
+		reportEnabled bool
+		reportPath    string
+	}
+
+	routes struct {
+		controller *ReportController
+		path       string
+	}
+)
+
+// Inject dependencies
+func (m *Module) Inject(config *struct {
+	ReportEnabled bool   `inject:"config:core.securityheaders.report.enabled"`
+	ReportPath    string `inject:"config:core.securityheaders.report.path"`
+}) *Module {
+	m.reportEnabled = config.ReportEnabled
+	m.reportPath = config.ReportPath
+	return m
+}
+
+// Configure DI
+func (m *Module) Configure(injector *dingo.Injector) {
+	injector.BindMulti(new(web.Filter)).To(filter{})
+	flamingo.BindTemplateFunc(injector, "cspNonce", new(nonceFunc))
+
+	if m.reportEnabled {
+		web.BindRoutes(injector, &routes{path: m.reportPath})
+	}
+}
+
+// CueConfig schema and defaults
+func (m *Module) CueConfig() string {
+	// language=cue
+	return `
+core: securityheaders: {
+	headers: {
+		contentSecurityPolicy: string | *"default-src 'self'; script-src 'self' {nonce}; style-src 'self' {nonce}; object-src 'none'; base-uri 'self'; frame-ancestors 'self'"
+		strictTransportSecurity: string | *"max-age=31536000; includeSubDomains"
+		frameOptions: string | *"SAMEORIGIN"
+		referrerPolicy: string | *"strict-origin-when-cross-origin"
+		permissionsPolicy: string | *"camera=(), microphone=(), geolocation=()"
+		contentTypeOptions: string | *"nosniff"
+	}
+	reportOnly: bool | *false
+	report: {
+		enabled: bool | *false
+		path: string | *"/_security/csp-report"
+		uri: string | *""
+	}
+	overrides: [...{
+		handler?: string
+		pathPrefix?: string
+		headers: {
+			contentSecurityPolicy?: string
+			strictTransportSecurity?: string
+			frameOptions?: string
+			referrerPolicy?: string
+			permissionsPolicy?: string
+			contentTypeOptions?: string
+		}
+	}]
+}
+`
+}
+
+// Inject dependencies
+func (r *routes) Inject(controller *ReportController) {
+	r.controller = controller
+}
+
+// Routes registers the CSP report collection endpoint
+func (r *routes) Routes(registry *web.RouterRegistry) {
+	registry.HandlePost("core.securityheaders.report", r.controller.Report)
+	registry.MustRoute(r.path, "core.securityheaders.report")
+}
@@ -0,0 +1,18 @@
+package securityheaders_test
+
+import (
+	"testing"
+
+	"flamingo.me/flamingo/v3/core/securityheaders"
+	"flamingo.me/flamingo/v3/framework/config"
+)
+
+func TestModule_Configure(t *testing.T) {
+	if err := config.TryModules(nil, new(securityheaders.Module)); err != nil {
+		t.Error(err)
+	}
+
+	if err := config.TryModules(config.Map{"core.securityheaders.report.enabled": true}, new(securityheaders.Module)); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,42 @@
+package securityheaders
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/web"
+)
+
+// maxReportSize limits the accepted size of a single violation report
+const maxReportSize = 64 * 1024
+
+// ReportController collects CSP violation reports and writes them to the log
+type ReportController struct {
+	responder *web.Responder
+	logger    flamingo.Logger
+}
+
+// Inject dependencies
+func (c *ReportController) Inject(responder *web.Responder, logger flamingo.Logger) *ReportController {
+	c.responder = responder
+	c.logger = logger.WithField(flamingo.LogKeyModule, "core.securityheaders").WithField(flamingo.LogKeyCategory, "report")
+	return c
+}
+
+// Report logs the received violation report
+func (c *ReportController) Report(ctx context.Context, r *web.Request) web.Result {
+	body, err := ioutil.ReadAll(io.LimitReader(r.Request().Body, maxReportSize))
+	if err != nil {
+		return c.responder.HTTP(http.StatusBadRequest, nil)
+	}
+
+	c.logger.WithContext(ctx).WithFields(map[flamingo.LogKey]interface{}{
+		"content_type": r.Request().Header.Get("Content-Type"),
+		"csp_report":   string(body),
+	}).Warn("content security policy violation reported")
+
+	return c.responder.HTTP(http.StatusNoContent, nil)
+}
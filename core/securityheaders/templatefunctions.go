@@ -0,0 +1,19 @@
+package securityheaders
+
+import (
+	"context"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+)
+
+// nonceFunc exposes the CSP nonce of the current request to templates
+type nonceFunc struct{}
+
+var _ flamingo.TemplateFunc = new(nonceFunc)
+
+// Func returns the cspNonce template function
+func (*nonceFunc) Func(ctx context.Context) interface{} {
+	return func() string {
+		return Nonce(ctx)
+	}
+}
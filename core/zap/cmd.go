@@ -0,0 +1,109 @@
+package zap
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// levelCmd shows and changes the log levels of a running instance through its system endpoint
+func levelCmd(address, endpoint string) *cobra.Command {
+	var module, category, revert string
+	var reset bool
+
+	if strings.HasPrefix(address, ":") {
+		address = "localhost" + address
+	}
+
+	cmd := &cobra.Command{
+		Use:   "loglevel [level]",
+		Short: "Show or change the log levels of a running instance",
+		Long: `Show or change the log levels of a running instance, e.g.
+
+	loglevel Debug --module core.oauth --revert 10m
+	loglevel --module core.oauth --reset`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := url.Values{}
+			query.Set("module", module)
+			query.Set("category", category)
+
+			method := http.MethodGet
+			switch {
+			case reset:
+				method = http.MethodDelete
+			case len(args) == 1:
+				if _, err := ParseLevel(args[0]); err != nil {
+					return err
+				}
+				method = http.MethodPost
+				query.Set("level", args[0])
+				query.Set("revert", revert)
+			}
+
+			req, err := http.NewRequest(method, "http://"+address+endpoint+"?"+query.Encode(), nil)
+			if err != nil {
+				return err
+			}
+
+			client := &http.Client{Timeout: 10 * time.Second}
+			resp, err := client.Do(req)
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				body, _ := ioutil.ReadAll(resp.Body)
+				return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+			}
+
+			var status LevelStatus
+			if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+				return err
+			}
+
+			return printLevels(cmd, status)
+		},
+	}
+
+	cmd.Flags().StringVar(&address, "address", address, "address of the system endpoint")
+	cmd.Flags().StringVar(&module, "module", "", "module to change, e.g. core.oauth")
+	cmd.Flags().StringVar(&category, "category", "", "category to change")
+	cmd.Flags().StringVar(&revert, "revert", "", "restore the configured level after this duration, e.g. 10m")
+	cmd.Flags().BoolVar(&reset, "reset", false, "restore the configured level")
+
+	return cmd
+}
+
+func printLevels(cmd *cobra.Command, status LevelStatus) error {
+	revertAt := func(t *time.Time) string {
+		if t == nil {
+			return "-"
+		}
+		return t.Format(time.RFC3339)
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "MODULE\tCATEGORY\tLEVEL\tREVERT AT")
+	fmt.Fprintf(w, "*\t*\t%s\t%s\n", status.Level, revertAt(status.RevertAt))
+	for _, level := range status.Modules {
+		module, category := level.Module, level.Category
+		if module == "" {
+			module = "*"
+		}
+		if category == "" {
+			category = "*"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", module, category, level.Level, revertAt(level.RevertAt))
+	}
+
+	return w.Flush()
+}
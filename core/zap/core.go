@@ -0,0 +1,62 @@
+package zap
+
+import (
+	"fmt"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// the module and category of a logger are passed to the levelCore as skipped fields, so they are independent
+// of the field map and not encoded twice
+const (
+	levelModuleKey   = "flamingo.zap.level.module"
+	levelCategoryKey = "flamingo.zap.level.category"
+)
+
+// levelCore checks the Levels with the module and category of the logger before the wrapped core is asked
+type levelCore struct {
+	zapcore.Core
+	levels   *Levels
+	module   string
+	category string
+}
+
+var _ zapcore.Core = new(levelCore)
+
+func levelField(key string, value interface{}) zapcore.Field {
+	return zapcore.Field{Key: key, Type: zapcore.SkipType, String: fmt.Sprint(value)}
+}
+
+// Enabled checks the level of the module and category and the level of the wrapped core
+func (c *levelCore) Enabled(level zapcore.Level) bool {
+	return c.levels.Enabled(c.module, c.category, level) && c.Core.Enabled(level)
+}
+
+// With adds fields to the wrapped core and picks up the module and category
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	for _, field := range fields {
+		if field.Type != zapcore.SkipType {
+			continue
+		}
+
+		switch field.Key {
+		case levelModuleKey:
+			clone.module = field.String
+		case levelCategoryKey:
+			clone.category = field.String
+		}
+	}
+	clone.Core = c.Core.With(fields)
+
+	return &clone
+}
+
+// Check the level of the module and category, the wrapped core decides about the rest (e.g. sampling)
+func (c *levelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(entry.Level) {
+		return checked
+	}
+
+	return c.Core.Check(entry, checked)
+}
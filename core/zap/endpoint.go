@@ -0,0 +1,58 @@
+package zap
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// LevelEndpoint shows and changes the log levels on the system endpoint:
+//
+//	GET    /loglevel                                            shows the levels
+//	POST   /loglevel?level=Debug&module=core.oauth&revert=10m  changes a level
+//	DELETE /loglevel?module=core.oauth                          restores the configured level
+//
+// Without module and category the global level is changed.
+type LevelEndpoint struct {
+	levels *Levels
+}
+
+// Inject dependencies
+func (e *LevelEndpoint) Inject(levels *Levels) *LevelEndpoint {
+	e.levels = levels
+	return e
+}
+
+// ServeHTTP handles the level requests
+func (e *LevelEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	module, category := r.FormValue("module"), r.FormValue("category")
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost, http.MethodPut:
+		level, err := ParseLevel(r.FormValue("level"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var revert time.Duration
+		if r.FormValue("revert") != "" {
+			if revert, err = time.ParseDuration(r.FormValue("revert")); err != nil {
+				http.Error(w, "invalid revert duration: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		e.levels.Set(module, category, level, revert)
+	case http.MethodDelete:
+		e.levels.Reset(module, category)
+	default:
+		w.Header().Set("Allow", "GET, POST, PUT, DELETE")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(e.levels.Status())
+}
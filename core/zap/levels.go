@@ -0,0 +1,233 @@
+package zap
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type (
+	// Levels controls the log levels at runtime, globally and per module and category.
+	// A logger with the fields module and category uses the first level found for
+	// module and category, module only and category only, and falls back to the global level.
+	Levels struct {
+		global zap.AtomicLevel
+		// overrides holds a map[selector]zapcore.Level, it is replaced on every change so Enabled does not need a lock
+		overrides atomic.Value
+
+		mu                  sync.Mutex
+		configuredLevel     zapcore.Level
+		configuredOverrides map[selector]zapcore.Level
+		timers              map[selector]*time.Timer
+		revertAt            map[selector]time.Time
+	}
+
+	selector struct {
+		module, category string
+	}
+
+	// LevelStatus describes the current log levels
+	LevelStatus struct {
+		Level    string        `json:"level"`
+		RevertAt *time.Time    `json:"revertAt,omitempty"`
+		Modules  []ModuleLevel `json:"modules"`
+	}
+
+	// ModuleLevel is the log level of a module and/or category
+	ModuleLevel struct {
+		Module   string     `json:"module,omitempty"`
+		Category string     `json:"category,omitempty"`
+		Level    string     `json:"level"`
+		RevertAt *time.Time `json:"revertAt,omitempty"`
+	}
+)
+
+// ErrUnknownLevel is returned for level names which are not one of Debug, Info, Warn, Error, DPanic, Panic or Fatal
+var ErrUnknownLevel = errors.New("unknown log level")
+
+// ParseLevel returns the zap level of a level name like "Debug", ignoring the case
+func ParseLevel(name string) (zapcore.Level, error) {
+	for levelName, level := range logLevels {
+		if strings.EqualFold(levelName, name) {
+			return level, nil
+		}
+	}
+
+	return zapcore.InfoLevel, fmt.Errorf("%w %q", ErrUnknownLevel, name)
+}
+
+func levelName(level zapcore.Level) string {
+	for name, l := range logLevels {
+		if l == level {
+			return name
+		}
+	}
+
+	return level.String()
+}
+
+// NewLevels creates the level control with the configured global level
+func NewLevels(level zapcore.Level) *Levels {
+	l := &Levels{
+		global:              zap.NewAtomicLevelAt(level),
+		configuredLevel:     level,
+		configuredOverrides: make(map[selector]zapcore.Level),
+		timers:              make(map[selector]*time.Timer),
+		revertAt:            make(map[selector]time.Time),
+	}
+	l.overrides.Store(make(map[selector]zapcore.Level))
+
+	return l
+}
+
+// configure the level of a module and/or category, Reset restores this level
+func (l *Levels) configure(module, category string, level zapcore.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	s := selector{module: module, category: category}
+	if s == (selector{}) {
+		l.configuredLevel = level
+	} else {
+		l.configuredOverrides[s] = level
+	}
+	l.set(s, &level)
+}
+
+// Enabled checks if a log entry of a logger with the module and category fields is logged
+func (l *Levels) Enabled(module, category string, level zapcore.Level) bool {
+	overrides := l.overrides.Load().(map[selector]zapcore.Level)
+	if len(overrides) > 0 && (module != "" || category != "") {
+		for _, s := range [...]selector{{module, category}, {module, ""}, {"", category}} {
+			if min, ok := overrides[s]; ok {
+				return min.Enabled(level)
+			}
+		}
+	}
+
+	return l.global.Enabled(level)
+}
+
+// Set changes the level of a module and/or category, or the global level if both are empty.
+// If revert is positive the configured level is restored after this duration.
+func (l *Levels) Set(module, category string, level zapcore.Level, revert time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	s := selector{module: module, category: category}
+	l.stopTimer(s)
+	l.set(s, &level)
+
+	if revert > 0 {
+		var timer *time.Timer
+		timer = time.AfterFunc(revert, func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+
+			// the level has been changed again in the meantime
+			if l.timers[s] != timer {
+				return
+			}
+			l.reset(s)
+		})
+		l.timers[s] = timer
+		l.revertAt[s] = time.Now().Add(revert)
+	}
+}
+
+// Reset restores the configured level of a module and/or category, or the global level if both are empty
+func (l *Levels) Reset(module, category string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.reset(selector{module: module, category: category})
+}
+
+// Status returns the global level and all module levels
+func (l *Levels) Status() LevelStatus {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	status := LevelStatus{
+		Level:    levelName(l.global.Level()),
+		RevertAt: l.revertTime(selector{}),
+		Modules:  make([]ModuleLevel, 0),
+	}
+
+	for s, level := range l.overrides.Load().(map[selector]zapcore.Level) {
+		status.Modules = append(status.Modules, ModuleLevel{
+			Module:   s.module,
+			Category: s.category,
+			Level:    levelName(level),
+			RevertAt: l.revertTime(s),
+		})
+	}
+
+	sort.Slice(status.Modules, func(i, j int) bool {
+		if status.Modules[i].Module != status.Modules[j].Module {
+			return status.Modules[i].Module < status.Modules[j].Module
+		}
+		return status.Modules[i].Category < status.Modules[j].Category
+	})
+
+	return status
+}
+
+func (l *Levels) reset(s selector) {
+	l.stopTimer(s)
+
+	if s == (selector{}) {
+		l.set(s, &l.configuredLevel)
+		return
+	}
+
+	if level, ok := l.configuredOverrides[s]; ok {
+		l.set(s, &level)
+		return
+	}
+	l.set(s, nil)
+}
+
+// set the level of the selector, nil removes a module level
+func (l *Levels) set(s selector, level *zapcore.Level) {
+	if s == (selector{}) {
+		l.global.SetLevel(*level)
+		return
+	}
+
+	current := l.overrides.Load().(map[selector]zapcore.Level)
+	overrides := make(map[selector]zapcore.Level, len(current)+1)
+	for k, v := range current {
+		overrides[k] = v
+	}
+
+	if level == nil {
+		delete(overrides, s)
+	} else {
+		overrides[s] = *level
+	}
+	l.overrides.Store(overrides)
+}
+
+func (l *Levels) stopTimer(s selector) {
+	if timer, ok := l.timers[s]; ok {
+		timer.Stop()
+		delete(l.timers, s)
+		delete(l.revertAt, s)
+	}
+}
+
+func (l *Levels) revertTime(s selector) *time.Time {
+	if t, ok := l.revertAt[s]; ok {
+		return &t
+	}
+
+	return nil
+}
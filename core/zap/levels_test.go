@@ -0,0 +1,142 @@
+package zap
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLevels_Enabled(t *testing.T) {
+	levels := NewLevels(zap.InfoLevel)
+	levels.configure("core.oauth", "", zap.ErrorLevel)
+	levels.configure("core.oauth", "session", zap.DebugLevel)
+	levels.configure("", "cache", zap.WarnLevel)
+
+	tests := []struct {
+		module, category string
+		level            zapcore.Level
+		want             bool
+	}{
+		{"", "", zap.DebugLevel, false},
+		{"", "", zap.InfoLevel, true},
+		{"core.oauth", "", zap.WarnLevel, false},
+		{"core.oauth", "", zap.ErrorLevel, true},
+		{"core.oauth", "session", zap.DebugLevel, true},
+		{"core.oauth", "cache", zap.WarnLevel, false},
+		{"core.cache", "cache", zap.InfoLevel, false},
+		{"core.cache", "cache", zap.WarnLevel, true},
+		{"core.cache", "", zap.InfoLevel, true},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, levels.Enabled(tt.module, tt.category, tt.level), "%s/%s %s", tt.module, tt.category, tt.level)
+	}
+}
+
+func TestLevels_SetReset(t *testing.T) {
+	levels := NewLevels(zap.InfoLevel)
+	levels.configure("core.oauth", "", zap.ErrorLevel)
+
+	levels.Set("", "", zap.DebugLevel, 0)
+	levels.Set("core.oauth", "", zap.InfoLevel, 0)
+	levels.Set("core.cache", "", zap.WarnLevel, 0)
+
+	assert.True(t, levels.Enabled("", "", zap.DebugLevel))
+	assert.True(t, levels.Enabled("core.oauth", "", zap.InfoLevel))
+	assert.False(t, levels.Enabled("core.cache", "", zap.InfoLevel))
+	assert.Equal(t, LevelStatus{
+		Level: "Debug",
+		Modules: []ModuleLevel{
+			{Module: "core.cache", Level: "Warn"},
+			{Module: "core.oauth", Level: "Info"},
+		},
+	}, levels.Status())
+
+	levels.Reset("", "")
+	levels.Reset("core.oauth", "")
+	levels.Reset("core.cache", "")
+
+	assert.Equal(t, LevelStatus{
+		Level:   "Info",
+		Modules: []ModuleLevel{{Module: "core.oauth", Level: "Error"}},
+	}, levels.Status())
+}
+
+func TestLevels_Revert(t *testing.T) {
+	levels := NewLevels(zap.InfoLevel)
+
+	levels.Set("core.oauth", "", zap.DebugLevel, 20*time.Millisecond)
+	status := levels.Status()
+	require.Len(t, status.Modules, 1)
+	assert.NotNil(t, status.Modules[0].RevertAt)
+	assert.True(t, levels.Enabled("core.oauth", "", zap.DebugLevel))
+
+	assert.Eventually(t, func() bool {
+		return !levels.Enabled("core.oauth", "", zap.DebugLevel)
+	}, time.Second, 5*time.Millisecond)
+	assert.Empty(t, levels.Status().Modules)
+
+	t.Run("a later change cancels the revert", func(t *testing.T) {
+		levels.Set("", "", zap.DebugLevel, 20*time.Millisecond)
+		levels.Set("", "", zap.WarnLevel, 0)
+
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, "Warn", levels.Status().Level)
+		assert.Nil(t, levels.Status().RevertAt)
+	})
+}
+
+func TestLogger_ModuleLevels(t *testing.T) {
+	levels := NewLevels(zap.WarnLevel)
+	observed, logs := observer.New(zap.DebugLevel)
+	logger := &Logger{Logger: zap.New(&levelCore{Core: observed, levels: levels})}
+
+	oauthLogger := logger.WithField(flamingo.LogKeyModule, "core.oauth")
+	sessionLogger := oauthLogger.WithFields(map[flamingo.LogKey]interface{}{flamingo.LogKeyCategory: "session"})
+
+	oauthLogger.Info("info")
+	sessionLogger.Info("info")
+	assert.Equal(t, 0, logs.Len())
+
+	levels.Set("core.oauth", "", zap.InfoLevel, 0)
+	levels.Set("core.oauth", "session", zap.DebugLevel, 0)
+
+	logger.Info("root")
+	oauthLogger.Info("oauth")
+	oauthLogger.Debug("oauth")
+	sessionLogger.Debug("session")
+
+	entries := logs.TakeAll()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "oauth", entries[0].Message)
+	assert.Equal(t, "session", entries[1].Message)
+	assert.Equal(t, map[string]interface{}{"module": "core.oauth", "category": "session"}, entries[1].ContextMap())
+}
+
+func TestLevelEndpoint(t *testing.T) {
+	levels := NewLevels(zap.InfoLevel)
+	endpoint := new(LevelEndpoint).Inject(levels)
+
+	rec := httptest.NewRecorder()
+	endpoint.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/loglevel?level=debug&module=core.oauth&revert=1h", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"module":"core.oauth","level":"Debug","revertAt"`)
+	assert.True(t, levels.Enabled("core.oauth", "", zap.DebugLevel))
+
+	rec = httptest.NewRecorder()
+	endpoint.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/loglevel?level=verbose", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	endpoint.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/loglevel?module=core.oauth", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"level":"Info","modules":[]}`, rec.Body.String())
+}
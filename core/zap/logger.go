@@ -6,6 +6,7 @@ import (
 
 	"flamingo.me/flamingo/v3/framework/flamingo"
 	"flamingo.me/flamingo/v3/framework/opencensus"
+	"flamingo.me/flamingo/v3/framework/redact"
 	"flamingo.me/flamingo/v3/framework/web"
 	"go.opencensus.io/stats"
 	"go.opencensus.io/stats/view"
@@ -33,6 +34,7 @@ type (
 		configArea string
 		fieldMap   map[string]string
 		logSession bool
+		redactor   *redact.Redactor
 	}
 )
 
@@ -76,45 +78,56 @@ func (l *Logger) record(level string) {
 // Debug logs a message at debug level
 func (l *Logger) Debug(args ...interface{}) {
 	l.record("Debug")
-	l.Logger.Debug(fmt.Sprint(args...))
+	// skip the redaction of disabled messages
+	if l.Logger.Core().Enabled(zap.DebugLevel) {
+		l.Logger.Debug(l.redactor.String(fmt.Sprint(args...)))
+	}
 }
 
 // Debugf logs a message at debug level with format string
 func (l *Logger) Debugf(log string, args ...interface{}) {
 	l.record("Debug")
-	l.Logger.Debug(fmt.Sprintf(log, args...))
+	if l.Logger.Core().Enabled(zap.DebugLevel) {
+		l.Logger.Debug(l.redactor.String(fmt.Sprintf(log, args...)))
+	}
 }
 
 // Info logs a message at info level
 func (l *Logger) Info(args ...interface{}) {
 	l.record("Info")
-	l.Logger.Info(fmt.Sprint(args...))
+	if l.Logger.Core().Enabled(zap.InfoLevel) {
+		l.Logger.Info(l.redactor.String(fmt.Sprint(args...)))
+	}
 }
 
 // Warn logs a message at warn level
 func (l *Logger) Warn(args ...interface{}) {
 	l.record("Warn")
-	l.Logger.Warn(fmt.Sprint(args...))
+	if l.Logger.Core().Enabled(zap.WarnLevel) {
+		l.Logger.Warn(l.redactor.String(fmt.Sprint(args...)))
+	}
 }
 
 // Error logs a message at error level
 func (l *Logger) Error(args ...interface{}) {
 	l.record("Error")
-	l.Logger.Error(fmt.Sprint(args...))
+	if l.Logger.Core().Enabled(zap.ErrorLevel) {
+		l.Logger.Error(l.redactor.String(fmt.Sprint(args...)))
+	}
 }
 
 // Fatal logs a message at fatal level
 // The logger then calls os.Exit(1), even if logging at FatalLevel is disabled.
 func (l *Logger) Fatal(args ...interface{}) {
 	l.record("Fatal")
-	l.Logger.Fatal(fmt.Sprint(args...))
+	l.Logger.Fatal(l.redactor.String(fmt.Sprint(args...)))
 }
 
 // Panic logs a message at panic level
 // The logger then panics, even if logging at PanicLevel is disabled.
 func (l *Logger) Panic(args ...interface{}) {
 	l.record("Panic")
-	l.Logger.Panic(fmt.Sprint(args...))
+	l.Logger.Panic(l.redactor.String(fmt.Sprint(args...)))
 }
 
 // WithField creates a child logger and adds structured context to it.
@@ -124,26 +137,37 @@ func (l *Logger) WithField(key flamingo.LogKey, value interface{}) flamingo.Logg
 		area = value.(string)
 	}
 
-	if alias, ok := l.fieldMap[string(key)]; ok {
-		// skip field
-		if alias == "-" {
-			return l
-		}
+	zapFields := make([]zapcore.Field, 0, 2)
+	if field, ok := levelFieldFor(key, value); ok {
+		zapFields = append(zapFields, field)
+	}
+
+	value = l.redactor.Value(string(key), value)
+	alias, ok := l.fieldMap[string(key)]
+	switch {
+	case !ok:
+		zapFields = append(zapFields, zap.Any(string(key), value))
+	case alias != "-": // "-" skips the field
+		zapFields = append(zapFields, zap.Any(alias, value))
+	}
 
-		key = flamingo.LogKey(alias)
+	if len(zapFields) == 0 {
+		return l
 	}
 
 	return &Logger{
-		Logger:     l.Logger.With(zap.Any(string(key), value)),
+		Logger:     l.Logger.With(zapFields...),
 		configArea: area,
 		fieldMap:   l.fieldMap,
 		logSession: l.logSession,
+		redactor:   l.redactor,
 	}
 }
 
 // WithFields creates a child logger and adds structured context to it.
 func (l *Logger) WithFields(fields map[flamingo.LogKey]interface{}) flamingo.Logger {
 	zapFields := make([]zapcore.Field, len(fields))
+	var levelFields []zapcore.Field
 	area := l.configArea
 	i := 0
 	for key, value := range fields {
@@ -151,6 +175,11 @@ func (l *Logger) WithFields(fields map[flamingo.LogKey]interface{}) flamingo.Log
 			area = value.(string)
 		}
 
+		if field, ok := levelFieldFor(key, value); ok {
+			levelFields = append(levelFields, field)
+		}
+
+		value = l.redactor.Value(string(key), value)
 		if alias, ok := l.fieldMap[string(key)]; ok {
 			// skip field
 			if alias == "-" {
@@ -164,14 +193,29 @@ func (l *Logger) WithFields(fields map[flamingo.LogKey]interface{}) flamingo.Log
 		i++
 	}
 
+	zapFields = append(zapFields[:i], levelFields...)
+
 	return &Logger{
-		Logger:     l.Logger.With(zapFields[:i]...),
+		Logger:     l.Logger.With(zapFields...),
 		configArea: area,
 		fieldMap:   l.fieldMap,
 		logSession: l.logSession,
+		redactor:   l.redactor,
 	}
 }
 
+// levelFieldFor passes the module and category to the levelCore
+func levelFieldFor(key flamingo.LogKey, value interface{}) (zapcore.Field, bool) {
+	switch key {
+	case flamingo.LogKeyModule:
+		return levelField(levelModuleKey, value), true
+	case flamingo.LogKeyCategory:
+		return levelField(levelCategoryKey, value), true
+	}
+
+	return zapcore.Field{}, false
+}
+
 // Flush is used by buffered loggers and triggers the actual writing. It is a good habit to call Flush before
 // letting the process exit. For the top level flamingo.Logger, this is called by the app itself.
 func (l *Logger) Flush() {
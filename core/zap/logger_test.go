@@ -0,0 +1,31 @@
+package zap
+
+import (
+	"errors"
+	"testing"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/redact"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLogger_Redaction(t *testing.T) {
+	redactor, err := redact.New(redact.Options{Keys: []string{"(?i)password"}, Detectors: []string{"email"}})
+	require.NoError(t, err)
+
+	observed, logs := observer.New(zap.DebugLevel)
+	logger := &Logger{Logger: zap.New(observed), redactor: redactor}
+
+	logger.
+		WithField("password", "hunter2").
+		WithFields(map[flamingo.LogKey]interface{}{"user": "jane@example.com", "error": errors.New("not found")}).
+		Info("login of jane@example.com with password=hunter2 failed")
+
+	entries := logs.TakeAll()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "login of [REDACTED] with password=[REDACTED] failed", entries[0].Message)
+	assert.Equal(t, map[string]interface{}{"password": "[REDACTED]", "user": "[REDACTED]", "error": "not found"}, entries[0].ContextMap())
+}
@@ -2,10 +2,15 @@ package zap
 
 import (
 	"context"
+	"fmt"
+	"os"
 
 	"flamingo.me/dingo"
 	"flamingo.me/flamingo/v3/framework/config"
 	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/redact"
+	"flamingo.me/flamingo/v3/framework/systemendpoint/domain"
+	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -23,6 +28,17 @@ type (
 		samplingThereafter float64
 		fieldMap           map[string]string
 		logSession         bool
+		moduleLevels       []moduleLevelConfig
+		levelEndpoint      string
+		systemEndpoint     string
+		redactor           *redact.Redactor
+		sinks              []sinkConfig
+	}
+
+	moduleLevelConfig struct {
+		Module   string
+		Category string
+		Level    string
 	}
 
 	shutdownEventSubscriber struct {
@@ -41,18 +57,23 @@ var logLevels = map[string]zapcore.Level{
 }
 
 // Inject dependencies
-func (m *Module) Inject(config *struct {
-	Area               string     `inject:"config:area"`
-	JSON               bool       `inject:"config:core.zap.json,optional"`
-	LogLevel           string     `inject:"config:core.zap.loglevel,optional"`
-	ColoredOutput      bool       `inject:"config:core.zap.colored,optional"`
-	DevelopmentMode    bool       `inject:"config:core.zap.devmode,optional"`
-	SamplingEnabled    bool       `inject:"config:core.zap.sampling.enabled,optional"`
-	SamplingInitial    float64    `inject:"config:core.zap.sampling.initial,optional"`
-	SamplingThereafter float64    `inject:"config:core.zap.sampling.thereafter,optional"`
-	FieldMap           config.Map `inject:"config:core.zap.fieldmap,optional"`
-	LogSession         bool       `inject:"config:core.zap.logsession,optional"`
+func (m *Module) Inject(redactor *redact.Redactor, config *struct {
+	Area               string       `inject:"config:area"`
+	JSON               bool         `inject:"config:core.zap.json,optional"`
+	LogLevel           string       `inject:"config:core.zap.loglevel,optional"`
+	ColoredOutput      bool         `inject:"config:core.zap.colored,optional"`
+	DevelopmentMode    bool         `inject:"config:core.zap.devmode,optional"`
+	SamplingEnabled    bool         `inject:"config:core.zap.sampling.enabled,optional"`
+	SamplingInitial    float64      `inject:"config:core.zap.sampling.initial,optional"`
+	SamplingThereafter float64      `inject:"config:core.zap.sampling.thereafter,optional"`
+	FieldMap           config.Map   `inject:"config:core.zap.fieldmap,optional"`
+	LogSession         bool         `inject:"config:core.zap.logsession,optional"`
+	Levels             config.Slice `inject:"config:core.zap.levels,optional"`
+	LevelEndpoint      string       `inject:"config:core.zap.levelEndpoint,optional"`
+	SystemEndpoint     string       `inject:"config:flamingo.systemendpoint.serviceAddr,optional"`
+	Sinks              config.Slice `inject:"config:core.zap.sinks,optional"`
 }) {
+	m.redactor = redactor
 	m.area = config.Area
 	m.json = config.JSON
 	m.logLevel = config.LogLevel
@@ -62,6 +83,11 @@ func (m *Module) Inject(config *struct {
 	m.samplingInitial = config.SamplingInitial
 	m.samplingThereafter = config.SamplingThereafter
 	m.logSession = config.LogSession
+	m.levelEndpoint = config.LevelEndpoint
+	m.systemEndpoint = config.SystemEndpoint
+	if m.systemEndpoint == "" {
+		m.systemEndpoint = ":13210"
+	}
 	if config.FieldMap != nil {
 		m.fieldMap = make(map[string]string, len(config.FieldMap))
 		for k, v := range config.FieldMap {
@@ -70,6 +96,16 @@ func (m *Module) Inject(config *struct {
 			}
 		}
 	}
+	if config.Levels != nil {
+		if err := config.Levels.MapInto(&m.moduleLevels); err != nil {
+			panic(fmt.Errorf("core.zap.levels: %w", err))
+		}
+	}
+	if config.Sinks != nil {
+		if err := config.Sinks.MapInto(&m.sinks); err != nil {
+			panic(fmt.Errorf("core.zap.sinks: %w", err))
+		}
+	}
 }
 
 // Configure the logrus logger as flamingo.Logger (in JSON mode kibana compatible)
@@ -80,50 +116,17 @@ func (m *Module) Configure(injector *dingo.Injector) {
 		level = zap.ErrorLevel
 	}
 
-	var samplingConfig *zap.SamplingConfig
-
-	if m.samplingEnabled && m.samplingThereafter > 0 && m.samplingInitial > 0 {
-		samplingConfig = &zap.SamplingConfig{
-			Initial:    int(m.samplingInitial),
-			Thereafter: int(m.samplingThereafter),
+	levels := NewLevels(level)
+	for _, moduleLevel := range m.moduleLevels {
+		l, err := ParseLevel(moduleLevel.Level)
+		if err != nil {
+			panic(fmt.Errorf("core.zap.levels: %w", err))
 		}
+		levels.configure(moduleLevel.Module, moduleLevel.Category, l)
 	}
 
-	output := "console"
-	if m.json {
-		output = "json"
-	}
-	encoder := zapcore.CapitalLevelEncoder
-	if m.coloredOutput {
-		encoder = zapcore.CapitalColorLevelEncoder
-	}
-	cfg := zap.Config{
-		Level:             zap.NewAtomicLevelAt(level),
-		Development:       m.developmentMode,
-		DisableCaller:     false,
-		DisableStacktrace: false,
-		Sampling:          samplingConfig,
-		Encoding:          output,
-		EncoderConfig: zapcore.EncoderConfig{
-			MessageKey:     flamingo.LogKeyMessage,
-			LevelKey:       flamingo.LogKeyLevel,
-			TimeKey:        flamingo.LogKeyTimestamp,
-			NameKey:        "logger",
-			CallerKey:      flamingo.LogKeySource,
-			StacktraceKey:  flamingo.LogKeyTrace,
-			LineEnding:     zapcore.DefaultLineEnding,
-			EncodeLevel:    encoder,
-			EncodeTime:     zapcore.ISO8601TimeEncoder,
-			EncodeDuration: zapcore.SecondsDurationEncoder,
-			EncodeCaller:   zapcore.ShortCallerEncoder,
-			EncodeName:     zapcore.FullNameEncoder,
-		},
-		OutputPaths:      []string{"stderr"},
-		ErrorOutputPaths: []string{"stderr"},
-		InitialFields:    nil,
-	}
-
-	logger, err := cfg.Build(zap.AddCallerSkip(1))
+	recorder := new(Recorder)
+	logger, err := m.newLogger(levels, recorder)
 	if err != nil {
 		panic(err)
 	}
@@ -133,14 +136,62 @@ func (m *Module) Configure(injector *dingo.Injector) {
 		fieldMap:   m.fieldMap,
 		logSession: m.logSession,
 		configArea: m.area,
+		redactor:   m.redactor,
 	}
 
 	zapLogger = zapLogger.WithField(flamingo.LogKeyArea, m.area).(*Logger)
 
 	injector.Bind(new(flamingo.Logger)).ToInstance(zapLogger)
+	injector.Bind(new(Levels)).ToInstance(levels)
+	injector.Bind(new(Recorder)).ToInstance(recorder)
+	injector.BindMulti(new(cobra.Command)).ToProvider(func() *cobra.Command {
+		return levelCmd(m.systemEndpoint, m.levelEndpoint)
+	})
+	if m.levelEndpoint != "" {
+		injector.BindMap((*domain.Handler)(nil), m.levelEndpoint).To(&LevelEndpoint{})
+	}
 	flamingo.BindEventSubscriber(injector).To(shutdownEventSubscriber{})
 }
 
+// newLogger creates a core for every sink of core.zap.sinks, or a single sink to stderr configured by
+// core.zap.json, colored and sampling if no sinks are configured
+func (m *Module) newLogger(levels *Levels, recorder *Recorder) (*zap.Logger, error) {
+	sinks := m.sinks
+	if len(sinks) == 0 {
+		sink := sinkConfig{Output: "stderr", JSON: m.json, Colored: m.coloredOutput}
+		sink.Sampling.Enabled = m.samplingEnabled
+		sink.Sampling.Initial = int(m.samplingInitial)
+		sink.Sampling.Thereafter = int(m.samplingThereafter)
+		sinks = []sinkConfig{sink}
+	}
+
+	cores := make([]zapcore.Core, len(sinks))
+	for i, sink := range sinks {
+		core, err := newSinkCore(sink, recorder)
+		if err != nil {
+			return nil, fmt.Errorf("core.zap.sinks[%d]: %w", i, err)
+		}
+		cores[i] = core
+	}
+
+	stacktraceLevel := zap.ErrorLevel
+	options := []zap.Option{
+		zap.AddCaller(),
+		zap.AddCallerSkip(1),
+		zap.ErrorOutput(zapcore.Lock(os.Stderr)),
+	}
+	if m.developmentMode {
+		stacktraceLevel = zap.WarnLevel
+		options = append(options, zap.Development())
+	}
+	options = append(options, zap.AddStacktrace(stacktraceLevel))
+
+	// the global and module levels are checked before the levels of the sinks
+	core := &levelCore{Core: zapcore.NewTee(cores...), levels: levels}
+
+	return zap.New(core, options...), nil
+}
+
 // Inject dependencies
 func (subscriber *shutdownEventSubscriber) Inject(logger flamingo.Logger) {
 	subscriber.logger = logger
@@ -162,6 +213,28 @@ func (m *Module) CueConfig() string {
 	return `
 core zap: {
 	loglevel: *"Debug" | "Info" | "Warn" | "Error" | "DPanic" | "Panic" | "Fatal"
+	sinks: [...{
+		output: string | *"stderr"
+		level: *"Debug" | "Info" | "Warn" | "Error" | "DPanic" | "Panic" | "Fatal"
+		json: bool | *false
+		colored: bool | *false
+		fieldmap: {}
+		rotate: {
+			maxSize: int | *0
+			maxBackups: int | *0
+		}
+		sampling: {
+			enabled: bool | *false
+			initial: int | *100
+			thereafter: int | *100
+		}
+	}]
+	levels: [...{
+		module: string | *""
+		category: string | *""
+		level: "Debug" | "Info" | "Warn" | "Error" | "DPanic" | "Panic" | "Fatal"
+	}]
+	levelEndpoint: string | *"/loglevel"
 	sampling: {
 		enabled: bool | *true
 		initial: int | *100 
@@ -171,6 +244,13 @@ core zap: {
 `
 }
 
+// Depends on the redact module
+func (*Module) Depends() []dingo.Module {
+	return []dingo.Module{
+		new(redact.Module),
+	}
+}
+
 // FlamingoLegacyConfigAlias mapping
 func (*Module) FlamingoLegacyConfigAlias() map[string]string {
 	return map[string]string{
@@ -0,0 +1,122 @@
+package zap
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type (
+	// Recorder captures log entries in memory for assertions in tests. It records the entries of the
+	// sinks with the output "memory", or of the logger created by NewTestLogger.
+	Recorder struct {
+		mu      sync.Mutex
+		entries []RecordedEntry
+	}
+
+	// RecordedEntry is a captured log entry with all fields of the logger
+	RecordedEntry struct {
+		Level   zapcore.Level
+		Time    time.Time
+		Message string
+		Fields  map[string]interface{}
+	}
+
+	recorderCore struct {
+		zapcore.LevelEnabler
+		recorder *Recorder
+		context  []zapcore.Field
+	}
+)
+
+// NewTestLogger creates a Logger which records all entries
+func NewTestLogger() (*Logger, *Recorder) {
+	recorder := new(Recorder)
+	levels := NewLevels(zap.DebugLevel)
+
+	return &Logger{Logger: zap.New(&levelCore{Core: recorder.core(zap.DebugLevel), levels: levels})}, recorder
+}
+
+// Entries returns the recorded entries
+func (r *Recorder) Entries() []RecordedEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries := make([]RecordedEntry, len(r.entries))
+	copy(entries, r.entries)
+
+	return entries
+}
+
+// Messages returns the messages of the recorded entries
+func (r *Recorder) Messages() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	messages := make([]string, len(r.entries))
+	for i, entry := range r.entries {
+		messages[i] = entry.Message
+	}
+
+	return messages
+}
+
+// Reset removes all recorded entries
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries = nil
+}
+
+func (r *Recorder) core(level zapcore.LevelEnabler) zapcore.Core {
+	return &recorderCore{LevelEnabler: level, recorder: r}
+}
+
+// With adds the fields to the context of the entries
+func (c *recorderCore) With(fields []zapcore.Field) zapcore.Core {
+	return &recorderCore{
+		LevelEnabler: c.LevelEnabler,
+		recorder:     c.recorder,
+		context:      append(c.context[:len(c.context):len(c.context)], fields...),
+	}
+}
+
+// Check adds the core if the level is enabled
+func (c *recorderCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+
+	return checked
+}
+
+// Write records the entry
+func (c *recorderCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	encoder := zapcore.NewMapObjectEncoder()
+	for _, field := range c.context {
+		field.AddTo(encoder)
+	}
+	for _, field := range fields {
+		field.AddTo(encoder)
+	}
+
+	c.recorder.mu.Lock()
+	defer c.recorder.mu.Unlock()
+
+	c.recorder.entries = append(c.recorder.entries, RecordedEntry{
+		Level:   entry.Level,
+		Time:    entry.Time,
+		Message: entry.Message,
+		Fields:  encoder.Fields,
+	})
+
+	return nil
+}
+
+// Sync does nothing
+func (c *recorderCore) Sync() error {
+	return nil
+}
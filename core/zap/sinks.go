@@ -0,0 +1,160 @@
+package zap
+
+import (
+	"time"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/logfile"
+	"go.uber.org/zap/zapcore"
+)
+
+type (
+	// sinkConfig is an entry of core.zap.sinks
+	sinkConfig struct {
+		// Output is stdout, stderr, memory or the path of a file
+		Output string
+		Level  string
+		JSON   bool
+		// Colored levels of the console encoder
+		Colored bool
+		// FieldMap renames the fields of this sink, "-" removes a field
+		FieldMap map[string]string
+		Rotate   struct {
+			// MaxSize in megabytes
+			MaxSize    int64
+			MaxBackups int
+		}
+		Sampling struct {
+			Enabled    bool
+			Initial    int
+			Thereafter int
+		}
+	}
+
+	// fieldMapCore renames the fields of a sink
+	fieldMapCore struct {
+		zapcore.Core
+		fieldMap map[string]string
+	}
+)
+
+// memoryOutput captures the entries of a sink with the Recorder of the module
+const memoryOutput = "memory"
+
+// newSinkCore creates the core of a sink, files are opened and memory sinks record into the recorder
+func newSinkCore(sink sinkConfig, recorder *Recorder) (zapcore.Core, error) {
+	level := zapcore.DebugLevel
+	if sink.Level != "" {
+		var err error
+		if level, err = ParseLevel(sink.Level); err != nil {
+			return nil, err
+		}
+	}
+
+	var core zapcore.Core
+	if sink.Output == memoryOutput {
+		core = recorder.core(level)
+	} else {
+		writer, err := logfile.Open(sink.Output, logfile.Options{
+			MaxSize:    sink.Rotate.MaxSize * 1024 * 1024,
+			MaxBackups: sink.Rotate.MaxBackups,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		encoderConfig := newEncoderConfig(sink)
+		encoder := zapcore.NewConsoleEncoder(encoderConfig)
+		if sink.JSON {
+			encoder = zapcore.NewJSONEncoder(encoderConfig)
+		}
+		core = zapcore.NewCore(encoder, writer, level)
+	}
+
+	if len(sink.FieldMap) > 0 {
+		core = &fieldMapCore{Core: core, fieldMap: sink.FieldMap}
+	}
+
+	if sink.Sampling.Enabled && sink.Sampling.Initial > 0 && sink.Sampling.Thereafter > 0 {
+		core = zapcore.NewSampler(core, time.Second, sink.Sampling.Initial, sink.Sampling.Thereafter)
+	}
+
+	return core, nil
+}
+
+// newEncoderConfig uses the flamingo keys, which can be renamed or removed by the field map of the sink
+func newEncoderConfig(sink sinkConfig) zapcore.EncoderConfig {
+	encodeLevel := zapcore.CapitalLevelEncoder
+	if sink.Colored {
+		encodeLevel = zapcore.CapitalColorLevelEncoder
+	}
+
+	encoderConfig := zapcore.EncoderConfig{
+		MessageKey:     flamingo.LogKeyMessage,
+		LevelKey:       flamingo.LogKeyLevel,
+		TimeKey:        flamingo.LogKeyTimestamp,
+		NameKey:        "logger",
+		CallerKey:      flamingo.LogKeySource,
+		StacktraceKey:  flamingo.LogKeyTrace,
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeLevel:    encodeLevel,
+		EncodeTime:     zapcore.ISO8601TimeEncoder,
+		EncodeDuration: zapcore.SecondsDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
+		EncodeName:     zapcore.FullNameEncoder,
+	}
+
+	for _, key := range []*string{
+		&encoderConfig.MessageKey,
+		&encoderConfig.LevelKey,
+		&encoderConfig.TimeKey,
+		&encoderConfig.NameKey,
+		&encoderConfig.CallerKey,
+		&encoderConfig.StacktraceKey,
+	} {
+		alias, ok := sink.FieldMap[*key]
+		switch {
+		case !ok:
+		case alias == "-": // an empty key omits the entry field
+			*key = ""
+		default:
+			*key = alias
+		}
+	}
+
+	return encoderConfig
+}
+
+// With renames the fields and adds them to the wrapped core
+func (c *fieldMapCore) With(fields []zapcore.Field) zapcore.Core {
+	return &fieldMapCore{Core: c.Core.With(c.rename(fields)), fieldMap: c.fieldMap}
+}
+
+// Check adds the fieldMapCore, so the fields of Write are renamed
+func (c *fieldMapCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+
+	return checked
+}
+
+// Write renames the fields and writes the entry to the wrapped core
+func (c *fieldMapCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(entry, c.rename(fields))
+}
+
+func (c *fieldMapCore) rename(fields []zapcore.Field) []zapcore.Field {
+	renamed := make([]zapcore.Field, 0, len(fields))
+	for _, field := range fields {
+		if alias, ok := c.fieldMap[field.Key]; ok {
+			if alias == "-" {
+				continue
+			}
+			field.Key = alias
+		}
+		renamed = append(renamed, field)
+	}
+
+	return renamed
+}
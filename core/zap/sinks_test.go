@@ -0,0 +1,89 @@
+package zap
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestModule_Sinks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "zap-sinks")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	jsonSink := sinkConfig{Output: filepath.Join(dir, "info.json"), Level: "Info", JSON: true, FieldMap: map[string]string{
+		flamingo.LogKeyMessage:   "msg",
+		flamingo.LogKeyTimestamp: "-",
+		flamingo.LogKeySource:    "-",
+		"user":                   "user_id",
+	}}
+	consoleSink := sinkConfig{Output: filepath.Join(dir, "logs", "debug.log"), Level: "Debug"}
+	errorSink := sinkConfig{Output: filepath.Join(dir, "error.log"), Level: "Error"}
+	memorySink := sinkConfig{Output: "memory", Level: "Warn", FieldMap: map[string]string{"user": "-"}}
+
+	module := &Module{sinks: []sinkConfig{jsonSink, consoleSink, errorSink, memorySink}}
+	recorder := new(Recorder)
+	zapLogger, err := module.newLogger(NewLevels(zap.DebugLevel), recorder)
+	require.NoError(t, err)
+
+	logger := (&Logger{Logger: zapLogger}).WithField("user", "42")
+	logger.Debug("debug message")
+	logger.Info("info message")
+	logger.Warn("warn message")
+	logger.Error("error message")
+	logger.Flush()
+
+	b, err := ioutil.ReadFile(jsonSink.Output)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	require.Len(t, lines, 3)
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+	assert.Equal(t, map[string]interface{}{"level": "INFO", "msg": "info message", "user_id": "42"}, entry)
+
+	b, err = ioutil.ReadFile(consoleSink.Output)
+	require.NoError(t, err)
+	assert.Equal(t, 4, strings.Count(string(b), " message"))
+	assert.Contains(t, string(b), "DEBUG")
+	assert.Contains(t, string(b), `{"user": "42"}`)
+
+	b, err = ioutil.ReadFile(errorSink.Output)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(b), " message"))
+	assert.Contains(t, string(b), "error message")
+
+	entries := recorder.Entries()
+	require.Len(t, entries, 2)
+	assert.Equal(t, zap.WarnLevel, entries[0].Level)
+	assert.Equal(t, "warn message", entries[0].Message)
+	assert.Empty(t, entries[0].Fields)
+	assert.Equal(t, []string{"warn message", "error message"}, recorder.Messages())
+}
+
+func TestModule_SinksInvalid(t *testing.T) {
+	module := &Module{sinks: []sinkConfig{{Output: "stderr", Level: "Verbose"}}}
+	_, err := module.newLogger(NewLevels(zap.DebugLevel), new(Recorder))
+	assert.Error(t, err)
+}
+
+func TestNewTestLogger(t *testing.T) {
+	logger, recorder := NewTestLogger()
+
+	logger.WithField(flamingo.LogKeyModule, "core.zap").Debugf("hello %s", "world")
+
+	entries := recorder.Entries()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "hello world", entries[0].Message)
+	assert.Equal(t, map[string]interface{}{"module": "core.zap"}, entries[0].Fields)
+
+	recorder.Reset()
+	assert.Empty(t, recorder.Entries())
+}
@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"flamingo.me/dingo"
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+)
+
+type (
+	// Lifecycle runs the Start and Stop hooks of the modules of an application
+	Lifecycle struct {
+		mu           sync.Mutex
+		logger       flamingo.Logger
+		startTimeout time.Duration
+		stopTimeout  time.Duration
+		running      bool
+		stoppers     []dingo.Module
+		stopped      chan struct{}
+	}
+)
+
+// Inject dependencies
+func (l *Lifecycle) Inject(logger flamingo.Logger, cfg *struct {
+	StartTimeout string `inject:"config:flamingo.lifecycle.startTimeout"`
+	StopTimeout  string `inject:"config:flamingo.lifecycle.stopTimeout"`
+}) *Lifecycle {
+	l.logger = logger.WithField(flamingo.LogKeyModule, "cmd").WithField(flamingo.LogKeyCategory, "lifecycle")
+	l.startTimeout = parseDuration(l.logger, "flamingo.lifecycle.startTimeout", cfg.StartTimeout)
+	l.stopTimeout = parseDuration(l.logger, "flamingo.lifecycle.stopTimeout", cfg.StopTimeout)
+	return l
+}
+
+// Start runs the Start hooks of all modules of the area and its parents, dependencies first.
+// If a hook fails, the already started modules are stopped and the error is returned.
+func (l *Lifecycle) Start(ctx context.Context, area *config.Area) error {
+	l.mu.Lock()
+	if l.running {
+		l.mu.Unlock()
+		return nil
+	}
+	l.running = true
+	l.mu.Unlock()
+
+	for _, module := range areaModules(area) {
+		if starter, ok := module.(flamingo.Starter); ok {
+			start := time.Now()
+			if err := l.run(ctx, module, l.startTimeout, starter.Start); err != nil {
+				err = fmt.Errorf("start %s: %w", moduleName(module), err)
+				l.logger.WithContext(ctx).Error(err)
+
+				if stopErr := l.Stop(ctx); stopErr != nil {
+					l.logger.WithContext(ctx).Error(stopErr)
+				}
+
+				return err
+			}
+			l.logger.WithContext(ctx).Debug(fmt.Sprintf("started %s in %v", moduleName(module), time.Since(start)))
+		}
+
+		if _, ok := module.(flamingo.Stopper); ok {
+			l.mu.Lock()
+			l.stoppers = append(l.stoppers, module)
+			l.mu.Unlock()
+		}
+	}
+
+	return nil
+}
+
+// Stop runs the Stop hooks of the started modules in reverse order.
+// All hooks are run, even if some of them fail.
+func (l *Lifecycle) Stop(ctx context.Context) error {
+	l.mu.Lock()
+	stoppers := l.stoppers
+	l.stoppers = nil
+	l.running = false
+	if l.stopped != nil {
+		close(l.stopped)
+		l.stopped = nil
+	}
+	l.mu.Unlock()
+
+	var failed []string
+	for i := len(stoppers) - 1; i >= 0; i-- {
+		module := stoppers[i]
+		if err := l.run(ctx, module, l.stopTimeout, module.(flamingo.Stopper).Stop); err != nil {
+			l.logger.WithContext(ctx).Error(fmt.Sprintf("stop %s: %v", moduleName(module), err))
+			failed = append(failed, moduleName(module))
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("stopping modules failed: %s", strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+// stoppedChan is closed by the next Stop
+func (l *Lifecycle) stoppedChan() <-chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.stopped == nil {
+		l.stopped = make(chan struct{})
+	}
+	return l.stopped
+}
+
+// run calls a hook with a timeout, panics are returned as error
+func (l *Lifecycle) run(ctx context.Context, module dingo.Module, timeout time.Duration, hook func(context.Context) error) error {
+	if t, ok := module.(flamingo.HookTimeouter); ok {
+		timeout = t.HookTimeout()
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- fmt.Errorf("panic: %v", p)
+			}
+		}()
+		done <- hook(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) && timeout > 0 {
+		return fmt.Errorf("timed out after %v", timeout)
+	}
+
+	return err
+}
+
+// areaModules returns the modules of the area and its parents, the root area's modules first
+func areaModules(area *config.Area) []dingo.Module {
+	if area == nil {
+		return nil
+	}
+
+	var areas []*config.Area
+	for a := area; a != nil; a = a.Parent {
+		areas = append([]*config.Area{a}, areas...)
+	}
+
+	known := make(map[interface{}]bool)
+	var modules []dingo.Module
+	for _, a := range areas {
+		for _, module := range a.Modules {
+			var identity interface{} = reflect.TypeOf(module)
+			if _, ok := module.(dingo.ModuleFunc); ok {
+				identity = reflect.ValueOf(module).Pointer()
+			}
+			if known[identity] {
+				continue
+			}
+			known[identity] = true
+			modules = append(modules, module)
+		}
+	}
+
+	return modules
+}
+
+func moduleName(module dingo.Module) string {
+	t := reflect.TypeOf(module)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.PkgPath() + "." + t.Name()
+}
+
+// parseDuration parses a configured duration, invalid durations disable the timeout
+func parseDuration(logger flamingo.Logger, key, value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		logger.Error(fmt.Sprintf("%s: invalid duration %q: %v", key, value, err))
+		return 0
+	}
+
+	return d
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"flamingo.me/dingo"
+	"github.com/stretchr/testify/assert"
+
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+)
+
+type (
+	hookRecorder struct {
+		calls []string
+	}
+
+	hookModule struct {
+		name     string
+		recorder *hookRecorder
+		startErr error
+		block    bool
+		timeout  time.Duration
+	}
+
+	stopOnlyModule struct {
+		recorder *hookRecorder
+	}
+
+	otherHookModule struct {
+		hookModule
+	}
+)
+
+func (*hookModule) Configure(*dingo.Injector) {}
+
+func (m *hookModule) Start(ctx context.Context) error {
+	m.recorder.calls = append(m.recorder.calls, "start "+m.name)
+	if m.block {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return m.startErr
+}
+
+func (m *hookModule) Stop(context.Context) error {
+	m.recorder.calls = append(m.recorder.calls, "stop "+m.name)
+	return nil
+}
+
+func (m *hookModule) HookTimeout() time.Duration {
+	return m.timeout
+}
+
+func (*stopOnlyModule) Configure(*dingo.Injector) {}
+
+func (m *stopOnlyModule) Stop(context.Context) error {
+	m.recorder.calls = append(m.recorder.calls, "stop only")
+	panic("stop failed")
+}
+
+func testLifecycle() *Lifecycle {
+	return new(Lifecycle).Inject(flamingo.NullLogger{}, &struct {
+		StartTimeout string `inject:"config:flamingo.lifecycle.startTimeout"`
+		StopTimeout  string `inject:"config:flamingo.lifecycle.stopTimeout"`
+	}{StartTimeout: "1s", StopTimeout: "1s"})
+}
+
+func TestLifecycle(t *testing.T) {
+	recorder := new(hookRecorder)
+	child := config.NewArea("child", []dingo.Module{
+		&otherHookModule{hookModule{name: "child", recorder: recorder}},
+		// already part of the root area
+		&hookModule{name: "duplicate", recorder: recorder},
+	})
+	config.NewArea("root", []dingo.Module{
+		&hookModule{name: "first", recorder: recorder},
+		&stopOnlyModule{recorder: recorder},
+		&hookModule{name: "second", recorder: recorder},
+	}, child)
+
+	l := testLifecycle()
+	assert.NoError(t, l.Start(context.Background(), child))
+	assert.NoError(t, l.Start(context.Background(), child), "already running")
+	assert.Equal(t, []string{"start first", "start child"}, recorder.calls, "modules of the same type are started once")
+
+	recorder.calls = nil
+	assert.Error(t, l.Stop(context.Background()))
+	assert.Equal(t, []string{"stop child", "stop only", "stop first"}, recorder.calls)
+}
+
+func TestLifecycle_StartFailure(t *testing.T) {
+	recorder := new(hookRecorder)
+	area := config.NewArea("root", []dingo.Module{
+		&hookModule{name: "first", recorder: recorder},
+		&otherHookModule{hookModule{name: "failing", recorder: recorder, startErr: errors.New("no connection")}},
+	})
+
+	err := testLifecycle().Start(context.Background(), area)
+	assert.EqualError(t, err, "start flamingo.me/flamingo/v3/framework/cmd.otherHookModule: no connection")
+	assert.Equal(t, []string{"start first", "start failing", "stop first"}, recorder.calls)
+}
+
+func TestLifecycle_Timeout(t *testing.T) {
+	recorder := new(hookRecorder)
+	area := config.NewArea("root", []dingo.Module{
+		&hookModule{name: "blocking", recorder: recorder, block: true, timeout: 10 * time.Millisecond},
+	})
+
+	err := testLifecycle().Start(context.Background(), area)
+	assert.EqualError(t, err, "start flamingo.me/flamingo/v3/framework/cmd.hookModule: timed out after 10ms")
+}
+
+func TestShutdown_LifecycleStopped(t *testing.T) {
+	l := testLifecycle()
+	signals := make(chan os.Signal, 1)
+	complete := make(chan struct{}, 1)
+
+	stopped := l.stoppedChan()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		shutdown(new(flamingo.DefaultEventRouter), l, stopped, time.Second, signals, make(chan struct{}), complete, flamingo.NullLogger{})
+	}()
+
+	assert.NoError(t, l.Stop(context.Background()))
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the shutdown handler is still running")
+	}
+	assert.Len(t, complete, 1, "a later post run of the command does not block")
+}
@@ -11,13 +11,12 @@ import (
 	"time"
 
 	"flamingo.me/dingo"
+	"flamingo.me/flamingo/v3/framework/config"
 	"flamingo.me/flamingo/v3/framework/flamingo"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
 
-var once = sync.Once{}
-
 type (
 	eventRouterProvider func() flamingo.EventRouter
 	flagSetProvider     func() []*pflag.FlagSet
@@ -28,30 +27,43 @@ type (
 
 // Configure DI
 func (m *Module) Configure(injector *dingo.Injector) {
+	injector.Bind(new(Lifecycle)).In(dingo.Singleton)
+
 	injector.Bind(new(cobra.Command)).AnnotatedWith("flamingo").ToProvider(
 		func(
 			commands []*cobra.Command,
 			eventRouterProvider eventRouterProvider,
+			lifecycle *Lifecycle,
 			logger flamingo.Logger,
 			flagSetProvider flagSetProvider,
 			config *struct {
-				Name string `inject:"config:flamingo.cmd.name"`
+				Name        string `inject:"config:flamingo.cmd.name"`
+				GracePeriod string `inject:"config:flamingo.lifecycle.gracePeriod"`
 			}) *cobra.Command {
 			signals := make(chan os.Signal, 1)
+			finished := make(chan struct{}, 1)
 			shutdownComplete := make(chan struct{}, 1)
 			signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
 
-			once.Do(func() {
-				go shutdown(eventRouterProvider(), signals, shutdownComplete, logger)
-			})
+			// the root command is a singleton, so every application has its own shutdown handler,
+			// which ends with the command or when the application is stopped otherwise
+			gracePeriod := parseDuration(logger, "flamingo.lifecycle.gracePeriod", config.GracePeriod)
+			go shutdown(eventRouterProvider(), lifecycle, lifecycle.stoppedChan(), gracePeriod, signals, finished, shutdownComplete, logger)
+
+			var finish sync.Once
 
 			rootCmd := &cobra.Command{
 				Use:              config.Name,
 				Short:            "Flamingo " + config.Name,
 				TraverseChildren: true,
 				PersistentPostRun: func(cmd *cobra.Command, args []string) {
-					signals <- syscall.SIGTERM
-					<-shutdownComplete
+					finish.Do(func() {
+						select {
+						case finished <- struct{}{}:
+						default:
+						}
+						<-shutdownComplete
+					})
 				},
 				Example: `Run with -h or -help to see global debug flags`,
 			}
@@ -68,9 +80,16 @@ func (m *Module) Configure(injector *dingo.Injector) {
 	).In(dingo.Singleton)
 }
 
-// CueConfig specifies the command name
+// CueConfig specifies the command name and the lifecycle timeouts
 func (*Module) CueConfig() string {
-	return fmt.Sprintf(`flamingo: cmd: name: string | *"%s"`, filepath.Base(os.Args[0]))
+	return fmt.Sprintf(`
+flamingo: cmd: name: string | *"%s"
+flamingo: lifecycle: {
+	startTimeout: string | *"30s"
+	stopTimeout: string | *"30s"
+	gracePeriod: string | *"30s"
+}
+`, filepath.Base(os.Args[0]))
 }
 
 // FlamingoLegacyConfigAlias maps legacy config to new
@@ -78,14 +97,48 @@ func (*Module) FlamingoLegacyConfigAlias() map[string]string {
 	return map[string]string{"cmd.name": "flamingo.cmd.name"}
 }
 
-func shutdown(eventRouter flamingo.EventRouter, signals <-chan os.Signal, complete chan<- struct{}, logger flamingo.Logger) {
-	<-signals
+// shutdown dispatches the ShutdownEvent and stops the modules when a signal is received or the command finished.
+// The process is only terminated if the shutdown was caused by a signal, a finished command returns normally.
+// If the shutdown takes longer than the grace period, the process is terminated.
+// If the lifecycle is stopped otherwise, e.g. after a failed start or by a test harness, the signals are released.
+func shutdown(
+	eventRouter flamingo.EventRouter,
+	lifecycle *Lifecycle,
+	stopped <-chan struct{},
+	gracePeriod time.Duration,
+	signals chan os.Signal,
+	finished <-chan struct{},
+	complete chan<- struct{},
+	logger flamingo.Logger,
+) {
+	byCommand := false
+	select {
+	case <-signals:
+	case <-finished:
+		byCommand = true
+	case <-stopped:
+		signal.Stop(signals)
+		complete <- struct{}{}
+		return
+	}
 	logger.Info("start graceful shutdown")
 
+	ctx := context.Background()
+	var timeout <-chan time.Time
+	if gracePeriod > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, gracePeriod)
+		defer cancel()
+		timeout = time.After(gracePeriod)
+	}
+
 	stopper := make(chan struct{})
 
 	go func() {
-		eventRouter.Dispatch(context.Background(), &flamingo.ShutdownEvent{})
+		eventRouter.Dispatch(ctx, &flamingo.ShutdownEvent{})
+		if err := lifecycle.Stop(ctx); err != nil {
+			logger.Error(err)
+		}
 		close(stopper)
 	}()
 
@@ -93,28 +146,59 @@ func shutdown(eventRouter flamingo.EventRouter, signals <-chan os.Signal, comple
 	case <-signals:
 		logger.Info("second interrupt signal received, hard shutdown")
 		os.Exit(130)
-	case <-time.After(30 * time.Second):
-		logger.Info("time limit reached, hard shutdown")
+	case <-timeout:
+		logger.Info("grace period of ", gracePeriod, " exceeded, hard shutdown")
 		os.Exit(130)
 	case <-stopper:
 		logger.Info("graceful shutdown complete")
 		complete <- struct{}{}
-		os.Exit(0)
+		if !byCommand {
+			os.Exit(0)
+		}
+		signal.Stop(signals)
 	}
 }
 
-// Run the root command
+// Run the root command, the modules of the injector's area are started first
 func Run(injector *dingo.Injector) error {
 	i, err := injector.GetAnnotatedInstance(new(cobra.Command), "flamingo")
 	if err != nil {
 		return err
 	}
 	cmd := i.(*cobra.Command)
+
+	i, err = injector.GetInstance(config.Area{})
+	if err != nil {
+		return err
+	}
+	area := i.(*config.Area)
+
+	i, err = injector.GetInstance(new(Lifecycle))
+	if err != nil {
+		return err
+	}
+	if err := i.(*Lifecycle).Start(context.Background(), area); err != nil {
+		return err
+	}
+
 	i, err = injector.GetInstance(new(eventRouterProvider))
 	if err != nil {
 		return err
 	}
 	i.(eventRouterProvider)().Dispatch(context.Background(), &flamingo.StartupEvent{})
 
-	return cmd.Execute()
+	return Execute(cmd)
+}
+
+// Execute the root command, the application is shut down if the command fails
+func Execute(cmd *cobra.Command) error {
+	if err := cmd.Execute(); err != nil {
+		// cobra does not run the post run of failed commands
+		if cmd.PersistentPostRun != nil {
+			cmd.PersistentPostRun(cmd, nil)
+		}
+		return err
+	}
+
+	return nil
 }
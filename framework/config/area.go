@@ -57,11 +57,13 @@ type (
 		FlamingoLegacyConfigAlias() map[string]string
 	}
 
-	// Route defines the yaml structure for a route, consisting of a path and a controller, as well as optional args
+	// Route defines the yaml structure for a route, consisting of a path and a controller, as well as optional args.
+	// Routes with a locale are locale specific variants of the same controller.
 	Route struct {
 		Path       string
 		Controller string
 		Name       string
+		Locale     string
 	}
 )
 
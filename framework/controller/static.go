@@ -19,7 +19,8 @@ func (fr fileResponse) Apply(ctx context.Context, rw http.ResponseWriter) error
 		return errors.New("can not serve from empty dir")
 	}
 
-	http.ServeFile(rw, fr.r.Request(), path.Join(fr.r.Params["dir"], fr.r.Params["name"]))
+	// the name is rooted before joining, so it can not escape the dir via ".."
+	http.ServeFile(rw, fr.r.Request(), path.Join(fr.r.Params["dir"], path.Clean("/"+fr.r.Params["name"])))
 	return nil
 }
 
@@ -0,0 +1,25 @@
+package flamingo
+
+import (
+	"context"
+	"time"
+)
+
+type (
+	// Starter is implemented by modules which need to start something before the application runs, e.g. connections or workers.
+	// Modules are started in the order of their dependencies, a failing Start aborts the startup.
+	Starter interface {
+		Start(ctx context.Context) error
+	}
+
+	// Stopper is implemented by modules which need to clean up on shutdown.
+	// Modules are stopped in reverse order, after the ShutdownEvent has been dispatched.
+	Stopper interface {
+		Stop(ctx context.Context) error
+	}
+
+	// HookTimeouter can be implemented by a Starter or Stopper to override the configured timeout of its hooks
+	HookTimeouter interface {
+		HookTimeout() time.Duration
+	}
+)
@@ -2,7 +2,10 @@ package flamingo
 
 import (
 	"context"
+	"fmt"
 	"log"
+
+	"flamingo.me/flamingo/v3/framework/redact"
 )
 
 // Common logger field keys
@@ -65,29 +68,34 @@ type (
 var _ Logger = new(NullLogger)
 var _ Logger = new(StdLogger)
 
-// StdLogger uses the go stdlib logger for logging
+// StdLogger uses the go stdlib logger for logging, messages and fields are redacted by the optional Redactor
 type StdLogger struct {
 	log.Logger
+	Redactor *redact.Redactor
+}
+
+func (l *StdLogger) print(args ...interface{}) {
+	l.Print(l.Redactor.String(fmt.Sprint(args...)))
 }
 
 // Debug logs output
 func (l *StdLogger) Debug(args ...interface{}) {
-	l.Print(args...)
+	l.print(args...)
 }
 
 // Debugf outputs the formatted debug string
 func (l *StdLogger) Debugf(f string, args ...interface{}) {
-	l.Printf(f, args...)
+	l.print(fmt.Sprintf(f, args...))
 }
 
 // Info log output
 func (l *StdLogger) Info(args ...interface{}) {
-	l.Print(args...)
+	l.print(args...)
 }
 
 // Warn log output
 func (l *StdLogger) Warn(args ...interface{}) {
-	l.Print(args...)
+	l.print(args...)
 }
 
 // WithContext currently does nothing
@@ -97,19 +105,33 @@ func (l *StdLogger) WithContext(ctx context.Context) Logger {
 
 // WithField currently logs the field
 func (l *StdLogger) WithField(key LogKey, value interface{}) Logger {
-	log.Println("WithField", key, value)
+	log.Println("WithField", key, l.Redactor.Value(string(key), value))
 	return l
 }
 
 // WithFields currently logs the fields
 func (l *StdLogger) WithFields(fields map[LogKey]interface{}) Logger {
-	log.Println("WithFields", fields)
+	redacted := make(map[LogKey]interface{}, len(fields))
+	for key, value := range fields {
+		redacted[key] = l.Redactor.Value(string(key), value)
+	}
+	log.Println("WithFields", redacted)
 	return l
 }
 
 // Error log
 func (l *StdLogger) Error(args ...interface{}) {
-	l.Print(args...)
+	l.print(args...)
+}
+
+// Fatal logs and exits
+func (l *StdLogger) Fatal(args ...interface{}) {
+	l.Logger.Fatal(l.Redactor.String(fmt.Sprint(args...)))
+}
+
+// Panic logs and panics
+func (l *StdLogger) Panic(args ...interface{}) {
+	l.Logger.Panic(l.Redactor.String(fmt.Sprint(args...)))
 }
 
 // Flush does nothing
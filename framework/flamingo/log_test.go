@@ -0,0 +1,29 @@
+package flamingo_test
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/redact"
+)
+
+func TestStdLogger_Redactor(t *testing.T) {
+	redactor, err := redact.New(redact.Options{Keys: []string{"(?i)password"}, Detectors: []string{"bearer"}})
+	require.NoError(t, err)
+
+	buf := new(bytes.Buffer)
+	logger := &flamingo.StdLogger{Logger: *log.New(buf, "", 0), Redactor: redactor}
+
+	logger.Info("login with password=secret")
+	logger.Debugf("header %s", "Authorization: Bearer abc.def")
+	assert.Equal(t, "login with password=[REDACTED]\nheader Authorization: Bearer [REDACTED]\n", buf.String())
+
+	buf.Reset()
+	(&flamingo.StdLogger{Logger: *log.New(buf, "", 0)}).Info("password=secret")
+	assert.Equal(t, "password=secret\n", buf.String(), "a nil Redactor does not redact")
+}
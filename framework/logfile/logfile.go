@@ -0,0 +1,194 @@
+// Package logfile opens log outputs: stdout, stderr, or files which are rotated by size.
+package logfile
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+type (
+	// Writer is a log output
+	Writer interface {
+		io.Writer
+		// Sync flushes the output
+		Sync() error
+		// Close the output, stdout and stderr are not closed
+		Close() error
+	}
+
+	// Options of a log file
+	Options struct {
+		// MaxSize in bytes after which the file is rotated, 0 disables the rotation
+		MaxSize int64
+		// MaxBackups is the number of rotated files which are kept, as <file>.1 (newest) to <file>.<MaxBackups>
+		MaxBackups int
+	}
+
+	// File is a log file which is rotated by size
+	File struct {
+		path    string
+		options Options
+
+		mu   sync.Mutex
+		file *os.File
+		size int64
+	}
+
+	std struct {
+		*os.File
+	}
+)
+
+var _ Writer = new(File)
+
+// Open returns the output for the target, which is "stdout", "stderr" or the path of a file.
+// Files are created with their directory and appended to.
+func Open(target string, options Options) (Writer, error) {
+	switch target {
+	case "stdout", "":
+		return std{os.Stdout}, nil
+	case "stderr":
+		return std{os.Stderr}, nil
+	}
+
+	f := &File{path: target, options: options}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// Write to the file, it is rotated before it exceeds the maximum size.
+// If the rotation fails, p is written to the current file and the rotation error is returned.
+func (f *File) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	var rotateErr error
+	if f.options.MaxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.options.MaxSize {
+		if err := f.rotate(); err != nil {
+			rotateErr = fmt.Errorf("logfile: rotating %s: %w", f.path, err)
+			if f.file == nil {
+				return 0, rotateErr
+			}
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	if err == nil {
+		err = rotateErr
+	}
+
+	return n, err
+}
+
+// Sync the file
+func (f *File) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	return f.file.Sync()
+}
+
+// Close the file
+func (f *File) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+
+	return err
+}
+
+// Path of the file
+func (f *File) Path() string {
+	return f.path
+}
+
+func (f *File) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+		return fmt.Errorf("logfile: %w", err)
+	}
+
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("logfile: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("logfile: %w", err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+
+	return nil
+}
+
+// rotate shifts the backups, moves the file to <file>.1 and opens a new file.
+// If a step fails, the file at the path is opened again, so the writes continue.
+func (f *File) rotate() error {
+	err := f.file.Close()
+	f.file = nil
+
+	if err == nil {
+		err = f.shift()
+	}
+	if openErr := f.open(); openErr != nil {
+		return openErr
+	}
+
+	return err
+}
+
+// shift the backups and move the file to <file>.1, or remove it without backups
+func (f *File) shift() error {
+	if f.options.MaxBackups > 0 {
+		_ = os.Remove(backup(f.path, f.options.MaxBackups))
+		for i := f.options.MaxBackups - 1; i > 0; i-- {
+			if err := os.Rename(backup(f.path, i), backup(f.path, i+1)); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		if err := os.Rename(f.path, backup(f.path, 1)); err != nil {
+			return err
+		}
+	} else if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func backup(path string, i int) string {
+	return fmt.Sprintf("%s.%d", path, i)
+}
+
+// Sync stdout or stderr, which is not supported by all terminals
+func (s std) Sync() error {
+	_ = s.File.Sync()
+	return nil
+}
+
+// Close does not close stdout or stderr
+func (std) Close() error {
+	return nil
+}
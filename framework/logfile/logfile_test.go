@@ -0,0 +1,96 @@
+package logfile_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"flamingo.me/flamingo/v3/framework/logfile"
+)
+
+func TestFile_Rotate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logfile")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "logs", "access.log")
+	w, err := logfile.Open(path, logfile.Options{MaxSize: 10, MaxBackups: 2})
+	require.NoError(t, err)
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err := w.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Sync())
+	require.NoError(t, w.Close())
+
+	for file, content := range map[string]string{
+		path:        "fourth\n",
+		path + ".1": "third\n",
+		path + ".2": "second\n",
+	} {
+		b, err := ioutil.ReadFile(file)
+		require.NoError(t, err)
+		assert.Equal(t, content, string(b), file)
+	}
+	_, err = os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err))
+
+	// the file is appended to
+	w, err = logfile.Open(path, logfile.Options{})
+	require.NoError(t, err)
+	_, err = w.Write([]byte("fifth\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	b, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "fourth\nfifth\n", string(b))
+}
+
+func TestFile_RotateFailed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logfile")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "access.log")
+	w, err := logfile.Open(path, logfile.Options{MaxSize: 10, MaxBackups: 1})
+	require.NoError(t, err)
+
+	// the backup can't be replaced by the file
+	require.NoError(t, os.MkdirAll(filepath.Join(path+".1", "blocked"), 0755))
+
+	_, err = w.Write([]byte("first\n"))
+	require.NoError(t, err)
+	n, err := w.Write([]byte("second\n"))
+	assert.Error(t, err)
+	assert.Equal(t, 7, n, "the line is written to the current file")
+
+	// the file is rotated once the backup can be replaced
+	require.NoError(t, os.RemoveAll(path+".1"))
+	_, err = w.Write([]byte("third\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	for file, content := range map[string]string{
+		path:        "third\n",
+		path + ".1": "first\nsecond\n",
+	} {
+		b, err := ioutil.ReadFile(file)
+		require.NoError(t, err)
+		assert.Equal(t, content, string(b), file)
+	}
+}
+
+func TestOpen_Std(t *testing.T) {
+	for _, target := range []string{"stdout", "stderr"} {
+		w, err := logfile.Open(target, logfile.Options{})
+		require.NoError(t, err)
+		assert.NoError(t, w.Sync())
+		assert.NoError(t, w.Close())
+	}
+}
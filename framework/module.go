@@ -47,15 +47,20 @@ func (*InitModule) Configure(injector *dingo.Injector) {
 	injector.Bind(new(flamingo.EventRouter)).To(flamingo.DefaultEventRouter{})
 
 	injector.Bind(web.Router{}).In(dingo.ChildSingleton)
+	injector.Bind(web.RequestMetrics{}).In(dingo.Singleton)
 	injector.Bind(new(web.ReverseRouter)).To(web.Router{})
+	injector.Bind(web.AreaRouter{}).In(dingo.ChildSingleton)
 	injector.Bind(web.RouterRegistry{}).In(dingo.Singleton).ToProvider(web.NewRegistry)
 	injector.BindMulti(new(web.Filter)).To(new(filter.MetricsFilter))
+	flamingo.BindEventSubscriber(injector).To(web.UploadCleanupSubscriber{})
 
 	flamingo.BindTemplateFunc(injector, "config", new(config.TemplateFunc))
 	flamingo.BindTemplateFunc(injector, "setPartialData", new(web.SetPartialDataFunc))
 	flamingo.BindTemplateFunc(injector, "getPartialData", new(web.GetPartialDataFunc))
 	flamingo.BindTemplateFunc(injector, "canonicalDomain", new(web.CanonicalDomainFunc))
 	flamingo.BindTemplateFunc(injector, "isExternalUrl", new(web.IsExternalURL))
+	flamingo.BindTemplateFunc(injector, "hreflang", new(web.HreflangFunc))
+	flamingo.BindTemplateFunc(injector, "areaUrl", new(web.AreaURLFunc))
 }
 
 // Inject controller for flamingo default handler
@@ -106,6 +111,23 @@ flamingo: {
 		errWithCode: string | *"error/withCode"
 		err503: string | *"error/503"
 	}
+	web: {
+		problem: {
+			negotiate: bool | *true
+			apiPaths: [...string]
+		}
+		metrics: {
+			buckets: [...number] | *[5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000]
+			maxHandlers: int | *500
+		}
+		upload: {
+			maxBodySize: int | *33554432
+			maxFileSize: int | *10485760
+			maxValueSize: int | *1048576
+			allowedTypes: [...string]
+			tempDir: string | *""
+		}
+	}
 	session: {
 		name: string | *"flamingo"
 		saveMode: *"Always" | "OnRead" | "OnWrite" 
@@ -0,0 +1,127 @@
+package opencensus
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+type (
+	baggageKey struct{}
+
+	baggageHandler struct {
+		next http.Handler
+	}
+
+	baggageTransport struct {
+		next http.RoundTripper
+	}
+)
+
+const (
+	baggageHeader = "baggage"
+	// limits of the W3C baggage specification
+	maxBaggageMembers = 180
+	maxBaggageBytes   = 8192
+)
+
+// Baggage returns the W3C baggage of the context, which is propagated to outgoing requests
+func Baggage(ctx context.Context) map[string]string {
+	baggage, _ := ctx.Value(baggageKey{}).(map[string]string)
+	result := make(map[string]string, len(baggage))
+	for k, v := range baggage {
+		result[k] = v
+	}
+
+	return result
+}
+
+// ContextWithBaggage adds members to the baggage of the context
+func ContextWithBaggage(ctx context.Context, members map[string]string) context.Context {
+	baggage, _ := ctx.Value(baggageKey{}).(map[string]string)
+	merged := make(map[string]string, len(baggage)+len(members))
+	for k, v := range baggage {
+		merged[k] = v
+	}
+	for k, v := range members {
+		merged[k] = v
+	}
+
+	return context.WithValue(ctx, baggageKey{}, merged)
+}
+
+// parseBaggage parses "key1=value1;property,key2=value2", properties are dropped
+func parseBaggage(header string) map[string]string {
+	if header == "" || len(header) > maxBaggageBytes {
+		return nil
+	}
+
+	baggage := make(map[string]string)
+	for _, member := range strings.Split(header, ",") {
+		if len(baggage) == maxBaggageMembers {
+			break
+		}
+
+		if i := strings.Index(member, ";"); i >= 0 {
+			member = member[:i]
+		}
+		i := strings.Index(member, "=")
+		if i <= 0 {
+			continue
+		}
+
+		key := strings.TrimSpace(member[:i])
+		value, err := url.PathUnescape(strings.TrimSpace(member[i+1:]))
+		if key == "" || err != nil {
+			continue
+		}
+		baggage[key] = value
+	}
+
+	return baggage
+}
+
+// formatBaggage formats the baggage sorted by key, members exceeding the limits are dropped
+func formatBaggage(baggage map[string]string) string {
+	keys := make([]string, 0, len(baggage))
+	for k := range baggage {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for i, k := range keys {
+		member := k + "=" + url.PathEscape(baggage[k])
+		if i == maxBaggageMembers || b.Len()+len(member)+1 > maxBaggageBytes {
+			break
+		}
+		if b.Len() > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(member)
+	}
+
+	return b.String()
+}
+
+// ServeHTTP adds the baggage of the request to the context
+func (h baggageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if baggage := parseBaggage(strings.Join(r.Header[http.CanonicalHeaderKey(baggageHeader)], ",")); len(baggage) > 0 {
+		r = r.WithContext(ContextWithBaggage(r.Context(), baggage))
+	}
+
+	h.next.ServeHTTP(w, r)
+}
+
+// RoundTrip adds the baggage of the context to the request, unless the request has a baggage header
+func (t baggageTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if baggage, ok := req.Context().Value(baggageKey{}).(map[string]string); ok && len(baggage) > 0 && req.Header.Get(baggageHeader) == "" {
+		// a RoundTripper must not modify the request
+		req = req.Clone(req.Context())
+		req.Header.Set(baggageHeader, formatBaggage(baggage))
+	}
+
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,215 @@
+package opencensus
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+type (
+	// Collector is an in-memory OTLP/HTTP collector for the JSON and the protobuf encoding, it is meant to test the
+	// tracing of an application, e.g. with httptest.NewServer(collector) as endpoint of the OTLPExporter or the
+	// OpenTelemetry SDK
+	Collector struct {
+		mu    sync.Mutex
+		spans []CollectedSpan
+	}
+
+	// CollectedSpan is a span received by the Collector
+	CollectedSpan struct {
+		ServiceName  string
+		TraceID      string
+		SpanID       string
+		ParentSpanID string
+		TraceState   string
+		Name         string
+		// Kind is the OTLP span kind: 1 internal, 2 server, 3 client
+		Kind       int
+		Start      time.Time
+		End        time.Time
+		Attributes map[string]interface{}
+		Events     []string
+		// Error is set for spans with an error status
+		Error   bool
+		Message string
+	}
+)
+
+var _ http.Handler = new(Collector)
+
+// NewCollector creates an empty collector
+func NewCollector() *Collector {
+	return new(Collector)
+}
+
+// ServeHTTP receives the spans of an export request, in the JSON or the protobuf encoding
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if r.Header.Get("Content-Type") == "application/x-protobuf" {
+		c.serveProtobuf(w, r)
+		return
+	}
+
+	var request otlpRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var spans []CollectedSpan
+	for _, resourceSpans := range request.ResourceSpans {
+		serviceName, _ := otlpAttributesMap(resourceSpans.Resource.Attributes)["service.name"].(string)
+		for _, scopeSpans := range resourceSpans.ScopeSpans {
+			for _, span := range scopeSpans.Spans {
+				collected := CollectedSpan{
+					ServiceName:  serviceName,
+					TraceID:      span.TraceID,
+					SpanID:       span.SpanID,
+					ParentSpanID: span.ParentSpanID,
+					TraceState:   span.TraceState,
+					Name:         span.Name,
+					Kind:         span.Kind,
+					Start:        fromUnixNano(span.StartTimeUnixNano),
+					End:          fromUnixNano(span.EndTimeUnixNano),
+					Attributes:   otlpAttributesMap(span.Attributes),
+					Error:        span.Status.Code == otlpStatusError,
+					Message:      span.Status.Message,
+				}
+				for _, event := range span.Events {
+					collected.Events = append(collected.Events, event.Name)
+				}
+				spans = append(spans, collected)
+			}
+		}
+	}
+
+	c.collect(spans)
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte("{}"))
+}
+
+// serveProtobuf receives the spans of the protobuf encoding, which is used by the OpenTelemetry SDK
+func (c *Collector) serveProtobuf(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var request coltracepb.ExportTraceServiceRequest
+	if err := proto.Unmarshal(body, &request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var spans []CollectedSpan
+	for _, resourceSpans := range request.ResourceSpans {
+		serviceName, _ := protoAttributesMap(resourceSpans.GetResource().GetAttributes())["service.name"].(string)
+		for _, scopeSpans := range resourceSpans.ScopeSpans {
+			for _, span := range scopeSpans.Spans {
+				collected := CollectedSpan{
+					ServiceName: serviceName,
+					TraceID:     hex.EncodeToString(span.TraceId),
+					SpanID:      hex.EncodeToString(span.SpanId),
+					TraceState:  span.TraceState,
+					Name:        span.Name,
+					Kind:        int(span.Kind),
+					Start:       time.Unix(0, int64(span.StartTimeUnixNano)),
+					End:         time.Unix(0, int64(span.EndTimeUnixNano)),
+					Attributes:  protoAttributesMap(span.Attributes),
+					Error:       span.GetStatus().GetCode() == tracepb.Status_STATUS_CODE_ERROR,
+					Message:     span.GetStatus().GetMessage(),
+				}
+				if len(span.ParentSpanId) > 0 {
+					collected.ParentSpanID = hex.EncodeToString(span.ParentSpanId)
+				}
+				for _, event := range span.Events {
+					collected.Events = append(collected.Events, event.Name)
+				}
+				spans = append(spans, collected)
+			}
+		}
+	}
+
+	c.collect(spans)
+
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (c *Collector) collect(spans []CollectedSpan) {
+	c.mu.Lock()
+	c.spans = append(c.spans, spans...)
+	c.mu.Unlock()
+}
+
+// Spans returns all received spans in the order of their arrival
+func (c *Collector) Spans() []CollectedSpan {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]CollectedSpan(nil), c.spans...)
+}
+
+// Reset removes the received spans
+func (c *Collector) Reset() {
+	c.mu.Lock()
+	c.spans = nil
+	c.mu.Unlock()
+}
+
+func fromUnixNano(s string) time.Time {
+	nanos, _ := strconv.ParseInt(s, 10, 64)
+	return time.Unix(0, nanos)
+}
+
+func protoAttributesMap(attributes []*commonpb.KeyValue) map[string]interface{} {
+	result := make(map[string]interface{}, len(attributes))
+	for _, attribute := range attributes {
+		switch v := attribute.GetValue().GetValue().(type) {
+		case *commonpb.AnyValue_StringValue:
+			result[attribute.Key] = v.StringValue
+		case *commonpb.AnyValue_BoolValue:
+			result[attribute.Key] = v.BoolValue
+		case *commonpb.AnyValue_IntValue:
+			result[attribute.Key] = v.IntValue
+		case *commonpb.AnyValue_DoubleValue:
+			result[attribute.Key] = v.DoubleValue
+		}
+	}
+
+	return result
+}
+
+func otlpAttributesMap(attributes []otlpAttribute) map[string]interface{} {
+	result := make(map[string]interface{}, len(attributes))
+	for _, attribute := range attributes {
+		switch v := attribute.Value; {
+		case v.StringValue != nil:
+			result[attribute.Key] = *v.StringValue
+		case v.BoolValue != nil:
+			result[attribute.Key] = *v.BoolValue
+		case v.IntValue != nil:
+			i, _ := strconv.ParseInt(*v.IntValue, 10, 64)
+			result[attribute.Key] = i
+		case v.DoubleValue != nil:
+			result[attribute.Key] = *v.DoubleValue
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,366 @@
+package opencensus
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"contrib.go.opencensus.io/exporter/jaeger"
+	"contrib.go.opencensus.io/exporter/prometheus"
+	"contrib.go.opencensus.io/exporter/zipkin"
+	"flamingo.me/dingo"
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	openzipkin "github.com/openzipkin/zipkin-go"
+	"github.com/openzipkin/zipkin-go/reporter"
+	reporterHttp "github.com/openzipkin/zipkin-go/reporter/http"
+	"go.opencensus.io/plugin/runmetrics"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/trace"
+)
+
+type (
+	// exporters registers the opencensus exporters on startup and removes them on shutdown,
+	// so the process wide opencensus registries are only touched while an application is running
+	exporters struct {
+		mu             sync.Mutex
+		logger         flamingo.Logger
+		config         exportersConfig
+		prometheus     *prometheus.Exporter
+		started        bool
+		jaeger         *jaeger.Exporter
+		zipkin         *zipkin.Exporter
+		zipkinReporter reporter.Reporter
+		otlp           *OTLPExporter
+		tail           *TailSampler
+	}
+
+	// registrations counts the process wide opencensus registrations of the running applications,
+	// a registration is only undone when the last application which made it is stopped
+	registrations struct {
+		mu            sync.Mutex
+		applications  int
+		spanExporters map[trace.Exporter]int
+		viewExporters map[view.Exporter]int
+	}
+
+	exportersConfig struct {
+		JaegerEnable          bool                        `inject:"config:flamingo.opencensus.jaeger.enable"`
+		JaegerEndpoint        string                      `inject:"config:flamingo.opencensus.jaeger.endpoint"`
+		ZipkinEnable          bool                        `inject:"config:flamingo.opencensus.zipkin.enable"`
+		ZipkinEndpoint        string                      `inject:"config:flamingo.opencensus.zipkin.endpoint"`
+		ServiceName           string                      `inject:"config:flamingo.opencensus.serviceName"`
+		OTLPEnable            bool                        `inject:"config:flamingo.opencensus.otlp.enable,optional"`
+		OTLPEndpoint          string                      `inject:"config:flamingo.opencensus.otlp.endpoint,optional"`
+		OTLPHeaders           config.Map                  `inject:"config:flamingo.opencensus.otlp.headers,optional"`
+		OTLPBatchSize         float64                     `inject:"config:flamingo.opencensus.otlp.batchSize,optional"`
+		OTLPMaxQueueSize      float64                     `inject:"config:flamingo.opencensus.otlp.maxQueueSize,optional"`
+		OTLPInterval          string                      `inject:"config:flamingo.opencensus.otlp.interval,optional"`
+		OTLPTimeout           string                      `inject:"config:flamingo.opencensus.otlp.timeout,optional"`
+		Sampler               *ConfiguredURLPrefixSampler `inject:""`
+		BackgroundProbability float64                     `inject:"config:flamingo.opencensus.tracing.sampler.backgroundProbability,optional"`
+		SpanExporters         []trace.Exporter            `inject:",optional"`
+	}
+)
+
+// defaultSampler is the default sampler of opencensus, which is restored when the last application is stopped
+var defaultSampler = trace.ProbabilitySampler(1e-4)
+
+var globalRegistrations = &registrations{
+	spanExporters: make(map[trace.Exporter]int),
+	viewExporters: make(map[view.Exporter]int),
+}
+
+// BindSpanExporter registers an additional exporter for the OpenCensus spans.
+// The exporter is registered on startup and removed on shutdown, a Flush() method is called on shutdown.
+func BindSpanExporter(injector *dingo.Injector) *dingo.Binding {
+	return injector.BindMulti(new(trace.Exporter))
+}
+
+// Inject dependencies, the prometheus exporter uses its own registry
+func (e *exporters) Inject(logger flamingo.Logger, config *exportersConfig) *exporters {
+	e.logger = logger.WithField(flamingo.LogKeyModule, "opencensus").WithField(flamingo.LogKeyCategory, "exporters")
+	if config != nil {
+		e.config = *config
+	}
+
+	exporter, err := prometheus.NewExporter(prometheus.Options{})
+	if err != nil {
+		e.logger.Error("prometheus exporter: ", err)
+	}
+	e.prometheus = exporter
+
+	return e
+}
+
+// Notify starts the exporters on startup and stops them on shutdown
+func (e *exporters) Notify(_ context.Context, event flamingo.Event) {
+	switch event.(type) {
+	case *flamingo.StartupEvent:
+		e.start()
+	case *flamingo.ShutdownEvent:
+		e.stop()
+	}
+}
+
+// metricsHandler serves the prometheus metrics
+func (e *exporters) metricsHandler() http.Handler {
+	if e.prometheus == nil {
+		return http.NotFoundHandler()
+	}
+	return e.prometheus
+}
+
+func (e *exporters) start() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.started {
+		return
+	}
+	e.started = true
+
+	// spans without a sampled parent, e.g. of background jobs
+	globalRegistrations.acquire(e.logger, trace.ProbabilitySampler(e.config.BackgroundProbability))
+
+	// the tail sampler exports the deferred traces, so the exporters are registered at the tail sampler
+	if e.config.Sampler != nil {
+		e.tail = e.config.Sampler.TailSampler()
+	}
+	if e.tail != nil {
+		globalRegistrations.registerSpanExporter(e.tail)
+	}
+
+	if e.config.JaegerEnable {
+		exporter, err := jaeger.NewExporter(jaeger.Options{
+			CollectorEndpoint: e.config.JaegerEndpoint,
+			Process: jaeger.Process{
+				ServiceName: e.config.ServiceName,
+				Tags: []jaeger.Tag{
+					jaeger.StringTag("ip", localAddr()),
+				},
+			},
+		})
+		if err != nil {
+			e.logger.Error("jaeger exporter: ", err)
+		} else {
+			e.jaeger = exporter
+			e.registerSpanExporter(exporter)
+		}
+	}
+
+	if e.config.ZipkinEnable {
+		localEndpoint, err := openzipkin.NewEndpoint(e.config.ServiceName, localAddr())
+		if err != nil {
+			e.logger.Error("zipkin exporter: ", err)
+		} else {
+			// The Zipkin reporter takes collected spans from the app and reports them to the backend
+			// http://localhost:9411/api/v2/spans is the default for the Zipkin Span v2
+			e.zipkinReporter = reporterHttp.NewReporter(e.config.ZipkinEndpoint)
+			// The OpenCensus exporter wraps the Zipkin reporter
+			e.zipkin = zipkin.NewExporter(e.zipkinReporter, localEndpoint)
+			e.registerSpanExporter(e.zipkin)
+		}
+	}
+
+	if e.config.OTLPEnable {
+		var headers map[string]string
+		if err := e.config.OTLPHeaders.MapInto(&headers); err != nil {
+			e.logger.Error("otlp exporter: ", err)
+		}
+		e.otlp = NewOTLPExporter(OTLPOptions{
+			Endpoint:     e.config.OTLPEndpoint,
+			Headers:      headers,
+			ServiceName:  e.config.ServiceName,
+			BatchSize:    int(e.config.OTLPBatchSize),
+			MaxQueueSize: int(e.config.OTLPMaxQueueSize),
+			Interval:     e.duration("interval", e.config.OTLPInterval),
+			Timeout:      e.duration("timeout", e.config.OTLPTimeout),
+			OnError: func(err error) {
+				e.logger.Warn("otlp exporter: ", err)
+			},
+		})
+		e.registerSpanExporter(e.otlp)
+	}
+
+	for _, exporter := range e.config.SpanExporters {
+		e.registerSpanExporter(exporter)
+	}
+
+	if e.prometheus != nil {
+		globalRegistrations.registerViewExporter(e.prometheus)
+	}
+}
+
+func (e *exporters) stop() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.started {
+		return
+	}
+	e.started = false
+
+	if e.prometheus != nil {
+		globalRegistrations.unregisterViewExporter(e.prometheus)
+	}
+
+	if e.jaeger != nil {
+		e.unregisterSpanExporter(e.jaeger)
+		e.jaeger.Flush()
+		e.jaeger = nil
+	}
+
+	if e.zipkin != nil {
+		e.unregisterSpanExporter(e.zipkin)
+		if err := e.zipkinReporter.Close(); err != nil {
+			e.logger.Error("zipkin reporter: ", err)
+		}
+		e.zipkin, e.zipkinReporter = nil, nil
+	}
+
+	if e.otlp != nil {
+		e.unregisterSpanExporter(e.otlp)
+		e.otlp.Stop()
+		e.otlp = nil
+	}
+
+	for _, exporter := range e.config.SpanExporters {
+		e.unregisterSpanExporter(exporter)
+		if flusher, ok := exporter.(interface{ Flush() }); ok {
+			flusher.Flush()
+		}
+	}
+
+	if e.tail != nil {
+		globalRegistrations.unregisterSpanExporter(e.tail)
+		e.tail = nil
+	}
+
+	globalRegistrations.release()
+}
+
+func (e *exporters) registerSpanExporter(exporter trace.Exporter) {
+	if e.tail != nil {
+		e.tail.RegisterExporter(exporter)
+		return
+	}
+	globalRegistrations.registerSpanExporter(exporter)
+}
+
+func (e *exporters) unregisterSpanExporter(exporter trace.Exporter) {
+	if e.tail != nil {
+		e.tail.UnregisterExporter(exporter)
+		return
+	}
+	globalRegistrations.unregisterSpanExporter(exporter)
+}
+
+// duration parses a configured duration, invalid values fall back to the default of the exporter
+func (e *exporters) duration(name, value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		e.logger.Error("otlp exporter: invalid ", name, ": ", err)
+	}
+
+	return d
+}
+
+// acquire the process wide configuration for an application, the first application enables the runtime metrics
+// and configures the sampler of spans without a sampled parent
+func (r *registrations) acquire(logger flamingo.Logger, sampler trace.Sampler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.applications++
+	if r.applications > 1 {
+		return
+	}
+
+	trace.ApplyConfig(trace.Config{DefaultSampler: sampler})
+
+	if err := runmetrics.Enable(runmetrics.RunMetricOptions{
+		EnableCPU:    true,
+		EnableMemory: true,
+	}); err != nil {
+		logger.Error("runmetrics: ", err)
+	}
+}
+
+// release the process wide configuration, the last application disables the runtime metrics
+// and restores the default sampler
+func (r *registrations) release() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.applications == 0 {
+		return
+	}
+
+	r.applications--
+	if r.applications > 0 {
+		return
+	}
+
+	runmetrics.Disable()
+	trace.ApplyConfig(trace.Config{DefaultSampler: defaultSampler})
+}
+
+// registerSpanExporter registers the exporter, an exporter which is shared by applications is registered once
+func (r *registrations) registerSpanExporter(exporter trace.Exporter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.spanExporters[exporter]++
+	if r.spanExporters[exporter] == 1 {
+		trace.RegisterExporter(exporter)
+	}
+}
+
+// unregisterSpanExporter removes the exporter when the last application which registered it is stopped
+func (r *registrations) unregisterSpanExporter(exporter trace.Exporter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.spanExporters[exporter] == 0 {
+		return
+	}
+
+	r.spanExporters[exporter]--
+	if r.spanExporters[exporter] == 0 {
+		delete(r.spanExporters, exporter)
+		trace.UnregisterExporter(exporter)
+	}
+}
+
+// registerViewExporter registers the exporter, an exporter which is shared by applications is registered once
+func (r *registrations) registerViewExporter(exporter view.Exporter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.viewExporters[exporter]++
+	if r.viewExporters[exporter] == 1 {
+		view.RegisterExporter(exporter)
+	}
+}
+
+// unregisterViewExporter removes the exporter when the last application which registered it is stopped
+func (r *registrations) unregisterViewExporter(exporter view.Exporter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.viewExporters[exporter] == 0 {
+		return
+	}
+
+	r.viewExporters[exporter]--
+	if r.viewExporters[exporter] == 0 {
+		delete(r.viewExporters, exporter)
+		view.UnregisterExporter(exporter)
+	}
+}
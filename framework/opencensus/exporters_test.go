@@ -0,0 +1,40 @@
+package opencensus
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opencensus.io/trace"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+)
+
+func TestExporters_Lifecycle(t *testing.T) {
+	original := http.DefaultTransport
+	shared := &recordingExporter{spans: make(chan *trace.SpanData, 10)}
+
+	first := new(exporters).Inject(flamingo.NullLogger{}, &exportersConfig{SpanExporters: []trace.Exporter{shared}})
+	second := new(exporters).Inject(flamingo.NullLogger{}, &exportersConfig{SpanExporters: []trace.Exporter{shared}})
+
+	first.Notify(context.Background(), new(flamingo.StartupEvent))
+	first.Notify(context.Background(), new(flamingo.StartupEvent))
+	second.Notify(context.Background(), new(flamingo.StartupEvent))
+	assert.Equal(t, 2, globalRegistrations.applications)
+	assert.Equal(t, original, http.DefaultTransport, "the default transport is not replaced")
+
+	first.Notify(context.Background(), new(flamingo.ShutdownEvent))
+	first.Notify(context.Background(), new(flamingo.ShutdownEvent))
+	_, span := trace.StartSpan(context.Background(), "running", trace.WithSampler(trace.AlwaysSample()))
+	span.End()
+	assert.Len(t, shared.spans, 1, "the exporter is kept while an application is running")
+
+	second.Notify(context.Background(), new(flamingo.ShutdownEvent))
+	_, span = trace.StartSpan(context.Background(), "stopped", trace.WithSampler(trace.AlwaysSample()))
+	span.End()
+	assert.Len(t, shared.spans, 1, "the exporter is removed with the last application")
+	assert.Equal(t, 0, globalRegistrations.applications)
+	assert.Empty(t, globalRegistrations.spanExporters)
+	assert.Empty(t, globalRegistrations.viewExporters)
+}
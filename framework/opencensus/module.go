@@ -6,18 +6,11 @@ import (
 	"math/rand"
 	"net"
 	"net/http"
-	"sync"
 
-	"contrib.go.opencensus.io/exporter/jaeger"
-	"contrib.go.opencensus.io/exporter/prometheus"
-	"contrib.go.opencensus.io/exporter/zipkin"
 	"flamingo.me/dingo"
+	"flamingo.me/flamingo/v3/framework/flamingo"
 	"flamingo.me/flamingo/v3/framework/systemendpoint"
 	"flamingo.me/flamingo/v3/framework/systemendpoint/domain"
-	openzipkin "github.com/openzipkin/zipkin-go"
-	reporterHttp "github.com/openzipkin/zipkin-go/reporter/http"
-	"go.opencensus.io/plugin/ochttp"
-	"go.opencensus.io/plugin/runmetrics"
 	"go.opencensus.io/stats"
 	"go.opencensus.io/stats/view"
 	"go.opencensus.io/tag"
@@ -25,7 +18,6 @@ import (
 )
 
 var (
-	registerOnce = new(sync.Once)
 	// KeyArea is the key to represent the current flamingo area
 	KeyArea, _ = tag.NewKey("area")
 )
@@ -53,15 +45,9 @@ func (rt *correlationIDInjector) RoundTrip(req *http.Request) (*http.Response, e
 	return rt.next.RoundTrip(req)
 }
 
-// Module registers the opencensus module which in turn enables jaeger & co
-type Module struct {
-	Endpoint       string `inject:"config:flamingo.opencensus.jaeger.endpoint"`
-	ServiceName    string `inject:"config:flamingo.opencensus.serviceName"`
-	ServiceAddr    string `inject:"config:flamingo.opencensus.serviceAddr"`
-	JaegerEnable   bool   `inject:"config:flamingo.opencensus.jaeger.enable"`
-	ZipkinEnable   bool   `inject:"config:flamingo.opencensus.zipkin.enable"`
-	ZipkinEndpoint string `inject:"config:flamingo.opencensus.zipkin.endpoint"`
-}
+// Module registers the opencensus module which in turn enables jaeger & co.
+// The exporters are registered on startup and removed on shutdown of the application.
+type Module struct{}
 
 // find first not-loopback ipv4 address
 func localAddr() string {
@@ -90,59 +76,15 @@ func localAddr() string {
 
 // Configure the opencensus Module
 func (m *Module) Configure(injector *dingo.Injector) {
-	registerOnce.Do(func() {
-		// For demoing purposes, always sample.
-		trace.ApplyConfig(trace.Config{DefaultSampler: trace.NeverSample()})
-		http.DefaultTransport = &correlationIDInjector{next: &ochttp.Transport{Base: http.DefaultTransport}}
-
-		if m.JaegerEnable {
-			// Register the Jaeger exporter to be able to retrieve
-			// the collected spans.
-			exporter, err := jaeger.NewExporter(jaeger.Options{
-				CollectorEndpoint: m.Endpoint,
-				Process: jaeger.Process{
-					ServiceName: m.ServiceName,
-					Tags: []jaeger.Tag{
-						jaeger.StringTag("ip", localAddr()),
-					},
-				},
-			})
-			if err != nil {
-				log.Fatal(err)
-			}
-			trace.RegisterExporter(exporter)
-		}
-
-		if m.ZipkinEnable {
-			localEndpoint, err := openzipkin.NewEndpoint(m.ServiceName, localAddr())
-			if err != nil {
-				log.Fatal(err)
-			}
-
-			// The Zipkin reporter takes collected spans from the app and reports them to the backend
-			// http://localhost:9411/api/v2/spans is the default for the Zipkin Span v2
-			reporter := reporterHttp.NewReporter(m.ZipkinEndpoint)
-			// defer reporter.Close()
-
-			// The OpenCensus exporter wraps the Zipkin reporter
-			exporter := zipkin.NewExporter(reporter, localEndpoint)
-			trace.RegisterExporter(exporter)
-		}
+	injector.Bind(new(exporters)).In(dingo.Singleton)
+	// the servers and the exporters share the rate limit and the tail sampler
+	injector.Bind(new(ConfiguredURLPrefixSampler)).In(dingo.Singleton)
+	flamingo.BindEventSubscriber(injector).ToProvider(func(e *exporters) *exporters {
+		return e
+	})
+	injector.BindMap((*domain.Handler)(nil), "/metrics").ToProvider(func(e *exporters) domain.Handler {
+		return e.metricsHandler()
 	})
-
-	if err := runmetrics.Enable(runmetrics.RunMetricOptions{
-		EnableCPU:    true,
-		EnableMemory: true,
-	}); err != nil {
-		log.Fatal(err)
-	}
-
-	exporter, err := prometheus.NewExporter(prometheus.Options{})
-	if err != nil {
-		log.Fatal(err)
-	}
-	view.RegisterExporter(exporter)
-	injector.BindMap((*domain.Handler)(nil), "/metrics").ToInstance(exporter)
 }
 
 // CueConfig defines the opencensus config scheme
@@ -157,12 +99,42 @@ flamingo: opencensus: {
 		enable: bool | *false
 		endpoint: string | *"http://localhost:9411/api/v2/spans"
 	}
+	otlp: {
+		enable: bool | *false
+		endpoint: string | *"http://localhost:4318/v1/traces"
+		headers: {[string]: string}
+		batchSize: number | *512
+		maxQueueSize: number | *2048
+		interval: string | *"5s"
+		timeout: string | *"10s"
+	}
 	serviceName: string | *"flamingo"
 	serviceAddr: string | *":13210"
-	tracing: sampler: {
-		whitelist: [...string]
-		blacklist: [...string]
-		allowParentTrace: bool | *true
+	tracing: {
+		propagation: *"b3" | "w3c"
+		baggage: bool | *true
+		publicEndpoint: bool | *true
+		sampler: {
+			whitelist: [...string]
+			blacklist: [...string]
+			allowParentTrace: bool | *true
+			probability: number | *1
+			rules: [...{
+				prefix?: string
+				handler?: string
+				probability: number
+			}]
+			rateLimit: number | *0
+			debugHeader: string | *""
+			backgroundProbability: number | *0
+			tail: {
+				errors: bool | *false
+				slowerThan: string | *""
+				maxTraces: number | *1000
+				maxSpans: number | *1000
+				timeout: string | *"1m"
+			}
+		}
 	}
 }
 `
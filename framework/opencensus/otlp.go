@@ -0,0 +1,399 @@
+package opencensus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/trace"
+)
+
+type (
+	// OTLPExporter exports the OpenCensus spans to an OpenTelemetry collector with OTLP/HTTP and JSON encoding
+	OTLPExporter struct {
+		options OTLPOptions
+		dropped int64
+
+		mu    sync.Mutex
+		spans []otlpSpan
+		// sendMu keeps the batches in order
+		sendMu  sync.Mutex
+		full    chan struct{}
+		stop    chan struct{}
+		stopped sync.WaitGroup
+	}
+
+	// OTLPOptions configure the OTLPExporter
+	OTLPOptions struct {
+		// Endpoint of the collector, e.g. http://localhost:4318/v1/traces
+		Endpoint string
+		// Headers are added to every request, e.g. for authentication
+		Headers map[string]string
+		// ServiceName is the service.name attribute of the resource
+		ServiceName string
+		// BatchSize is the number of spans which triggers an export, defaults to 512
+		BatchSize int
+		// MaxQueueSize is the maximum number of buffered spans, further spans are dropped until the next export,
+		// defaults to 2048
+		MaxQueueSize int
+		// Interval in which the spans are exported, defaults to 5s
+		Interval time.Duration
+		// Timeout of an export request, defaults to 10s
+		Timeout time.Duration
+		// OnError is called when an export fails
+		OnError func(error)
+	}
+
+	// the OTLP JSON encoding, see https://github.com/open-telemetry/opentelemetry-proto
+	otlpRequest struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+
+	otlpResourceSpans struct {
+		Resource   otlpResource     `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+
+	otlpResource struct {
+		Attributes []otlpAttribute `json:"attributes,omitempty"`
+	}
+
+	otlpScopeSpans struct {
+		Scope otlpScope  `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+
+	otlpSpan struct {
+		TraceID           string          `json:"traceId"`
+		SpanID            string          `json:"spanId"`
+		ParentSpanID      string          `json:"parentSpanId,omitempty"`
+		TraceState        string          `json:"traceState,omitempty"`
+		Name              string          `json:"name"`
+		Kind              int             `json:"kind"`
+		StartTimeUnixNano string          `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+		Attributes        []otlpAttribute `json:"attributes,omitempty"`
+		Events            []otlpEvent     `json:"events,omitempty"`
+		Links             []otlpLink      `json:"links,omitempty"`
+		Status            otlpStatus      `json:"status"`
+	}
+
+	otlpAttribute struct {
+		Key   string    `json:"key"`
+		Value otlpValue `json:"value"`
+	}
+
+	otlpValue struct {
+		StringValue *string  `json:"stringValue,omitempty"`
+		BoolValue   *bool    `json:"boolValue,omitempty"`
+		IntValue    *string  `json:"intValue,omitempty"`
+		DoubleValue *float64 `json:"doubleValue,omitempty"`
+	}
+
+	otlpEvent struct {
+		TimeUnixNano string          `json:"timeUnixNano"`
+		Name         string          `json:"name"`
+		Attributes   []otlpAttribute `json:"attributes,omitempty"`
+	}
+
+	otlpLink struct {
+		TraceID    string          `json:"traceId"`
+		SpanID     string          `json:"spanId"`
+		Attributes []otlpAttribute `json:"attributes,omitempty"`
+	}
+
+	otlpStatus struct {
+		Code    int    `json:"code,omitempty"`
+		Message string `json:"message,omitempty"`
+	}
+)
+
+// OTLP span kinds and status codes
+const (
+	otlpSpanKindInternal = 1
+	otlpSpanKindServer   = 2
+	otlpSpanKindClient   = 3
+	otlpStatusError      = 2
+
+	otlpScopeName = "flamingo.me/flamingo/v3/framework/opencensus"
+)
+
+var (
+	_ trace.Exporter = new(OTLPExporter)
+
+	// otlpDroppedMeasure counts the spans which are dropped because the queue is full
+	otlpDroppedMeasure = stats.Int64("flamingo/opencensus/otlp/dropped", "Count of spans dropped by the OTLP exporter", stats.UnitDimensionless)
+)
+
+func init() {
+	if err := View("flamingo/opencensus/otlp/dropped", otlpDroppedMeasure, view.Sum()); err != nil {
+		panic(err)
+	}
+}
+
+// NewOTLPExporter creates an exporter and starts to export in the background, Stop flushes and ends it
+func NewOTLPExporter(options OTLPOptions) *OTLPExporter {
+	if options.BatchSize <= 0 {
+		options.BatchSize = 512
+	}
+	if options.MaxQueueSize <= 0 {
+		options.MaxQueueSize = 2048
+	}
+	if options.MaxQueueSize < options.BatchSize {
+		options.MaxQueueSize = options.BatchSize
+	}
+	if options.Interval <= 0 {
+		options.Interval = 5 * time.Second
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = 10 * time.Second
+	}
+	if options.OnError == nil {
+		options.OnError = func(error) {}
+	}
+
+	e := &OTLPExporter{
+		options: options,
+		full:    make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+	}
+
+	e.stopped.Add(1)
+	go e.run()
+
+	return e
+}
+
+// ExportSpan buffers the span for the next export, the span is dropped if the queue is full
+func (e *OTLPExporter) ExportSpan(s *trace.SpanData) {
+	span := convertSpan(s)
+
+	e.mu.Lock()
+	queued := len(e.spans) < e.options.MaxQueueSize
+	if queued {
+		e.spans = append(e.spans, span)
+	}
+	full := len(e.spans) >= e.options.BatchSize
+	e.mu.Unlock()
+
+	if !queued {
+		atomic.AddInt64(&e.dropped, 1)
+		stats.Record(context.Background(), otlpDroppedMeasure.M(1))
+	}
+
+	if full {
+		select {
+		case e.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Dropped returns the number of spans which were dropped because the queue was full
+func (e *OTLPExporter) Dropped() int64 {
+	return atomic.LoadInt64(&e.dropped)
+}
+
+// Flush exports the buffered spans
+func (e *OTLPExporter) Flush() {
+	e.mu.Lock()
+	spans := e.spans
+	e.spans = nil
+	e.mu.Unlock()
+
+	if len(spans) == 0 {
+		return
+	}
+
+	e.sendMu.Lock()
+	defer e.sendMu.Unlock()
+
+	if err := e.send(spans); err != nil {
+		e.options.OnError(err)
+	}
+}
+
+// Stop the background export and flush the buffered spans
+func (e *OTLPExporter) Stop() {
+	close(e.stop)
+	e.stopped.Wait()
+	e.Flush()
+}
+
+func (e *OTLPExporter) run() {
+	defer e.stopped.Done()
+
+	ticker := time.NewTicker(e.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-e.stop:
+			return
+		case <-ticker.C:
+			e.Flush()
+		case <-e.full:
+			e.Flush()
+		}
+	}
+}
+
+func (e *OTLPExporter) send(spans []otlpSpan) error {
+	body, err := json.Marshal(otlpRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: otlpResource{
+				Attributes: []otlpAttribute{otlpAttributeOf("service.name", e.options.ServiceName)},
+			},
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: otlpScopeName},
+				Spans: spans,
+			}},
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("otlp: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.options.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequest(http.MethodPost, e.options.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("otlp: %w", err)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.options.Headers {
+		req.Header.Set(k, v)
+	}
+
+	// the export must not be traced itself, so it uses an own client
+	resp, err := otlpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("otlp: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("otlp: export of %d spans failed: %s", len(spans), resp.Status)
+	}
+
+	return nil
+}
+
+var otlpClient = &http.Client{
+	Transport: &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		MaxIdleConnsPerHost: 2,
+		IdleConnTimeout:     90 * time.Second,
+	},
+}
+
+func convertSpan(s *trace.SpanData) otlpSpan {
+	span := otlpSpan{
+		TraceID:           s.TraceID.String(),
+		SpanID:            s.SpanID.String(),
+		Name:              s.Name,
+		Kind:              otlpSpanKindInternal,
+		StartTimeUnixNano: unixNano(s.StartTime),
+		EndTimeUnixNano:   unixNano(s.EndTime),
+		Attributes:        otlpAttributes(s.Attributes),
+	}
+
+	if s.ParentSpanID != (trace.SpanID{}) {
+		span.ParentSpanID = s.ParentSpanID.String()
+	}
+
+	if s.Tracestate != nil {
+		var traceState bytes.Buffer
+		for _, entry := range s.Tracestate.Entries() {
+			if traceState.Len() > 0 {
+				traceState.WriteByte(',')
+			}
+			traceState.WriteString(entry.Key + "=" + entry.Value)
+		}
+		span.TraceState = traceState.String()
+	}
+
+	switch s.SpanKind {
+	case trace.SpanKindServer:
+		span.Kind = otlpSpanKindServer
+	case trace.SpanKindClient:
+		span.Kind = otlpSpanKindClient
+	}
+
+	// OpenCensus uses the code 0 for unset and OK
+	if s.Code != trace.StatusCodeOK {
+		span.Status = otlpStatus{Code: otlpStatusError, Message: s.Message}
+	}
+
+	for _, annotation := range s.Annotations {
+		span.Events = append(span.Events, otlpEvent{
+			TimeUnixNano: unixNano(annotation.Time),
+			Name:         annotation.Message,
+			Attributes:   otlpAttributes(annotation.Attributes),
+		})
+	}
+
+	for _, link := range s.Links {
+		span.Links = append(span.Links, otlpLink{
+			TraceID:    link.TraceID.String(),
+			SpanID:     link.SpanID.String(),
+			Attributes: otlpAttributes(link.Attributes),
+		})
+	}
+
+	return span
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+func otlpAttributes(attributes map[string]interface{}) []otlpAttribute {
+	if len(attributes) == 0 {
+		return nil
+	}
+
+	result := make([]otlpAttribute, 0, len(attributes))
+	for k, v := range attributes {
+		result = append(result, otlpAttributeOf(k, v))
+	}
+
+	return result
+}
+
+func otlpAttributeOf(key string, value interface{}) otlpAttribute {
+	attribute := otlpAttribute{Key: key}
+	switch v := value.(type) {
+	case string:
+		attribute.Value.StringValue = &v
+	case bool:
+		attribute.Value.BoolValue = &v
+	case int64:
+		i := strconv.FormatInt(v, 10)
+		attribute.Value.IntValue = &i
+	case float64:
+		attribute.Value.DoubleValue = &v
+	default:
+		s := fmt.Sprint(v)
+		attribute.Value.StringValue = &s
+	}
+
+	return attribute
+}
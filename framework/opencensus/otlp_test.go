@@ -0,0 +1,116 @@
+package opencensus_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/trace"
+
+	"flamingo.me/flamingo/v3/framework/opencensus"
+)
+
+func TestOTLPExporter(t *testing.T) {
+	collector := opencensus.NewCollector()
+	server := httptest.NewServer(collector)
+	defer server.Close()
+
+	exporter := opencensus.NewOTLPExporter(opencensus.OTLPOptions{
+		Endpoint:    server.URL,
+		ServiceName: "test",
+	})
+	trace.RegisterExporter(exporter)
+	defer trace.UnregisterExporter(exporter)
+
+	ctx, parent := trace.StartSpan(context.Background(), "parent", trace.WithSampler(trace.AlwaysSample()), trace.WithSpanKind(trace.SpanKindServer))
+	_, child := trace.StartSpan(ctx, "child")
+	child.AddAttributes(trace.StringAttribute("string", "value"), trace.Int64Attribute("int", 42), trace.BoolAttribute("bool", true))
+	child.Annotate(nil, "something happened")
+	child.SetStatus(trace.Status{Code: trace.StatusCodeInternal, Message: "failed"})
+	child.End()
+	parent.End()
+
+	exporter.Stop()
+
+	spans := collector.Spans()
+	require.Len(t, spans, 2)
+
+	assert.Equal(t, "child", spans[0].Name)
+	assert.Equal(t, "test", spans[0].ServiceName)
+	assert.Equal(t, parent.SpanContext().TraceID.String(), spans[0].TraceID)
+	assert.Equal(t, parent.SpanContext().SpanID.String(), spans[0].ParentSpanID)
+	assert.Equal(t, map[string]interface{}{"string": "value", "int": int64(42), "bool": true}, spans[0].Attributes)
+	assert.Equal(t, []string{"something happened"}, spans[0].Events)
+	assert.True(t, spans[0].Error)
+	assert.Equal(t, "failed", spans[0].Message)
+	assert.Equal(t, 1, spans[0].Kind)
+
+	assert.Equal(t, "parent", spans[1].Name)
+	assert.Empty(t, spans[1].ParentSpanID)
+	assert.False(t, spans[1].Error)
+	assert.Equal(t, 2, spans[1].Kind)
+	assert.False(t, spans[1].End.Before(spans[1].Start))
+}
+
+func TestOTLPExporter_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	var exportErr error
+	exporter := opencensus.NewOTLPExporter(opencensus.OTLPOptions{
+		Endpoint: server.URL,
+		OnError: func(err error) {
+			exportErr = err
+		},
+	})
+
+	_, span := trace.StartSpan(context.Background(), "span", trace.WithSampler(trace.AlwaysSample()))
+	exporter.ExportSpan(&trace.SpanData{SpanContext: span.SpanContext(), Name: "span"})
+	exporter.Stop()
+
+	assert.Error(t, exportErr)
+}
+
+func TestOTLPExporter_MaxQueueSize(t *testing.T) {
+	collector := opencensus.NewCollector()
+	received := make(chan struct{}, 1)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case received <- struct{}{}:
+			<-release
+		default:
+		}
+		collector.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	exporter := opencensus.NewOTLPExporter(opencensus.OTLPOptions{
+		Endpoint:     server.URL,
+		BatchSize:    100,
+		MaxQueueSize: 100,
+		Interval:     time.Hour,
+	})
+
+	_, span := trace.StartSpan(context.Background(), "span", trace.WithSampler(trace.AlwaysSample()))
+	export := func(n int) {
+		for i := 0; i < n; i++ {
+			exporter.ExportSpan(&trace.SpanData{SpanContext: span.SpanContext(), Name: "span"})
+		}
+	}
+
+	export(100)
+	<-received
+	export(105)
+	close(release)
+	exporter.Stop()
+
+	assert.Equal(t, int64(5), exporter.Dropped(), "spans are dropped while the collector is slow")
+	assert.Len(t, collector.Spans(), 200)
+}
@@ -0,0 +1,80 @@
+package opencensus
+
+import (
+	"net/http"
+
+	"go.opencensus.io/plugin/ochttp"
+	"go.opencensus.io/plugin/ochttp/propagation/b3"
+	"go.opencensus.io/plugin/ochttp/propagation/tracecontext"
+	"go.opencensus.io/trace"
+	"go.opencensus.io/trace/propagation"
+)
+
+// Propagation formats of flamingo.opencensus.tracing.propagation
+const (
+	PropagationB3  = "b3"
+	PropagationW3C = "w3c"
+)
+
+// Propagation instruments incoming and outgoing requests with the configured trace context propagation:
+// B3 headers, or the W3C traceparent and tracestate headers. W3C baggage is propagated if enabled.
+type Propagation struct {
+	format         string
+	baggage        bool
+	publicEndpoint bool
+}
+
+// Inject configuration
+func (p *Propagation) Inject(cfg *struct {
+	Format         string `inject:"config:flamingo.opencensus.tracing.propagation,optional"`
+	Baggage        bool   `inject:"config:flamingo.opencensus.tracing.baggage,optional"`
+	PublicEndpoint bool   `inject:"config:flamingo.opencensus.tracing.publicEndpoint,optional"`
+}) *Propagation {
+	if cfg != nil {
+		p.format = cfg.Format
+		p.baggage = cfg.Baggage
+		p.publicEndpoint = cfg.PublicEndpoint
+	}
+
+	return p
+}
+
+// HTTPFormat of the trace context, B3 is the default
+func (p *Propagation) HTTPFormat() propagation.HTTPFormat {
+	if p != nil && p.format == PropagationW3C {
+		return new(tracecontext.HTTPFormat)
+	}
+
+	return new(b3.HTTPFormat)
+}
+
+// Handler traces incoming requests. Requests of public endpoints start a new trace which is linked to
+// the incoming trace context, otherwise the incoming trace is continued.
+// The decision of the ConfiguredURLPrefixSampler is added to the request span.
+func (p *Propagation) Handler(handler http.Handler, getStartOptions func(*http.Request) trace.StartOptions) http.Handler {
+	if p != nil && p.baggage {
+		handler = baggageHandler{next: handler}
+	}
+
+	return samplerDecisionContext{next: &ochttp.Handler{
+		IsPublicEndpoint: p == nil || p.publicEndpoint,
+		Propagation:      p.HTTPFormat(),
+		Handler:          samplerDecisionHandler{next: handler},
+		GetStartOptions:  getStartOptions,
+	}}
+}
+
+// Transport traces outgoing requests and propagates the trace context and baggage
+func (p *Propagation) Transport(base http.RoundTripper) http.RoundTripper {
+	var transport http.RoundTripper = &ochttp.Transport{Base: base, Propagation: p.HTTPFormat()}
+	if p != nil && p.baggage {
+		transport = baggageTransport{next: transport}
+	}
+
+	return transport
+}
+
+// ClientTransport is the Transport which additionally sends the trace id as X-Correlation-ID header
+func (p *Propagation) ClientTransport(base http.RoundTripper) http.RoundTripper {
+	return &correlationIDInjector{next: p.Transport(base)}
+}
@@ -0,0 +1,70 @@
+package opencensus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opencensus.io/trace"
+)
+
+func TestPropagation_W3C(t *testing.T) {
+	propagation := new(Propagation).Inject(&struct {
+		Format         string `inject:"config:flamingo.opencensus.tracing.propagation,optional"`
+		Baggage        bool   `inject:"config:flamingo.opencensus.tracing.baggage,optional"`
+		PublicEndpoint bool   `inject:"config:flamingo.opencensus.tracing.publicEndpoint,optional"`
+	}{Format: PropagationW3C, Baggage: true})
+
+	var traceparent, baggage string
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		baggage = r.Header.Get("baggage")
+	}))
+	defer backend.Close()
+
+	var traceID trace.TraceID
+	handler := propagation.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceID = trace.FromContext(r.Context()).SpanContext().TraceID
+		assert.Equal(t, map[string]string{"user": "a b", "tenant": "x"}, Baggage(r.Context()))
+
+		req, _ := http.NewRequest(http.MethodGet, backend.URL, nil)
+		resp, err := (&http.Client{Transport: propagation.Transport(http.DefaultTransport)}).Do(req.WithContext(r.Context()))
+		if assert.NoError(t, err) {
+			resp.Body.Close()
+		}
+	}), func(*http.Request) trace.StartOptions { return trace.StartOptions{Sampler: trace.AlwaysSample()} })
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	request.Header.Set("baggage", "user=a%20b;property, tenant=x")
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", traceID.String(), "the incoming trace is continued")
+	assert.Regexp(t, "^00-4bf92f3577b34da6a3ce929d0e0e4736-[0-9a-f]{16}-01$", traceparent)
+	assert.Equal(t, "tenant=x,user=a%20b", baggage)
+}
+
+func TestPropagation_Default(t *testing.T) {
+	var propagation *Propagation
+
+	var traceID trace.TraceID
+	handler := propagation.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceID = trace.FromContext(r.Context()).SpanContext().TraceID
+		assert.Empty(t, Baggage(r.Context()))
+	}), nil)
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("X-B3-TraceId", "4bf92f3577b34da6a3ce929d0e0e4736")
+	request.Header.Set("X-B3-SpanId", "00f067aa0ba902b7")
+	request.Header.Set("baggage", "user=a")
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	assert.NotEqual(t, "4bf92f3577b34da6a3ce929d0e0e4736", traceID.String(), "public endpoints start a new trace")
+}
+
+func TestBaggage(t *testing.T) {
+	assert.Equal(t, map[string]string{"a": "1", "b": "x=y"}, parseBaggage("a=1,b=x%3Dy;prop=1,invalid,=empty"))
+	assert.Nil(t, parseBaggage(""))
+	assert.Equal(t, "a=1,b=x=y", formatBaggage(map[string]string{"b": "x=y", "a": "1"}))
+}
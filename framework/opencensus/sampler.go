@@ -1,8 +1,13 @@
 package opencensus
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"flamingo.me/flamingo/v3/framework/config"
 	"go.opencensus.io/trace"
@@ -55,18 +60,331 @@ func URLPrefixSampler(whitelist, blacklist []string, allowParentTrace bool) func
 	}
 }
 
-// ConfiguredURLPrefixSampler constructs the prefix GetStartOptions getter with the default opencensus configuration
+// ConfiguredURLPrefixSampler constructs the GetStartOptions getter with the sampling strategies of the opencensus configuration
 type ConfiguredURLPrefixSampler struct {
 	Whitelist        config.Slice `inject:"config:flamingo.opencensus.tracing.sampler.whitelist,optional"`
 	Blacklist        config.Slice `inject:"config:flamingo.opencensus.tracing.sampler.blacklist,optional"`
 	AllowParentTrace bool         `inject:"config:flamingo.opencensus.tracing.sampler.allowParentTrace,optional"`
+	Sampler          config.Map   `inject:"config:flamingo.opencensus.tracing.sampler,optional"`
+
+	once    sync.Once
+	options samplerOptions
+	limiter *rateLimiter
+	tail    *TailSampler
 }
 
-// GetStartOptions constructor for ochttp.Server
-func (c *ConfiguredURLPrefixSampler) GetStartOptions() func(*http.Request) trace.StartOptions {
+type (
+	// samplerOptions are the sampling strategies of flamingo.opencensus.tracing.sampler
+	samplerOptions struct {
+		Probability float64
+		Rules       []samplerRule
+		// RateLimit of sampled traces per second
+		RateLimit   float64
+		DebugHeader string
+		Tail        struct {
+			Errors     bool
+			SlowerThan string
+			MaxTraces  int
+			MaxSpans   int
+			Timeout    string
+		}
+	}
+
+	// samplerRule sets the probability of requests with a path prefix or a handler name
+	samplerRule struct {
+		Prefix      string
+		Handler     string
+		Probability float64
+	}
+
+	// requestSampler decides about the sampling of requests
+	requestSampler struct {
+		whitelist, blacklist []string
+		allowParentTrace     bool
+		probability          float64
+		// rules with a prefix, the longest prefix first
+		rules []samplerRule
+		// deferHandlers to the tail sampler, if there are handler rules
+		deferHandlers bool
+		debugHeader   string
+		limiter       *rateLimiter
+		tail          *TailSampler
+	}
+
+	// samplerDecision of a request span, see Propagation.Handler
+	samplerDecision struct {
+		reason      string
+		probability float64
+	}
+
+	// samplerDecisionContext adds the storage of the sampler decision to the request context, before the
+	// request span is started by the next handler
+	samplerDecisionContext struct {
+		next http.Handler
+	}
+
+	// samplerDecisionKey of the *samplerDecision in the request context
+	samplerDecisionKey struct{}
+
+	samplerDecisionHandler struct {
+		next http.Handler
+	}
+
+	// rateLimiter is a token bucket, which allows a burst of one second
+	rateLimiter struct {
+		mu     sync.Mutex
+		rate   float64
+		tokens float64
+		last   time.Time
+	}
+)
+
+// reasons of the sampler decisions, the decision of a sampled request is added to its span
+const (
+	samplerReasonDebug       = "debug"
+	samplerReasonParent      = "parent"
+	samplerReasonRoute       = "route"
+	samplerReasonProbability = "probability"
+	samplerReasonHandler     = "handler"
+	samplerReasonError       = "error"
+	samplerReasonSlow        = "slow"
+	// samplerReasonDeferred traces are buffered by the TailSampler until the request span ends
+	samplerReasonDeferred = "deferred"
+
+	samplerDecisionAttribute    = "sampler.decision"
+	samplerProbabilityAttribute = "sampler.probability"
+)
+
+// GetStartOptions constructor for ochttp.Server, the white- and blacklist as well as the prefix rules are
+// also applied below the given prefixes. The decision is added to the request span by Propagation.Handler,
+// without it the decision is not recorded.
+func (c *ConfiguredURLPrefixSampler) GetStartOptions(prefixes ...string) func(*http.Request) trace.StartOptions {
+	c.init()
+
 	var whitelist, blacklist []string
 	c.Whitelist.MapInto(&whitelist)
 	c.Blacklist.MapInto(&blacklist)
 
-	return URLPrefixSampler(whitelist, blacklist, c.AllowParentTrace)
+	sampler := &requestSampler{
+		whitelist:        withPrefixes(whitelist, prefixes),
+		blacklist:        withPrefixes(blacklist, prefixes),
+		allowParentTrace: c.AllowParentTrace,
+		probability:      c.options.Probability,
+		deferHandlers:    c.tail != nil && len(c.tail.options.Handlers) > 0,
+		debugHeader:      c.options.DebugHeader,
+		limiter:          c.limiter,
+		tail:             c.tail,
+	}
+
+	for _, rule := range c.options.Rules {
+		if rule.Handler != "" {
+			continue
+		}
+		for _, prefix := range withPrefixes([]string{rule.Prefix}, prefixes) {
+			sampler.rules = append(sampler.rules, samplerRule{Prefix: prefix, Probability: rule.Probability})
+		}
+	}
+	sort.SliceStable(sampler.rules, func(i, j int) bool {
+		return len(sampler.rules[i].Prefix) > len(sampler.rules[j].Prefix)
+	})
+
+	return sampler.startOptions
+}
+
+// TailSampler buffers the deferred traces, it is nil unless tail sampling or handler rules are configured
+func (c *ConfiguredURLPrefixSampler) TailSampler() *TailSampler {
+	c.init()
+
+	return c.tail
+}
+
+func (c *ConfiguredURLPrefixSampler) init() {
+	c.once.Do(func() {
+		c.options.Probability = 1
+		c.options.Tail.MaxTraces = 1000
+		c.options.Tail.MaxSpans = 1000
+		c.options.Tail.Timeout = "1m"
+		if err := c.Sampler.MapInto(&c.options); err != nil {
+			panic(fmt.Errorf("flamingo.opencensus.tracing.sampler: %w", err))
+		}
+
+		if c.options.RateLimit > 0 {
+			c.limiter = newRateLimiter(c.options.RateLimit)
+		}
+
+		var slowerThan time.Duration
+		if c.options.Tail.SlowerThan != "" {
+			var err error
+			if slowerThan, err = time.ParseDuration(c.options.Tail.SlowerThan); err != nil {
+				panic(fmt.Errorf("flamingo.opencensus.tracing.sampler.tail.slowerThan: %w", err))
+			}
+		}
+
+		timeout, err := time.ParseDuration(c.options.Tail.Timeout)
+		if err != nil {
+			panic(fmt.Errorf("flamingo.opencensus.tracing.sampler.tail.timeout: %w", err))
+		}
+
+		handlers := make(map[string]float64)
+		for _, rule := range c.options.Rules {
+			if rule.Handler != "" {
+				handlers[rule.Handler] = rule.Probability
+			}
+		}
+
+		if c.options.Tail.Errors || slowerThan > 0 || len(handlers) > 0 {
+			c.tail = NewTailSampler(TailOptions{
+				Errors:      c.options.Tail.Errors,
+				SlowerThan:  slowerThan,
+				Handlers:    handlers,
+				Probability: c.options.Probability,
+				MaxTraces:   c.options.Tail.MaxTraces,
+				MaxSpans:    c.options.Tail.MaxSpans,
+				Timeout:     timeout,
+				limiter:     c.limiter,
+			})
+		}
+	})
+}
+
+func withPrefixes(paths, prefixes []string) []string {
+	result := append([]string(nil), paths...)
+	for _, prefix := range prefixes {
+		for _, path := range paths {
+			result = append(result, prefix+path)
+		}
+	}
+
+	return result
+}
+
+func hasPrefix(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *requestSampler) startOptions(r *http.Request) trace.StartOptions {
+	return trace.StartOptions{
+		Sampler: func(p trace.SamplingParameters) trace.SamplingDecision {
+			decision, sample := s.decide(r, p)
+			// the decision is only kept for requests of Propagation.Handler, which adds it to the span
+			if stored, ok := r.Context().Value(samplerDecisionKey{}).(*samplerDecision); ok && sample {
+				*stored = decision
+			}
+
+			return trace.SamplingDecision{Sample: sample}
+		},
+	}
+}
+
+// decide about the sampling in the order: debug header, white- and blacklist, sampled parent, prefix rules,
+// handler rules and the default probability. Traces which are not sampled are deferred to the tail sampler.
+func (s *requestSampler) decide(r *http.Request, p trace.SamplingParameters) (samplerDecision, bool) {
+	if s.debugHeader != "" {
+		if value := r.Header.Get(s.debugHeader); value != "" && value != "0" && value != "false" {
+			return samplerDecision{reason: samplerReasonDebug}, true
+		}
+	}
+
+	path := r.URL.Path
+	if (len(s.whitelist) > 0 && !hasPrefix(path, s.whitelist)) || hasPrefix(path, s.blacklist) {
+		return samplerDecision{}, false
+	}
+
+	if s.allowParentTrace && p.HasRemoteParent && p.ParentContext.IsSampled() {
+		return samplerDecision{reason: samplerReasonParent}, true
+	}
+
+	decision := samplerDecision{reason: samplerReasonProbability, probability: s.probability}
+	for _, rule := range s.rules {
+		if strings.HasPrefix(path, rule.Prefix) {
+			decision = samplerDecision{reason: samplerReasonRoute, probability: rule.Probability}
+			break
+		}
+	}
+
+	// the handler is known after the routing, so the tail sampler decides
+	if decision.reason == samplerReasonProbability && s.deferHandlers && s.tail.deferTrace(p.TraceID, p.SpanID, true) {
+		return samplerDecision{reason: samplerReasonDeferred}, true
+	}
+
+	if trace.ProbabilitySampler(decision.probability)(p).Sample && s.limiter.allow() {
+		return decision, true
+	}
+
+	if s.tail.deferTrace(p.TraceID, p.SpanID, false) {
+		return samplerDecision{reason: samplerReasonDeferred}, true
+	}
+
+	return samplerDecision{}, false
+}
+
+// attributes of the decision
+func (d samplerDecision) attributes() []trace.Attribute {
+	attributes := []trace.Attribute{trace.StringAttribute(samplerDecisionAttribute, d.reason)}
+	if d.reason == samplerReasonRoute || d.reason == samplerReasonProbability || d.reason == samplerReasonHandler {
+		attributes = append(attributes, trace.Float64Attribute(samplerProbabilityAttribute, d.probability))
+	}
+
+	return attributes
+}
+
+// ServeHTTP with an empty sampler decision in the request context, it is set by the sampler of the request span
+func (h samplerDecisionContext) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), samplerDecisionKey{}, new(samplerDecision))))
+}
+
+// ServeHTTP adds the sampler decision to the request span
+func (h samplerDecisionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	decision, ok := r.Context().Value(samplerDecisionKey{}).(*samplerDecision)
+	if span := trace.FromContext(r.Context()); ok && decision.reason != "" && span != nil && span.IsRecordingEvents() {
+		span.AddAttributes(decision.attributes()...)
+	}
+
+	h.next.ServeHTTP(w, r)
+}
+
+func newRateLimiter(rate float64) *rateLimiter {
+	l := &rateLimiter{rate: rate, last: time.Now()}
+	l.tokens = l.burst()
+
+	return l
+}
+
+// allow takes a token, a nil limiter allows everything
+func (l *rateLimiter) allow() bool {
+	if l == nil {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	l.last = now
+	if max := l.burst(); l.tokens > max {
+		l.tokens = max
+	}
+
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+
+	return true
+}
+
+// burst of one second, at least one trace
+func (l *rateLimiter) burst() float64 {
+	if l.rate < 1 {
+		return 1
+	}
+
+	return l.rate
 }
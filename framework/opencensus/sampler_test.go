@@ -0,0 +1,146 @@
+package opencensus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opencensus.io/plugin/ochttp"
+	"go.opencensus.io/trace"
+
+	"flamingo.me/flamingo/v3/framework/config"
+)
+
+type recordingExporter struct {
+	spans chan *trace.SpanData
+}
+
+func (e *recordingExporter) ExportSpan(s *trace.SpanData) {
+	e.spans <- s
+}
+
+func sampled(t *testing.T, sampler *ConfiguredURLPrefixSampler, request *http.Request, prefixes ...string) map[string]interface{} {
+	t.Helper()
+
+	exporter := &recordingExporter{spans: make(chan *trace.SpanData, 10)}
+	trace.RegisterExporter(exporter)
+	defer trace.UnregisterExporter(exporter)
+
+	new(Propagation).Handler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}), sampler.GetStartOptions(prefixes...)).ServeHTTP(httptest.NewRecorder(), request)
+
+	select {
+	case span := <-exporter.spans:
+		return span.Attributes
+	default:
+		return nil
+	}
+}
+
+func TestConfiguredURLPrefixSampler(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		attributes := sampled(t, new(ConfiguredURLPrefixSampler), httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, "probability", attributes["sampler.decision"])
+		assert.Equal(t, 1.0, attributes["sampler.probability"])
+	})
+
+	t.Run("lists", func(t *testing.T) {
+		sampler := &ConfiguredURLPrefixSampler{
+			Whitelist: config.Slice{"/shop"},
+			Blacklist: config.Slice{"/shop/health"},
+		}
+
+		assert.NotNil(t, sampled(t, sampler, httptest.NewRequest(http.MethodGet, "/shop/product", nil)))
+		assert.NotNil(t, sampled(t, sampler, httptest.NewRequest(http.MethodGet, "/de/shop/product", nil), "/de"))
+		assert.Nil(t, sampled(t, sampler, httptest.NewRequest(http.MethodGet, "/cms", nil)))
+		assert.Nil(t, sampled(t, sampler, httptest.NewRequest(http.MethodGet, "/shop/health", nil)))
+	})
+
+	t.Run("rules", func(t *testing.T) {
+		sampler := &ConfiguredURLPrefixSampler{Sampler: config.Map{
+			"probability": 0.0,
+			"rules": config.Slice{
+				config.Map{"prefix": "/checkout", "probability": 1.0},
+				config.Map{"prefix": "/checkout/static", "probability": 0.0},
+			},
+		}}
+
+		attributes := sampled(t, sampler, httptest.NewRequest(http.MethodGet, "/checkout/cart", nil))
+		assert.Equal(t, "route", attributes["sampler.decision"])
+		assert.Equal(t, 1.0, attributes["sampler.probability"])
+		assert.Nil(t, sampled(t, sampler, httptest.NewRequest(http.MethodGet, "/checkout/static/app.js", nil)))
+		assert.Nil(t, sampled(t, sampler, httptest.NewRequest(http.MethodGet, "/", nil)))
+	})
+
+	t.Run("debug header", func(t *testing.T) {
+		sampler := &ConfiguredURLPrefixSampler{
+			Blacklist: config.Slice{"/"},
+			Sampler:   config.Map{"debugHeader": "X-Debug-Trace"},
+		}
+
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		request.Header.Set("X-Debug-Trace", "1")
+		assert.Equal(t, map[string]interface{}{"sampler.decision": "debug"}, pick(sampled(t, sampler, request), "sampler.decision", "sampler.probability"))
+
+		request.Header.Set("X-Debug-Trace", "0")
+		assert.Nil(t, sampled(t, sampler, request))
+	})
+
+	t.Run("rate limit", func(t *testing.T) {
+		sampler := &ConfiguredURLPrefixSampler{Sampler: config.Map{"rateLimit": 2.0}}
+
+		count := 0
+		for i := 0; i < 5; i++ {
+			if sampled(t, sampler, httptest.NewRequest(http.MethodGet, "/", nil)) != nil {
+				count++
+			}
+		}
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("without Propagation.Handler", func(t *testing.T) {
+		exporter := &recordingExporter{spans: make(chan *trace.SpanData, 10)}
+		trace.RegisterExporter(exporter)
+		defer trace.UnregisterExporter(exporter)
+
+		handler := &ochttp.Handler{
+			Handler:         http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}),
+			GetStartOptions: new(ConfiguredURLPrefixSampler).GetStartOptions(),
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+		select {
+		case span := <-exporter.spans:
+			assert.NotContains(t, span.Attributes, "sampler.decision", "the decision is not kept without Propagation.Handler")
+		default:
+			t.Fatal("the request is not sampled")
+		}
+	})
+}
+
+func pick(m map[string]interface{}, keys ...string) map[string]interface{} {
+	result := make(map[string]interface{})
+	for _, k := range keys {
+		if v, ok := m[k]; ok {
+			result[k] = v
+		}
+	}
+
+	return result
+}
+
+func TestRateLimiter(t *testing.T) {
+	limiter := newRateLimiter(10)
+	for i := 0; i < 10; i++ {
+		assert.True(t, limiter.allow())
+	}
+	assert.False(t, limiter.allow())
+
+	limiter.last = limiter.last.Add(-100 * time.Millisecond)
+	assert.True(t, limiter.allow())
+	assert.False(t, limiter.allow())
+
+	var unlimited *rateLimiter
+	assert.True(t, unlimited.allow())
+}
@@ -0,0 +1,259 @@
+package opencensus
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"go.opencensus.io/plugin/ochttp"
+	"go.opencensus.io/trace"
+)
+
+type (
+	// TailSampler buffers the spans of deferred traces until their request span ends, and decides then
+	// which traces are exported: traces with errors, slow requests and the handler rules.
+	// The exporters are registered at the TailSampler instead of trace.RegisterExporter.
+	TailSampler struct {
+		options TailOptions
+
+		mu          sync.Mutex
+		exporters   map[trace.Exporter]struct{}
+		traces      map[trace.TraceID]*tailTrace
+		lastCleanup time.Time
+		// pending is the number of undecided traces, only they count against MaxTraces
+		pending int
+	}
+
+	// TailOptions configure the TailSampler
+	TailOptions struct {
+		// Errors samples traces with a span with an error status
+		Errors bool
+		// SlowerThan samples traces with a request span slower than this
+		SlowerThan time.Duration
+		// Handlers with the sampling probability of the requests, which are routed to them
+		Handlers map[string]float64
+		// Probability of the requests of other handlers
+		Probability float64
+		// MaxTraces is the maximum number of undecided buffered traces, new traces are not sampled if it is exceeded
+		MaxTraces int
+		// MaxSpans of a buffered trace, further spans are dropped
+		MaxSpans int
+		// Timeout after which a buffered trace is dropped, and the decision about a trace is forgotten
+		Timeout time.Duration
+
+		// limiter of the sampled traces, shared with the requestSampler
+		limiter *rateLimiter
+	}
+
+	tailTrace struct {
+		root     trace.SpanID
+		started  time.Time
+		handlers bool
+		spans    []*trace.SpanData
+		// decided traces are exported or dropped, for spans which end after the request span
+		decided bool
+		sampled bool
+	}
+)
+
+// HandlerAttribute is the span attribute with the name of the handler of a request, which is set by the router
+const HandlerAttribute = "flamingo.handler"
+
+var _ trace.Exporter = new(TailSampler)
+
+// NewTailSampler creates a TailSampler
+func NewTailSampler(options TailOptions) *TailSampler {
+	if options.MaxTraces <= 0 {
+		options.MaxTraces = 1000
+	}
+	if options.MaxSpans <= 0 {
+		options.MaxSpans = 1000
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = time.Minute
+	}
+
+	return &TailSampler{
+		options:   options,
+		exporters: make(map[trace.Exporter]struct{}),
+		traces:    make(map[trace.TraceID]*tailTrace),
+	}
+}
+
+// RegisterExporter adds an exporter for the sampled traces
+func (t *TailSampler) RegisterExporter(exporter trace.Exporter) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.exporters[exporter] = struct{}{}
+}
+
+// UnregisterExporter removes an exporter
+func (t *TailSampler) UnregisterExporter(exporter trace.Exporter) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.exporters, exporter)
+}
+
+// deferTrace buffers the trace of the request span, handlers are decided by the handler rules.
+// It returns false if the buffer is full, a nil TailSampler does not defer traces.
+func (t *TailSampler) deferTrace(traceID trace.TraceID, root trace.SpanID, handlers bool) bool {
+	if t == nil {
+		return false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	if t.pending >= t.options.MaxTraces || now.Sub(t.lastCleanup) > time.Second {
+		t.cleanup(now)
+	}
+	if t.pending >= t.options.MaxTraces {
+		return false
+	}
+
+	if buffered, ok := t.traces[traceID]; !ok || buffered.decided {
+		t.pending++
+	}
+	t.traces[traceID] = &tailTrace{root: root, started: now, handlers: handlers}
+
+	return true
+}
+
+// cleanup removes the traces older than the timeout
+func (t *TailSampler) cleanup(now time.Time) {
+	t.lastCleanup = now
+	for traceID, buffered := range t.traces {
+		if now.Sub(buffered.started) > t.options.Timeout {
+			if !buffered.decided {
+				t.pending--
+			}
+			delete(t.traces, traceID)
+		}
+	}
+}
+
+// ExportSpan buffers the spans of deferred traces, other spans are exported immediately
+func (t *TailSampler) ExportSpan(s *trace.SpanData) {
+	t.mu.Lock()
+	buffered, ok := t.traces[s.TraceID]
+	switch {
+	case !ok, buffered.decided && buffered.sampled:
+		t.mu.Unlock()
+		t.export(s)
+		return
+	case buffered.decided:
+		t.mu.Unlock()
+		return
+	}
+
+	if len(buffered.spans) < t.options.MaxSpans || s.SpanID == buffered.root {
+		buffered.spans = append(buffered.spans, s)
+	}
+	if s.SpanID != buffered.root {
+		t.mu.Unlock()
+		return
+	}
+
+	decision, sampled := t.decide(buffered, s)
+	spans := buffered.spans
+	buffered.spans, buffered.decided, buffered.sampled = nil, true, sampled
+	t.pending--
+	t.mu.Unlock()
+
+	if !sampled {
+		return
+	}
+
+	for _, span := range spans {
+		if span == s {
+			span = withSamplerDecision(s, decision)
+		}
+		t.export(span)
+	}
+}
+
+func (t *TailSampler) export(s *trace.SpanData) {
+	t.mu.Lock()
+	exporters := make([]trace.Exporter, 0, len(t.exporters))
+	for exporter := range t.exporters {
+		exporters = append(exporters, exporter)
+	}
+	t.mu.Unlock()
+
+	for _, exporter := range exporters {
+		exporter.ExportSpan(s)
+	}
+}
+
+// decide about a buffered trace when its request span ends
+func (t *TailSampler) decide(buffered *tailTrace, root *trace.SpanData) (samplerDecision, bool) {
+	if t.options.Errors {
+		for _, span := range buffered.spans {
+			if isError(span) {
+				return samplerDecision{reason: samplerReasonError}, true
+			}
+		}
+	}
+
+	if t.options.SlowerThan > 0 && root.EndTime.Sub(root.StartTime) >= t.options.SlowerThan {
+		return samplerDecision{reason: samplerReasonSlow}, true
+	}
+
+	if !buffered.handlers {
+		return samplerDecision{}, false
+	}
+
+	decision := samplerDecision{reason: samplerReasonProbability, probability: t.options.Probability}
+	for _, span := range buffered.spans {
+		if handler, ok := span.Attributes[HandlerAttribute].(string); ok {
+			if probability, ok := t.options.Handlers[handler]; ok {
+				decision = samplerDecision{reason: samplerReasonHandler, probability: probability}
+			}
+			break
+		}
+	}
+
+	sampled := trace.ProbabilitySampler(decision.probability)(trace.SamplingParameters{TraceID: root.TraceID}).Sample
+
+	return decision, sampled && t.options.limiter.allow()
+}
+
+// isError is true for server side errors, client errors are ignored
+func isError(s *trace.SpanData) bool {
+	if code, ok := s.Attributes[ochttp.StatusCodeAttribute].(int64); ok {
+		return code >= http.StatusInternalServerError
+	}
+
+	switch s.Code {
+	case trace.StatusCodeOK,
+		trace.StatusCodeCancelled,
+		trace.StatusCodeInvalidArgument,
+		trace.StatusCodeNotFound,
+		trace.StatusCodeAlreadyExists,
+		trace.StatusCodePermissionDenied,
+		trace.StatusCodeResourceExhausted,
+		trace.StatusCodeFailedPrecondition,
+		trace.StatusCodeOutOfRange,
+		trace.StatusCodeUnauthenticated:
+		return false
+	}
+
+	return true
+}
+
+// withSamplerDecision copies the span data, because it is shared with other exporters
+func withSamplerDecision(s *trace.SpanData, decision samplerDecision) *trace.SpanData {
+	span := *s
+	span.Attributes = make(map[string]interface{}, len(s.Attributes)+2)
+	for k, v := range s.Attributes {
+		span.Attributes[k] = v
+	}
+	for _, attribute := range decision.attributes() {
+		span.Attributes[attribute.Key()] = attribute.Value()
+	}
+
+	return &span
+}
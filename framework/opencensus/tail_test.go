@@ -0,0 +1,126 @@
+package opencensus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/trace"
+
+	"flamingo.me/flamingo/v3/framework/config"
+)
+
+func tailSampled(t *testing.T, sampler *ConfiguredURLPrefixSampler, handler http.HandlerFunc) []*trace.SpanData {
+	t.Helper()
+
+	tail := sampler.TailSampler()
+	require.NotNil(t, tail)
+	trace.RegisterExporter(tail)
+	defer trace.UnregisterExporter(tail)
+
+	exporter := &recordingExporter{spans: make(chan *trace.SpanData, 10)}
+	tail.RegisterExporter(exporter)
+	defer tail.UnregisterExporter(exporter)
+
+	new(Propagation).Handler(handler, sampler.GetStartOptions()).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	var spans []*trace.SpanData
+	for {
+		select {
+		case span := <-exporter.spans:
+			spans = append(spans, span)
+		default:
+			return spans
+		}
+	}
+}
+
+func TestTailSampler(t *testing.T) {
+	t.Run("errors and slow requests", func(t *testing.T) {
+		sampler := &ConfiguredURLPrefixSampler{Sampler: config.Map{
+			"probability": 0.0,
+			"tail":        config.Map{"errors": true, "slowerThan": "50ms"},
+		}}
+
+		spans := tailSampled(t, sampler, func(w http.ResponseWriter, r *http.Request) {
+			_, span := trace.StartSpan(r.Context(), "child")
+			span.End()
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+		require.Len(t, spans, 2)
+		assert.Equal(t, "child", spans[0].Name)
+		assert.Equal(t, "error", spans[1].Attributes["sampler.decision"])
+
+		spans = tailSampled(t, sampler, func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(60 * time.Millisecond)
+		})
+		require.Len(t, spans, 1)
+		assert.Equal(t, "slow", spans[0].Attributes["sampler.decision"])
+
+		assert.Empty(t, tailSampled(t, sampler, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+	})
+
+	t.Run("handlers", func(t *testing.T) {
+		sampler := &ConfiguredURLPrefixSampler{Sampler: config.Map{
+			"probability": 0.0,
+			"rules": config.Slice{
+				config.Map{"handler": "checkout.cart", "probability": 1.0},
+			},
+		}}
+
+		routed := func(handler string) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				_, span := trace.StartSpan(r.Context(), "router")
+				span.AddAttributes(trace.StringAttribute(HandlerAttribute, handler))
+				span.End()
+			}
+		}
+
+		spans := tailSampled(t, sampler, routed("checkout.cart"))
+		require.Len(t, spans, 2)
+		assert.Equal(t, "handler", spans[1].Attributes["sampler.decision"])
+		assert.Equal(t, 1.0, spans[1].Attributes["sampler.probability"])
+
+		assert.Empty(t, tailSampled(t, sampler, routed("home")))
+	})
+}
+
+func TestTailSampler_Limits(t *testing.T) {
+	tail := NewTailSampler(TailOptions{Errors: true, MaxTraces: 1, Timeout: time.Second})
+
+	assert.True(t, tail.deferTrace(trace.TraceID{1}, trace.SpanID{1}, false))
+	assert.False(t, tail.deferTrace(trace.TraceID{2}, trace.SpanID{2}, false), "the buffer is full")
+
+	tail.traces[trace.TraceID{1}].started = time.Now().Add(-2 * time.Second)
+	assert.True(t, tail.deferTrace(trace.TraceID{2}, trace.SpanID{2}, false), "expired traces are removed")
+
+	var disabled *TailSampler
+	assert.False(t, disabled.deferTrace(trace.TraceID{3}, trace.SpanID{3}, false))
+}
+
+func TestTailSampler_DecidedTraces(t *testing.T) {
+	tail := NewTailSampler(TailOptions{Errors: true, MaxTraces: 2, Timeout: time.Minute})
+	exporter := &recordingExporter{spans: make(chan *trace.SpanData, 10)}
+	tail.RegisterExporter(exporter)
+
+	for i := byte(1); i <= 5; i++ {
+		require.True(t, tail.deferTrace(trace.TraceID{i}, trace.SpanID{i}, false))
+		tail.ExportSpan(&trace.SpanData{SpanContext: trace.SpanContext{TraceID: trace.TraceID{i}, SpanID: trace.SpanID{i}}})
+	}
+	assert.Empty(t, exporter.spans)
+
+	require.True(t, tail.deferTrace(trace.TraceID{6}, trace.SpanID{6}, false), "decided traces do not count against MaxTraces")
+	tail.ExportSpan(&trace.SpanData{
+		SpanContext:  trace.SpanContext{TraceID: trace.TraceID{6}, SpanID: trace.SpanID{7}},
+		ParentSpanID: trace.SpanID{6},
+		Status:       trace.Status{Code: trace.StatusCodeInternal},
+	})
+	tail.ExportSpan(&trace.SpanData{SpanContext: trace.SpanContext{TraceID: trace.TraceID{6}, SpanID: trace.SpanID{6}}})
+
+	assert.Len(t, exporter.spans, 2, "the error trace is captured")
+}
@@ -0,0 +1,146 @@
+package opentelemetry
+
+import (
+	"fmt"
+	"strings"
+
+	octrace "go.opencensus.io/trace"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	// Bridge exports the OpenCensus spans with the OpenTelemetry SDK of the Tracing.
+	// The spans are bridged after the OpenCensus sampling, so the sampler rules and the tail sampler
+	// of the opencensus module decide about them.
+	Bridge struct {
+		tracing *Tracing
+	}
+)
+
+// bridgeScope is the instrumentation scope of the bridged spans
+const bridgeScope = "go.opencensus.io"
+
+var _ octrace.Exporter = new(Bridge)
+
+// Inject dependencies
+func (b *Bridge) Inject(tracing *Tracing) *Bridge {
+	b.tracing = tracing
+	return b
+}
+
+// ExportSpan passes the finished OpenCensus span to the span processors of the SDK
+func (b *Bridge) ExportSpan(s *octrace.SpanData) {
+	b.tracing.onEnd(b.convert(s))
+}
+
+func (b *Bridge) convert(s *octrace.SpanData) sdktrace.ReadOnlySpan {
+	stub := tracetest.SpanStub{
+		Name:                   s.Name,
+		SpanContext:            spanContext(s.SpanContext, false),
+		SpanKind:               trace.SpanKindInternal,
+		StartTime:              s.StartTime,
+		EndTime:                s.EndTime,
+		Attributes:             attributes(s.Attributes),
+		DroppedAttributes:      s.DroppedAttributeCount,
+		DroppedEvents:          s.DroppedAnnotationCount + s.DroppedMessageEventCount,
+		DroppedLinks:           s.DroppedLinkCount,
+		ChildSpanCount:         s.ChildSpanCount,
+		Resource:               b.tracing.resource,
+		InstrumentationLibrary: instrumentation.Library{Name: bridgeScope},
+	}
+
+	if s.ParentSpanID != (octrace.SpanID{}) {
+		stub.Parent = spanContext(octrace.SpanContext{TraceID: s.TraceID, SpanID: s.ParentSpanID, TraceOptions: s.TraceOptions}, s.HasRemoteParent)
+	}
+
+	switch s.SpanKind {
+	case octrace.SpanKindServer:
+		stub.SpanKind = trace.SpanKindServer
+	case octrace.SpanKindClient:
+		stub.SpanKind = trace.SpanKindClient
+	}
+
+	// OpenCensus uses the code 0 for unset and OK
+	if s.Code != octrace.StatusCodeOK {
+		stub.Status = sdktrace.Status{Code: codes.Error, Description: s.Message}
+	}
+
+	for _, annotation := range s.Annotations {
+		stub.Events = append(stub.Events, sdktrace.Event{
+			Name:       annotation.Message,
+			Time:       annotation.Time,
+			Attributes: attributes(annotation.Attributes),
+		})
+	}
+
+	for _, event := range s.MessageEvents {
+		eventType := "SENT"
+		if event.EventType == octrace.MessageEventTypeRecv {
+			eventType = "RECEIVED"
+		}
+		stub.Events = append(stub.Events, sdktrace.Event{
+			Name: "message",
+			Time: event.Time,
+			Attributes: []attribute.KeyValue{
+				attribute.String("message.type", eventType),
+				attribute.Int64("message.id", event.MessageID),
+				attribute.Int64("message.uncompressed_size", event.UncompressedByteSize),
+				attribute.Int64("message.compressed_size", event.CompressedByteSize),
+			},
+		})
+	}
+
+	for _, link := range s.Links {
+		stub.Links = append(stub.Links, sdktrace.Link{
+			SpanContext: spanContext(octrace.SpanContext{TraceID: link.TraceID, SpanID: link.SpanID}, true),
+			Attributes:  attributes(link.Attributes),
+		})
+	}
+
+	return stub.Snapshot()
+}
+
+// spanContext converts an OpenCensus span context, the trace state is kept
+func spanContext(sc octrace.SpanContext, remote bool) trace.SpanContext {
+	config := trace.SpanContextConfig{
+		TraceID:    trace.TraceID(sc.TraceID),
+		SpanID:     trace.SpanID(sc.SpanID),
+		TraceFlags: trace.TraceFlags(sc.TraceOptions),
+		Remote:     remote,
+	}
+
+	if sc.Tracestate != nil {
+		entries := make([]string, 0, len(sc.Tracestate.Entries()))
+		for _, entry := range sc.Tracestate.Entries() {
+			entries = append(entries, entry.Key+"="+entry.Value)
+		}
+		config.TraceState, _ = trace.ParseTraceState(strings.Join(entries, ","))
+	}
+
+	return trace.NewSpanContext(config)
+}
+
+func attributes(values map[string]interface{}) []attribute.KeyValue {
+	result := make([]attribute.KeyValue, 0, len(values))
+	for key, value := range values {
+		switch v := value.(type) {
+		case string:
+			result = append(result, attribute.String(key, v))
+		case bool:
+			result = append(result, attribute.Bool(key, v))
+		case int64:
+			result = append(result, attribute.Int64(key, v))
+		case float64:
+			result = append(result, attribute.Float64(key, v))
+		default:
+			result = append(result, attribute.String(key, fmt.Sprint(v)))
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,83 @@
+package opentelemetry
+
+import (
+	"context"
+
+	"flamingo.me/dingo"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/opencensus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Module binds a trace.TracerProvider of the OpenTelemetry SDK, which exports with OTLP/HTTP.
+// The OpenCensus spans of the framework are bridged into it.
+type Module struct {
+	tracing *Tracing
+	bridge  bool
+}
+
+var (
+	_ flamingo.Starter = new(Module)
+	_ flamingo.Stopper = new(Module)
+)
+
+// Inject dependencies
+func (m *Module) Inject(tracing *Tracing, cfg *struct {
+	Bridge bool `inject:"config:flamingo.opentelemetry.bridge,optional"`
+}) *Module {
+	m.tracing = tracing
+	if cfg != nil {
+		m.bridge = cfg.Bridge
+	}
+
+	return m
+}
+
+// Configure DI
+func (m *Module) Configure(injector *dingo.Injector) {
+	injector.Bind(new(Tracing)).ToInstance(m.tracing)
+	injector.Bind(new(trace.TracerProvider)).ToProvider(func(t *Tracing) trace.TracerProvider {
+		return t.TracerProvider()
+	})
+
+	if m.bridge {
+		opencensus.BindSpanExporter(injector).To(new(Bridge))
+	}
+}
+
+// Start the export of the spans
+func (m *Module) Start(ctx context.Context) error {
+	return m.tracing.Start(ctx)
+}
+
+// Stop the export, the buffered spans are exported first
+func (m *Module) Stop(ctx context.Context) error {
+	return m.tracing.Stop(ctx)
+}
+
+// CueConfig defines the opentelemetry config scheme
+func (m *Module) CueConfig() string {
+	// language=cue
+	return `
+flamingo: opentelemetry: {
+	serviceName: string | *"flamingo"
+	bridge: bool | *true
+	otlp: {
+		endpoint: string | *"http://localhost:4318/v1/traces"
+		headers: {[string]: string}
+		timeout: string | *"10s"
+		batchSize: number | *512
+		maxQueueSize: number | *2048
+		interval: string | *"5s"
+	}
+	sampler: probability: number & >=0 & <=1 | *1
+}
+`
+}
+
+// Depends on the opencensus module, which samples and exports the bridged spans
+func (m *Module) Depends() []dingo.Module {
+	return []dingo.Module{
+		new(opencensus.Module),
+	}
+}
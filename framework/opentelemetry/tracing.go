@@ -0,0 +1,186 @@
+package opentelemetry
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	octrace "go.opencensus.io/trace"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	// Tracing owns the OpenTelemetry SDK of an application. The tracer provider can be used right away,
+	// the spans are exported with OTLP/HTTP between Start and Stop.
+	Tracing struct {
+		mu        sync.Mutex
+		logger    flamingo.Logger
+		config    tracingConfig
+		resource  *resource.Resource
+		provider  *sdktrace.TracerProvider
+		processor sdktrace.SpanProcessor
+		// newExporter is replaced in tests
+		newExporter func(ctx context.Context) (sdktrace.SpanExporter, error)
+	}
+
+	tracingConfig struct {
+		ServiceName  string     `inject:"config:flamingo.opentelemetry.serviceName,optional"`
+		Endpoint     string     `inject:"config:flamingo.opentelemetry.otlp.endpoint,optional"`
+		Headers      config.Map `inject:"config:flamingo.opentelemetry.otlp.headers,optional"`
+		Timeout      string     `inject:"config:flamingo.opentelemetry.otlp.timeout,optional"`
+		MaxQueueSize float64    `inject:"config:flamingo.opentelemetry.otlp.maxQueueSize,optional"`
+		BatchSize    float64    `inject:"config:flamingo.opentelemetry.otlp.batchSize,optional"`
+		Interval     string     `inject:"config:flamingo.opentelemetry.otlp.interval,optional"`
+		Probability  float64    `inject:"config:flamingo.opentelemetry.sampler.probability,optional"`
+	}
+
+	// tracerProvider starts the spans of its tracers below the OpenCensus span of the context
+	tracerProvider struct {
+		trace.TracerProvider
+	}
+
+	tracer struct {
+		trace.Tracer
+	}
+)
+
+// Inject configuration, the tracer provider is created without exporter
+func (t *Tracing) Inject(logger flamingo.Logger, cfg *tracingConfig) *Tracing {
+	t.logger = logger.WithField(flamingo.LogKeyModule, "opentelemetry").WithField(flamingo.LogKeyCategory, "tracing")
+	t.config = tracingConfig{ServiceName: "flamingo", Endpoint: "http://localhost:4318/v1/traces", Probability: 1}
+	if cfg != nil {
+		t.config = *cfg
+	}
+
+	t.resource = resource.NewSchemaless(attribute.String("service.name", t.config.ServiceName))
+	t.provider = sdktrace.NewTracerProvider(
+		sdktrace.WithResource(t.resource),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(t.config.Probability))),
+	)
+	t.newExporter = t.otlpExporter
+
+	return t
+}
+
+// TracerProvider of the application, spans of its tracers are children of the OpenCensus span of the context,
+// if there is no OpenTelemetry span in the context
+func (t *Tracing) TracerProvider() trace.TracerProvider {
+	return tracerProvider{TracerProvider: t.provider}
+}
+
+// Start the export of the spans
+func (t *Tracing) Start(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.processor != nil {
+		return nil
+	}
+
+	exporter, err := t.newExporter(ctx)
+	if err != nil {
+		return fmt.Errorf("opentelemetry: %w", err)
+	}
+
+	var options []sdktrace.BatchSpanProcessorOption
+	if t.config.MaxQueueSize > 0 {
+		options = append(options, sdktrace.WithMaxQueueSize(int(t.config.MaxQueueSize)))
+	}
+	if t.config.BatchSize > 0 {
+		options = append(options, sdktrace.WithMaxExportBatchSize(int(t.config.BatchSize)))
+	}
+	if interval, err := duration(t.config.Interval); err != nil {
+		return fmt.Errorf("opentelemetry: interval: %w", err)
+	} else if interval > 0 {
+		options = append(options, sdktrace.WithBatchTimeout(interval))
+	}
+
+	t.processor = sdktrace.NewBatchSpanProcessor(exporter, options...)
+	t.provider.RegisterSpanProcessor(t.processor)
+
+	return nil
+}
+
+// Stop the export, the buffered spans are exported first
+func (t *Tracing) Stop(ctx context.Context) error {
+	t.mu.Lock()
+	processor := t.processor
+	t.processor = nil
+	t.mu.Unlock()
+
+	if processor == nil {
+		return nil
+	}
+
+	t.provider.UnregisterSpanProcessor(processor)
+	if err := processor.Shutdown(ctx); err != nil {
+		return fmt.Errorf("opentelemetry: %w", err)
+	}
+
+	return nil
+}
+
+// onEnd passes a finished span to the export, spans are dropped if the export is not started
+func (t *Tracing) onEnd(span sdktrace.ReadOnlySpan) {
+	t.mu.Lock()
+	processor := t.processor
+	t.mu.Unlock()
+
+	if processor != nil {
+		processor.OnEnd(span)
+	}
+}
+
+func (t *Tracing) otlpExporter(ctx context.Context) (sdktrace.SpanExporter, error) {
+	options := []otlptracehttp.Option{otlptracehttp.WithEndpointURL(t.config.Endpoint)}
+
+	if len(t.config.Headers) > 0 {
+		headers := make(map[string]string, len(t.config.Headers))
+		if err := t.config.Headers.MapInto(&headers); err != nil {
+			return nil, fmt.Errorf("headers: %w", err)
+		}
+		options = append(options, otlptracehttp.WithHeaders(headers))
+	}
+
+	timeout, err := duration(t.config.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("timeout: %w", err)
+	}
+	if timeout > 0 {
+		options = append(options, otlptracehttp.WithTimeout(timeout))
+	}
+
+	return otlptracehttp.New(ctx, options...)
+}
+
+// Tracer which links its spans to OpenCensus spans
+func (p tracerProvider) Tracer(name string, options ...trace.TracerOption) trace.Tracer {
+	return tracer{Tracer: p.TracerProvider.Tracer(name, options...)}
+}
+
+// Start a span, the OpenCensus span of the context is the parent if there is no OpenTelemetry span
+func (t tracer) Start(ctx context.Context, name string, options ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		if span := octrace.FromContext(ctx); span != nil {
+			ctx = trace.ContextWithSpanContext(ctx, spanContext(span.SpanContext(), false))
+		}
+	}
+
+	return t.Tracer.Start(ctx, name, options...)
+}
+
+// duration parses a configured duration, "" is no duration
+func duration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	return time.ParseDuration(s)
+}
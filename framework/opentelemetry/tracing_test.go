@@ -0,0 +1,146 @@
+package opentelemetry
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	octrace "go.opencensus.io/trace"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/opencensus"
+)
+
+// memoryExporter keeps the spans on shutdown
+type memoryExporter struct {
+	*tracetest.InMemoryExporter
+}
+
+func (memoryExporter) Shutdown(context.Context) error { return nil }
+
+func testTracing(t *testing.T, endpoint string) *Tracing {
+	t.Helper()
+
+	return new(Tracing).Inject(flamingo.NullLogger{}, &tracingConfig{
+		ServiceName: "test",
+		Endpoint:    endpoint,
+		Timeout:     "5s",
+		Probability: 1,
+	})
+}
+
+// memoryTracing exports to an in-memory exporter
+func memoryTracing(t *testing.T) (*Tracing, *tracetest.InMemoryExporter) {
+	t.Helper()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tracing := testTracing(t, "")
+	tracing.newExporter = func(context.Context) (sdktrace.SpanExporter, error) { return memoryExporter{exporter}, nil }
+	require.NoError(t, tracing.Start(context.Background()))
+
+	return tracing, exporter
+}
+
+func TestBridge(t *testing.T) {
+	tracing, exporter := memoryTracing(t)
+
+	bridge := new(Bridge).Inject(tracing)
+	octrace.RegisterExporter(bridge)
+	defer octrace.UnregisterExporter(bridge)
+
+	ctx, parent := octrace.StartSpan(context.Background(), "parent", octrace.WithSampler(octrace.AlwaysSample()), octrace.WithSpanKind(octrace.SpanKindServer))
+	_, child := octrace.StartSpan(ctx, "child")
+	child.AddAttributes(octrace.StringAttribute("key", "value"), octrace.Int64Attribute("count", 2))
+	child.Annotate(nil, "annotation")
+	child.SetStatus(octrace.Status{Code: octrace.StatusCodeInternal, Message: "failed"})
+	child.End()
+	parent.End()
+
+	require.NoError(t, tracing.Stop(context.Background()))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+
+	assert.Equal(t, "child", spans[0].Name)
+	assert.Equal(t, trace.TraceID(parent.SpanContext().TraceID), spans[0].SpanContext.TraceID())
+	assert.Equal(t, trace.SpanID(child.SpanContext().SpanID), spans[0].SpanContext.SpanID())
+	assert.Equal(t, trace.SpanID(parent.SpanContext().SpanID), spans[0].Parent.SpanID())
+	assert.Equal(t, trace.SpanKindInternal, spans[0].SpanKind)
+	assert.ElementsMatch(t, []attribute.KeyValue{attribute.String("key", "value"), attribute.Int64("count", 2)}, spans[0].Attributes)
+	assert.Equal(t, "annotation", spans[0].Events[0].Name)
+	assert.Equal(t, sdktrace.Status{Code: codes.Error, Description: "failed"}, spans[0].Status)
+	assert.Equal(t, bridgeScope, spans[0].InstrumentationLibrary.Name)
+	assert.Contains(t, spans[0].Resource.Attributes(), attribute.String("service.name", "test"))
+
+	assert.Equal(t, "parent", spans[1].Name)
+	assert.False(t, spans[1].Parent.IsValid())
+	assert.Equal(t, trace.SpanKindServer, spans[1].SpanKind)
+	assert.True(t, spans[1].SpanContext.IsSampled())
+}
+
+func TestBridge_NotStarted(t *testing.T) {
+	tracing, exporter := memoryTracing(t)
+	require.NoError(t, tracing.Stop(context.Background()))
+
+	_, span := octrace.StartSpan(context.Background(), "span", octrace.WithSampler(octrace.AlwaysSample()))
+	span.End()
+	new(Bridge).Inject(tracing).ExportSpan(&octrace.SpanData{SpanContext: span.SpanContext(), Name: "span"})
+
+	assert.Empty(t, exporter.GetSpans(), "spans are dropped after Stop")
+}
+
+func TestTracing_TracerProvider(t *testing.T) {
+	tracing, exporter := memoryTracing(t)
+
+	ctx, ocSpan := octrace.StartSpan(context.Background(), "opencensus", octrace.WithSampler(octrace.AlwaysSample()))
+	ctx, span := tracing.TracerProvider().Tracer("test").Start(ctx, "opentelemetry")
+	_, child := tracing.TracerProvider().Tracer("test").Start(ctx, "child")
+	child.End()
+	span.End()
+	ocSpan.End()
+
+	require.NoError(t, tracing.Stop(context.Background()))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "child", spans[0].Name)
+	assert.Equal(t, span.SpanContext().SpanID(), spans[0].Parent.SpanID())
+	assert.Equal(t, "opentelemetry", spans[1].Name)
+	assert.Equal(t, trace.TraceID(ocSpan.SpanContext().TraceID), spans[1].SpanContext.TraceID(), "the span continues the OpenCensus trace")
+	assert.Equal(t, trace.SpanID(ocSpan.SpanContext().SpanID), spans[1].Parent.SpanID())
+}
+
+func TestTracing_OTLP(t *testing.T) {
+	collector := opencensus.NewCollector()
+	server := httptest.NewServer(collector)
+	defer server.Close()
+
+	tracing := testTracing(t, server.URL+"/v1/traces")
+	require.NoError(t, tracing.Start(context.Background()))
+
+	bridge := new(Bridge).Inject(tracing)
+	octrace.RegisterExporter(bridge)
+	defer octrace.UnregisterExporter(bridge)
+
+	ctx, span := octrace.StartSpan(context.Background(), "bridged", octrace.WithSampler(octrace.AlwaysSample()))
+	_, native := tracing.TracerProvider().Tracer("test").Start(ctx, "native")
+	native.End()
+	span.End()
+
+	require.NoError(t, tracing.Stop(context.Background()))
+
+	spans := collector.Spans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "native", spans[0].Name)
+	assert.Equal(t, "bridged", spans[1].Name)
+	assert.Equal(t, "test", spans[1].ServiceName)
+	assert.Equal(t, span.SpanContext().TraceID.String(), spans[0].TraceID)
+	assert.Equal(t, span.SpanContext().SpanID.String(), spans[0].ParentSpanID)
+}
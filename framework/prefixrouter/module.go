@@ -14,7 +14,6 @@ import (
 	"flamingo.me/flamingo/v3/framework/opencensus"
 	"flamingo.me/flamingo/v3/framework/web"
 	"github.com/spf13/cobra"
-	"go.opencensus.io/plugin/ochttp"
 )
 
 // Module for core/prefix_router
@@ -71,14 +70,14 @@ type serveCmdConfig struct {
 	FallbackHandlers []OptionalHandler `inject:"fallback,optional"`        // Optional Register a FallbackHandlers which is passed to the FrontendRouter
 }
 
-func (m *Module) serveCmd(area *config.Area, defaultmux *http.ServeMux, configuredURLPrefixSampler *opencensus.ConfiguredURLPrefixSampler, config *serveCmdConfig) *cobra.Command {
+func (m *Module) serveCmd(area *config.Area, defaultmux *http.ServeMux, configuredURLPrefixSampler *opencensus.ConfiguredURLPrefixSampler, propagation *opencensus.Propagation, config *serveCmdConfig) *cobra.Command {
 	var addr string
 
 	cmd := &cobra.Command{
 		Use:     "serve",
 		Short:   "run the prefix router",
 		Aliases: []string{"server"},
-		Run:     m.serve(area, defaultmux, &addr, configuredURLPrefixSampler, config.PrimaryHandlers, config.FallbackHandlers),
+		Run:     m.serve(area, defaultmux, &addr, configuredURLPrefixSampler, propagation, config.PrimaryHandlers, config.FallbackHandlers),
 	}
 
 	cmd.Flags().StringVarP(&addr, "addr", "a", ":3210", "addr on which flamingo runs")
@@ -92,6 +91,7 @@ func (m *Module) serve(
 	defaultRouter *http.ServeMux,
 	addr *string,
 	configuredURLPrefixSampler *opencensus.ConfiguredURLPrefixSampler,
+	propagation *opencensus.Propagation,
 	primaryHandlers []OptionalHandler,
 	fallbackHandlers []OptionalHandler,
 ) func(cmd *cobra.Command, args []string) {
@@ -135,35 +135,16 @@ func (m *Module) serve(
 			frontRouter.Add(prefix, routerHandler{area: area.Name, handler: areaRouter.Handler()})
 		}
 
-		whitelist := make([]string, 0, len(configuredURLPrefixSampler.Whitelist)*len(frontRouter.router)+1)
-		blacklist := make([]string, 0, len(configuredURLPrefixSampler.Blacklist)*len(frontRouter.router)+1)
-
-		// default routes
-		for _, p := range configuredURLPrefixSampler.Whitelist {
-			whitelist = append(whitelist, p.(string))
-		}
-		for _, p := range configuredURLPrefixSampler.Blacklist {
-			blacklist = append(blacklist, p.(string))
-		}
-
-		// prefixed routes
+		// the sampling rules apply to the routes of the areas as well
+		prefixes := make([]string, 0, len(frontRouter.router))
 		for k := range frontRouter.router {
-			for _, p := range configuredURLPrefixSampler.Whitelist {
-				whitelist = append(whitelist, k+p.(string))
-			}
-			for _, p := range configuredURLPrefixSampler.Blacklist {
-				blacklist = append(blacklist, k+p.(string))
-			}
+			prefixes = append(prefixes, k)
 		}
 
 		m.logger.WithField("category", "prefixrouter").Info("Starting HTTP Server (Prefixrouter) at ", *addr, ".....")
 		m.server = &http.Server{
-			Addr: *addr,
-			Handler: &ochttp.Handler{
-				IsPublicEndpoint: true,
-				Handler:          frontRouter,
-				GetStartOptions:  opencensus.URLPrefixSampler(whitelist, blacklist, configuredURLPrefixSampler.AllowParentTrace),
-			},
+			Addr:    *addr,
+			Handler: propagation.Handler(frontRouter, configuredURLPrefixSampler.GetStartOptions(prefixes...)),
 		}
 
 		e := m.listenAndServe()
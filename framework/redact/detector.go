@@ -0,0 +1,85 @@
+package redact
+
+import (
+	"regexp"
+	"sort"
+)
+
+// Detector finds sensitive values in texts
+type Detector struct {
+	Name string
+	// Pattern matches the sensitive values, if it has a group named "value" only the group is replaced
+	Pattern *regexp.Regexp
+	// Valid filters the matches, e.g. with a checksum, nil accepts every match
+	Valid func(match string) bool
+}
+
+var detectors = map[string]Detector{
+	// JSON Web Tokens, the signature is empty for unsecured tokens
+	"jwt": {
+		Name:    "jwt",
+		Pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]*`),
+	},
+	// credentials of Authorization headers, the scheme is kept
+	"bearer": {
+		Name:    "bearer",
+		Pattern: regexp.MustCompile(`(?i)\b(?:bearer|basic)\s+(?P<value>[A-Za-z0-9._~+/-]+=*)`),
+	},
+	// payment card numbers with 13 to 19 digits, optionally grouped by spaces or dashes
+	"card": {
+		Name:    "card",
+		Pattern: regexp.MustCompile(`\b[2-6](?:[ -]?\d){12,18}\b`),
+		Valid:   luhn,
+	},
+	"email": {
+		Name:    "email",
+		Pattern: regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`),
+	},
+}
+
+// DetectorNames returns the names of the builtin detectors
+func DetectorNames() []string {
+	names := make([]string, 0, len(detectors))
+	for name := range detectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func (d Detector) redact(s string, replacement string) string {
+	group := 0
+	for i, name := range d.Pattern.SubexpNames() {
+		if name == "value" {
+			group = i
+		}
+	}
+
+	return replaceGroup(s, d.Pattern, group, replacement, func(groups []string) bool {
+		return d.Valid == nil || d.Valid(groups[0])
+	})
+}
+
+// luhn validates the check digit of a card number, spaces and dashes are ignored
+func luhn(number string) bool {
+	sum, digits := 0, 0
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+
+		d := int(c - '0')
+		if digits%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		digits++
+	}
+
+	return digits > 0 && sum%10 == 0
+}
@@ -0,0 +1,27 @@
+package redact
+
+import (
+	"flamingo.me/dingo"
+)
+
+// Module binds the configured Redactor
+type Module struct{}
+
+// Configure DI
+func (*Module) Configure(injector *dingo.Injector) {
+	injector.Bind(new(Redactor)).In(dingo.Singleton)
+}
+
+// CueConfig schema and defaults
+func (*Module) CueConfig() string {
+	// language=cue
+	return `
+flamingo: redact: {
+	enabled: bool | *true
+	keys: [...string] | *["(?i)passw(or)?d", "(?i)secret", "(?i)token", "(?i)authorization", "(?i)cookie", "(?i)api[-_]?key"]
+	detectors: [...("jwt" | "bearer" | "card" | "email")] | *["jwt", "bearer"]
+	values: [...string]
+	replacement: string | *"[REDACTED]"
+}
+`
+}
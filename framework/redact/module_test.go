@@ -0,0 +1,14 @@
+package redact_test
+
+import (
+	"testing"
+
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/redact"
+)
+
+func TestModule_Configure(t *testing.T) {
+	if err := config.TryModules(nil, new(redact.Module)); err != nil {
+		t.Error(err)
+	}
+}
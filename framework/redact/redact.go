@@ -0,0 +1,293 @@
+// Package redact removes sensitive fields and values, such as passwords and tokens, from logs and debug output.
+package redact
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+
+	"flamingo.me/flamingo/v3/framework/config"
+)
+
+type (
+	// Redactor replaces the values of sensitive keys and values found by detectors.
+	// The zero value and a nil Redactor do not redact anything.
+	Redactor struct {
+		keys        []*regexp.Regexp
+		detectors   []Detector
+		replacement string
+	}
+
+	// Options of a Redactor
+	Options struct {
+		// Keys are regular expressions of sensitive keys, e.g. field names, header names and query parameters
+		Keys []string
+		// Detectors are the names of the builtin detectors, see DetectorNames
+		Detectors []string
+		// Values are additional regular expressions of sensitive values
+		Values []string
+		// Replacement of redacted values, defaults to DefaultReplacement
+		Replacement string
+	}
+)
+
+// DefaultReplacement replaces redacted values
+const DefaultReplacement = "[REDACTED]"
+
+var (
+	// DefaultOptions are the defaults of the flamingo.redact config, they are used by Default
+	DefaultOptions = Options{
+		Keys:      []string{"(?i)passw(or)?d", "(?i)secret", "(?i)token", "(?i)authorization", "(?i)cookie", "(?i)api[-_]?key"},
+		Detectors: []string{"jwt", "bearer"},
+	}
+
+	defaultRedactor     *Redactor
+	defaultRedactorOnce sync.Once
+
+	// keyValuePatterns find keys with values in texts, the first group is the key and the second the value
+	keyValuePatterns = []*regexp.Regexp{
+		// query strings, form bodies and key=value log messages
+		regexp.MustCompile(`([A-Za-z0-9_.\[\]-]+)=([^&\s"',;]*)`),
+		// header lines of request and response dumps
+		regexp.MustCompile(`(?m)^([A-Za-z0-9-]+):[ \t]*(.*?)\r?$`),
+		// string values of JSON objects
+		regexp.MustCompile(`"([^"\\]+)"\s*:\s*"((?:[^"\\]|\\.)*)"`),
+	}
+)
+
+// New creates a Redactor
+func New(options Options) (*Redactor, error) {
+	r := &Redactor{
+		replacement: options.Replacement,
+	}
+	if r.replacement == "" {
+		r.replacement = DefaultReplacement
+	}
+
+	for _, key := range options.Keys {
+		re, err := regexp.Compile(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key pattern %q: %w", key, err)
+		}
+		r.keys = append(r.keys, re)
+	}
+
+	for _, name := range options.Detectors {
+		detector, ok := detectors[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown detector %q, expected one of %s", name, strings.Join(DetectorNames(), ", "))
+		}
+		r.detectors = append(r.detectors, detector)
+	}
+
+	for i, value := range options.Values {
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value pattern %q: %w", value, err)
+		}
+		r.detectors = append(r.detectors, Detector{Name: fmt.Sprintf("value%d", i), Pattern: re})
+	}
+
+	return r, nil
+}
+
+// Default returns a shared Redactor with the DefaultOptions, e.g. for loggers which are created without DI
+func Default() *Redactor {
+	defaultRedactorOnce.Do(func() {
+		redactor, err := New(DefaultOptions)
+		if err != nil {
+			panic(err)
+		}
+		defaultRedactor = redactor
+	})
+
+	return defaultRedactor
+}
+
+// Inject the configuration of flamingo.redact, an invalid configuration panics
+func (r *Redactor) Inject(cfg *struct {
+	Enabled     bool         `inject:"config:flamingo.redact.enabled,optional"`
+	Keys        config.Slice `inject:"config:flamingo.redact.keys,optional"`
+	Detectors   config.Slice `inject:"config:flamingo.redact.detectors,optional"`
+	Values      config.Slice `inject:"config:flamingo.redact.values,optional"`
+	Replacement string       `inject:"config:flamingo.redact.replacement,optional"`
+}) *Redactor {
+	if cfg == nil || !cfg.Enabled {
+		return r
+	}
+
+	options := Options{Replacement: cfg.Replacement}
+	for _, list := range []struct {
+		slice config.Slice
+		into  *[]string
+	}{
+		{cfg.Keys, &options.Keys},
+		{cfg.Detectors, &options.Detectors},
+		{cfg.Values, &options.Values},
+	} {
+		if err := list.slice.MapInto(list.into); err != nil {
+			panic(fmt.Errorf("invalid flamingo.redact config: %w", err))
+		}
+	}
+
+	redactor, err := New(options)
+	if err != nil {
+		panic(fmt.Errorf("invalid flamingo.redact config: %w", err))
+	}
+	*r = *redactor
+
+	return r
+}
+
+func (r *Redactor) enabled() bool {
+	return r != nil && (len(r.keys) > 0 || len(r.detectors) > 0)
+}
+
+// Key checks if the values of the key are sensitive
+func (r *Redactor) Key(key string) bool {
+	if r == nil {
+		return false
+	}
+
+	for _, re := range r.keys {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String redacts a text, e.g. a log message or a request dump. Values of sensitive keys are replaced in
+// key=value pairs, header lines and JSON objects, afterwards all values found by the detectors are replaced.
+func (r *Redactor) String(s string) string {
+	if !r.enabled() || s == "" {
+		return s
+	}
+
+	if len(r.keys) > 0 {
+		for _, re := range keyValuePatterns {
+			s = replaceGroup(s, re, 2, r.replacement, func(groups []string) bool {
+				return groups[2] != "" && r.Key(groups[1])
+			})
+		}
+	}
+
+	for _, detector := range r.detectors {
+		s = detector.redact(s, r.replacement)
+	}
+
+	return s
+}
+
+// Bytes redacts a text like String
+func (r *Redactor) Bytes(b []byte) []byte {
+	if !r.enabled() {
+		return b
+	}
+
+	return []byte(r.String(string(b)))
+}
+
+// Value redacts a field value: the value of a sensitive key is replaced completely, strings, byte slices,
+// errors, fmt.Stringer and maps or slices of strings are redacted like String.
+// Errors and fmt.Stringer are only converted to a string if they contain sensitive values.
+func (r *Redactor) Value(key string, value interface{}) interface{} {
+	if !r.enabled() {
+		return value
+	}
+
+	if r.Key(key) {
+		return r.replacement
+	}
+
+	switch v := value.(type) {
+	case string:
+		return r.String(v)
+	case []byte:
+		return r.Bytes(v)
+	case error:
+		if s := v.Error(); r.String(s) != s {
+			return r.String(s)
+		}
+	case fmt.Stringer:
+		if s := v.String(); r.String(s) != s {
+			return r.String(s)
+		}
+	case []string:
+		redacted := make([]string, len(v))
+		for i := range v {
+			redacted[i] = r.String(v[i])
+		}
+		return redacted
+	case map[string]string:
+		redacted := make(map[string]string, len(v))
+		for k, value := range v {
+			redacted[k] = r.Value(k, value).(string)
+		}
+		return redacted
+	case http.Header:
+		return r.header(v)
+	case map[string][]string:
+		return map[string][]string(r.header(v))
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for k, value := range v {
+			redacted[k] = r.Value(k, value)
+		}
+		return redacted
+	}
+
+	return value
+}
+
+func (r *Redactor) header(header http.Header) http.Header {
+	redacted := make(http.Header, len(header))
+	for name, values := range header {
+		redacted[name] = make([]string, len(values))
+		for i, value := range values {
+			if r.Key(name) {
+				value = r.replacement
+			}
+			redacted[name][i] = r.String(value)
+		}
+	}
+
+	return redacted
+}
+
+// replaceGroup replaces the group of every match which is accepted, accept gets the match and its groups
+func replaceGroup(s string, re *regexp.Regexp, group int, replacement string, accept func(groups []string) bool) string {
+	matches := re.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, match := range matches {
+		start, end := match[2*group], match[2*group+1]
+		if start < 0 {
+			continue
+		}
+
+		groups := make([]string, len(match)/2)
+		for i := range groups {
+			if match[2*i] >= 0 {
+				groups[i] = s[match[2*i]:match[2*i+1]]
+			}
+		}
+		if accept != nil && !accept(groups) {
+			continue
+		}
+
+		b.WriteString(s[last:start])
+		b.WriteString(replacement)
+		last = end
+	}
+	b.WriteString(s[last:])
+
+	return b.String()
+}
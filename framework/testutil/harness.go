@@ -0,0 +1,401 @@
+package testutil
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"flamingo.me/dingo"
+	"github.com/ghodss/yaml"
+
+	flamingoapp "flamingo.me/flamingo/v3"
+	"flamingo.me/flamingo/v3/framework/cmd"
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/web"
+)
+
+type (
+	// Harness boots a flamingo application in-process for integration tests.
+	// Requests are served directly by the router of the selected area, no ports are bound.
+	Harness struct {
+		t         testing.TB
+		area      *config.Area
+		injector  *dingo.Injector
+		router    *web.Router
+		handler   http.Handler
+		jar       http.CookieJar
+		client    *http.Client
+		baseURL   *url.URL
+		templates *TemplateRecorder
+		lifecycle *cmd.Lifecycle
+		closeOnce sync.Once
+	}
+
+	// HarnessOption configures the Harness
+	HarnessOption func(*harnessConfig)
+
+	harnessConfig struct {
+		configDir       string
+		configs         []config.Map
+		childAreas      []*config.Area
+		area            string
+		overrides       []func(injector *dingo.Injector)
+		recordTemplates bool
+	}
+
+	// TemplateRecorder is a template engine which records the rendered templates instead of rendering them
+	TemplateRecorder struct {
+		mu        sync.Mutex
+		templates []RenderedTemplate
+	}
+
+	// RenderedTemplate is a template render call recorded by the TemplateRecorder
+	RenderedTemplate struct {
+		Name string
+		Data interface{}
+	}
+
+	handlerTransport struct {
+		handler http.Handler
+	}
+)
+
+// HarnessBaseURL is the URL the test client sends requests to
+const HarnessBaseURL = "http://flamingo.test"
+
+var _ flamingo.TemplateEngine = new(TemplateRecorder)
+
+// WithConfig adds configuration, e.g. config.Map{"flamingo.session.backend": "memory"}
+func WithConfig(cfg config.Map) HarnessOption {
+	return func(c *harnessConfig) {
+		c.configs = append(c.configs, cfg)
+	}
+}
+
+// WithConfigDir loads the configuration from a directory, by default the "config" directory of the tested package is used
+func WithConfigDir(dir string) HarnessOption {
+	return func(c *harnessConfig) {
+		c.configDir = dir
+	}
+}
+
+// WithChildAreas adds child areas to the root area
+func WithChildAreas(areas ...*config.Area) HarnessOption {
+	return func(c *harnessConfig) {
+		c.childAreas = areas
+	}
+}
+
+// WithArea selects the area which serves the requests, e.g. "root/shop-de"
+func WithArea(name string) HarnessOption {
+	return func(c *harnessConfig) {
+		c.area = name
+	}
+}
+
+// WithOverride overrides bindings, e.g. to bind fake implementations:
+//
+//	testutil.WithOverride(func(injector *dingo.Injector) {
+//		injector.Override(new(domain.ProductService), "").To(fakeProductService{})
+//	})
+func WithOverride(override func(injector *dingo.Injector)) HarnessOption {
+	return func(c *harnessConfig) {
+		c.overrides = append(c.overrides, override)
+	}
+}
+
+// KeepTemplateEngine uses the configured template engine instead of the TemplateRecorder.
+// By default the TemplateRecorder overrides the template engine binding, e.g. of the gotemplate module.
+func KeepTemplateEngine() HarnessOption {
+	return func(c *harnessConfig) {
+		c.recordTemplates = false
+	}
+}
+
+// NewHarness creates the application with the given modules and initializes the router of the area.
+// The test fails if the application can not be created. Call Close when the test is done.
+func NewHarness(t testing.TB, modules []dingo.Module, options ...HarnessOption) *Harness {
+	t.Helper()
+
+	cfg := &harnessConfig{
+		configDir:       "config",
+		area:            "root",
+		recordTemplates: true,
+	}
+	for _, option := range options {
+		option(cfg)
+	}
+
+	h := &Harness{
+		t:         t,
+		templates: new(TemplateRecorder),
+	}
+
+	// the arguments of the test binary must not be parsed by the application
+	args := []string{"--flamingo-context", cfg.area}
+	for _, c := range cfg.configs {
+		b, err := yaml.Marshal(c)
+		if err != nil {
+			t.Fatalf("testutil: invalid config: %v", err)
+		}
+		args = append(args, "--flamingo-config", string(b))
+	}
+
+	modules = append(modules, dingo.ModuleFunc(func(injector *dingo.Injector) {
+		if cfg.recordTemplates {
+			injector.Override(new(flamingo.TemplateEngine), "").ToInstance(h.templates)
+		}
+		for _, override := range cfg.overrides {
+			override(injector)
+		}
+	}))
+
+	app, err := flamingoapp.NewApplication(
+		modules,
+		flamingoapp.WithArgs(args...),
+		flamingoapp.ConfigDir(cfg.configDir),
+		flamingoapp.ChildAreas(cfg.childAreas...),
+	)
+	if err != nil {
+		t.Fatalf("testutil: %v", err)
+	}
+
+	h.area = app.ConfigArea()
+	h.injector, err = h.area.GetInitializedInjector()
+	if err != nil {
+		t.Fatalf("testutil: %v", err)
+	}
+
+	i, err := h.injector.GetInstance(web.Router{})
+	if err != nil {
+		t.Fatalf("testutil: %v", err)
+	}
+	h.router = i.(*web.Router)
+	h.handler = h.router.Handler()
+
+	h.baseURL, _ = url.Parse(HarnessBaseURL)
+	h.jar, _ = cookiejar.New(nil)
+	h.client = &http.Client{
+		Transport: &handlerTransport{handler: h.handler},
+		Jar:       h.jar,
+		// redirects are not followed, so tests can assert them
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	i, err = h.injector.GetInstance(new(cmd.Lifecycle))
+	if err != nil {
+		t.Fatalf("testutil: %v", err)
+	}
+	h.lifecycle = i.(*cmd.Lifecycle)
+	if err := h.lifecycle.Start(context.Background(), h.area); err != nil {
+		t.Fatalf("testutil: %v", err)
+	}
+
+	i, err = h.injector.GetInstance(new(flamingo.EventRouter))
+	if err != nil {
+		t.Fatalf("testutil: %v", err)
+	}
+	i.(flamingo.EventRouter).Dispatch(context.Background(), new(flamingo.StartupEvent))
+
+	return h
+}
+
+// Close dispatches the ShutdownEvent and stops the modules, the process is not terminated
+func (h *Harness) Close() {
+	h.closeOnce.Do(func() {
+		i, err := h.injector.GetInstance(new(flamingo.EventRouter))
+		if err != nil {
+			h.t.Errorf("testutil: %v", err)
+			return
+		}
+		i.(flamingo.EventRouter).Dispatch(context.Background(), new(flamingo.ShutdownEvent))
+
+		if err := h.lifecycle.Stop(context.Background()); err != nil {
+			h.t.Errorf("testutil: %v", err)
+		}
+	})
+}
+
+// Area returns the config area serving the requests
+func (h *Harness) Area() *config.Area {
+	return h.area
+}
+
+// Injector returns the initialized injector of the area
+func (h *Harness) Injector() *dingo.Injector {
+	return h.injector
+}
+
+// GetInstance returns an instance from the injector of the area, the test fails on errors
+func (h *Harness) GetInstance(of interface{}) interface{} {
+	h.t.Helper()
+
+	i, err := h.injector.GetInstance(of)
+	if err != nil {
+		h.t.Fatalf("testutil: %v", err)
+	}
+	return i
+}
+
+// Client returns the HTTP client, which keeps cookies and does not follow redirects
+func (h *Harness) Client() *http.Client {
+	return h.client
+}
+
+// Do sends a request, relative URLs are resolved against HarnessBaseURL
+func (h *Harness) Do(req *http.Request) *http.Response {
+	h.t.Helper()
+
+	req.URL = h.baseURL.ResolveReference(req.URL)
+	req.Host = req.URL.Host
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		h.t.Fatalf("testutil: %v", err)
+	}
+
+	return resp
+}
+
+// Get sends a GET request
+func (h *Harness) Get(path string) *http.Response {
+	h.t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		h.t.Fatalf("testutil: %v", err)
+	}
+
+	return h.Do(req)
+}
+
+// PostForm sends a POST request with form values
+func (h *Harness) PostForm(path string, values url.Values) *http.Response {
+	h.t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, path, strings.NewReader(values.Encode()))
+	if err != nil {
+		h.t.Fatalf("testutil: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return h.Do(req)
+}
+
+// Body returns the body of a response
+func (h *Harness) Body(resp *http.Response) string {
+	h.t.Helper()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		h.t.Fatalf("testutil: %v", err)
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(b))
+
+	return string(b)
+}
+
+// Session loads the session of the client's session cookie
+func (h *Harness) Session() *web.Session {
+	h.t.Helper()
+
+	req := &http.Request{URL: h.baseURL, Header: make(http.Header)}
+	for _, cookie := range h.jar.Cookies(h.baseURL) {
+		req.AddCookie(cookie)
+	}
+
+	session, err := h.sessionStore().LoadByRequest(context.Background(), req)
+	if err != nil {
+		h.t.Fatalf("testutil: %v", err)
+	}
+
+	return session
+}
+
+// SaveSession stores a modified session and updates the client's session cookie
+func (h *Harness) SaveSession(session *web.Session) {
+	h.t.Helper()
+
+	header, err := h.sessionStore().Save(context.Background(), session)
+	if err != nil {
+		h.t.Fatalf("testutil: %v", err)
+	}
+
+	h.jar.SetCookies(h.baseURL, (&http.Response{Header: header}).Cookies())
+}
+
+func (h *Harness) sessionStore() *web.SessionStore {
+	return h.GetInstance(new(web.SessionStore)).(*web.SessionStore)
+}
+
+// Data calls a data controller with the client's session
+func (h *Harness) Data(handler string, params map[interface{}]interface{}) interface{} {
+	h.t.Helper()
+
+	session := h.Session()
+	ctx := web.ContextWithSession(context.Background(), session)
+	ctx = web.ContextWithRequest(ctx, web.CreateRequest(httptest.NewRequest(http.MethodGet, HarnessBaseURL, nil), session))
+
+	return h.router.Data(ctx, handler, params)
+}
+
+// Templates returns the recorded templates, it is empty with KeepTemplateEngine
+func (h *Harness) Templates() []RenderedTemplate {
+	return h.templates.Templates()
+}
+
+// LastTemplate returns the last recorded template, the test fails if no template was rendered
+func (h *Harness) LastTemplate() RenderedTemplate {
+	h.t.Helper()
+
+	templates := h.templates.Templates()
+	if len(templates) == 0 {
+		h.t.Fatal("testutil: no template rendered")
+	}
+
+	return templates[len(templates)-1]
+}
+
+// Render records the template and its data, the rendered body is the template name
+func (r *TemplateRecorder) Render(_ context.Context, name string, data interface{}) (io.Reader, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.templates = append(r.templates, RenderedTemplate{Name: name, Data: data})
+
+	return strings.NewReader(name), nil
+}
+
+// Templates returns a copy of the recorded templates
+func (r *TemplateRecorder) Templates() []RenderedTemplate {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]RenderedTemplate(nil), r.templates...)
+}
+
+// RoundTrip serves the request with the handler
+func (t *handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body == nil {
+		req.Body = http.NoBody
+	}
+
+	recorder := httptest.NewRecorder()
+	t.handler.ServeHTTP(recorder, req)
+
+	resp := recorder.Result()
+	resp.Request = req
+
+	return resp, nil
+}
@@ -0,0 +1,98 @@
+package testutil_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"flamingo.me/dingo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/testutil"
+	"flamingo.me/flamingo/v3/framework/web"
+)
+
+type (
+	greeter interface {
+		Greet() string
+	}
+
+	defaultGreeter struct{}
+	fakeGreeter    struct{}
+
+	harnessModule struct{}
+
+	harnessRoutes struct {
+		responder *web.Responder
+		greeter   greeter
+	}
+)
+
+func (defaultGreeter) Greet() string { return "hello" }
+func (fakeGreeter) Greet() string    { return "fake" }
+
+func (*harnessModule) Configure(injector *dingo.Injector) {
+	injector.Bind(new(greeter)).To(defaultGreeter{})
+	web.BindRoutes(injector, new(harnessRoutes))
+}
+
+func (r *harnessRoutes) Inject(responder *web.Responder, greeter greeter) {
+	r.responder = responder
+	r.greeter = greeter
+}
+
+func (r *harnessRoutes) Routes(registry *web.RouterRegistry) {
+	registry.MustRoute("/", "index")
+	registry.HandleGet("index", func(ctx context.Context, req *web.Request) web.Result {
+		req.Session().Store("visited", true)
+		return r.responder.Render("index", map[string]string{"greeting": r.greeter.Greet()})
+	})
+
+	registry.MustRoute("/away", "away")
+	registry.HandleGet("away", func(ctx context.Context, req *web.Request) web.Result {
+		return r.responder.URLRedirect(&url.URL{Path: "/"})
+	})
+
+	registry.HandleData("greeting", func(ctx context.Context, req *web.Request, params web.RequestParams) interface{} {
+		return r.greeter.Greet() + " " + params["name"]
+	})
+}
+
+func TestHarness(t *testing.T) {
+	h := testutil.NewHarness(t, []dingo.Module{new(harnessModule)},
+		testutil.WithConfig(config.Map{"flamingo.session.backend": "memory"}),
+		testutil.WithOverride(func(injector *dingo.Injector) {
+			injector.Override(new(greeter), "").To(fakeGreeter{})
+		}),
+	)
+	defer h.Close()
+
+	resp := h.Get("/")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "index", h.Body(resp))
+
+	tpl := h.LastTemplate()
+	assert.Equal(t, "index", tpl.Name)
+	assert.Equal(t, map[string]string{"greeting": "fake"}, tpl.Data)
+
+	visited, ok := h.Session().Load("visited")
+	require.True(t, ok, "session is kept by the cookie jar")
+	assert.Equal(t, true, visited)
+
+	resp = h.Get("/away")
+	assert.Equal(t, http.StatusSeeOther, resp.StatusCode)
+	assert.Equal(t, "/", resp.Header.Get("Location"))
+
+	assert.Equal(t, "fake flamingo", h.Data("greeting", map[interface{}]interface{}{"name": "flamingo"}))
+}
+
+func TestHarness_Repeated(t *testing.T) {
+	for i := 0; i < 2; i++ {
+		h := testutil.NewHarness(t, []dingo.Module{new(harnessModule)})
+		assert.Equal(t, http.StatusOK, h.Get("/").StatusCode)
+		h.Close()
+	}
+}
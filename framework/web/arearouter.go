@@ -0,0 +1,155 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+)
+
+type (
+	// AreaRouter resolves URLs of handlers in other config areas, e.g. links from a country area into the checkout area.
+	// The routers of the other areas are initialized lazily on first use.
+	AreaRouter struct {
+		root    *config.Area
+		logger  flamingo.Logger
+		mu      sync.Mutex
+		routers map[string]*Router
+	}
+
+	// AreaURLFunc is exported as a template function
+	AreaURLFunc struct {
+		areaRouter *AreaRouter
+	}
+)
+
+// Inject dependencies
+func (a *AreaRouter) Inject(area *config.Area, logger flamingo.Logger) *AreaRouter {
+	a.root = area
+	for a.root != nil && a.root.Parent != nil {
+		a.root = a.root.Parent
+	}
+	a.logger = logger.WithField(flamingo.LogKeyModule, "web").WithField(flamingo.LogKeyCategory, "arearouter")
+	return a
+}
+
+// Router returns the router of the named area.
+// The name is either the full name as used by the prefixrouter (e.g. "root/shop-de"), or the name of the area itself.
+func (a *AreaRouter) Router(name string) (*Router, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if router, ok := a.routers[name]; ok {
+		return router, nil
+	}
+
+	if a.root == nil {
+		return nil, fmt.Errorf("area %q not found: no config area available", name)
+	}
+
+	flat, err := a.root.Flat()
+	if err != nil {
+		return nil, err
+	}
+
+	area := findArea(flat, name)
+	if area == nil {
+		return nil, fmt.Errorf("area %q not found", name)
+	}
+
+	injector, err := area.GetInitializedInjector()
+	if err != nil {
+		return nil, fmt.Errorf("area %q: %w", name, err)
+	}
+
+	i, err := injector.GetInstance(Router{})
+	if err != nil {
+		return nil, fmt.Errorf("area %q: %w", name, err)
+	}
+	router := i.(*Router)
+	if _, err := router.registry(); err != nil {
+		return nil, fmt.Errorf("area %q: %w", name, err)
+	}
+
+	if a.routers == nil {
+		a.routers = make(map[string]*Router)
+	}
+	a.routers[name] = router
+
+	return router, nil
+}
+
+// findArea looks up an area by its full flat name, by the name relative to the root, or by the area name itself
+func findArea(flat map[string]*config.Area, name string) *config.Area {
+	if area, ok := flat[name]; ok {
+		return area
+	}
+
+	var found *config.Area
+	var foundKey string
+	for key, area := range flat {
+		if !strings.HasSuffix(key, "/"+name) && area.Name != name {
+			continue
+		}
+		// prefer the least nested area on ambiguous names
+		if found == nil || len(key) < len(foundKey) {
+			found, foundKey = area, key
+		}
+	}
+
+	return found
+}
+
+// Relative returns a root-relative URL of a handler in the named area, including the area's path prefix
+func (a *AreaRouter) Relative(area, to string, params map[string]string) (*url.URL, error) {
+	router, err := a.Router(area)
+	if err != nil {
+		return nil, err
+	}
+
+	return router.Relative(to, params)
+}
+
+// Absolute returns an absolute URL of a handler in the named area.
+// The area's host and external base URL are used, the request is only used as fallback for scheme and host.
+func (a *AreaRouter) Absolute(r *Request, area, to string, params map[string]string) (*url.URL, error) {
+	router, err := a.Router(area)
+	if err != nil {
+		return nil, err
+	}
+
+	var locale string
+	if r != nil && r.handler != nil {
+		locale = r.handler.locale
+	}
+
+	return router.AbsoluteLocalized(r, locale, to, params)
+}
+
+// Inject dependencies
+func (f *AreaURLFunc) Inject(areaRouter *AreaRouter) *AreaURLFunc {
+	f.areaRouter = areaRouter
+	return f
+}
+
+// Func returns the areaUrl func, which resolves the absolute URL of a handler in another area
+func (f *AreaURLFunc) Func(ctx context.Context) interface{} {
+	return func(area, to string, params ...string) string {
+		p := make(map[string]string, len(params)/2)
+		for i := 0; i < len(params) && len(params)%2 == 0; i += 2 {
+			p[params[i]] = params[i+1]
+		}
+
+		u, err := f.areaRouter.Absolute(RequestFromContext(ctx), area, to, p)
+		if err != nil {
+			f.areaRouter.logger.WithContext(ctx).Warn(err)
+			return ""
+		}
+
+		return u.String()
+	}
+}
@@ -0,0 +1,56 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"flamingo.me/flamingo/v3/framework/config"
+)
+
+func TestFindArea(t *testing.T) {
+	flat := map[string]*config.Area{
+		"root":                 {Name: "root"},
+		"root/shop-de":         {Name: "shop-de"},
+		"root/checkout":        {Name: "checkout"},
+		"root/shop-de/account": {Name: "account"},
+		"root/shop-at/account": {Name: "account"},
+		"root/shop-at":         {Name: "shop-at"},
+	}
+
+	assert.Equal(t, "shop-de", findArea(flat, "root/shop-de").Name)
+	assert.Equal(t, "checkout", findArea(flat, "checkout").Name)
+	assert.Equal(t, "account", findArea(flat, "shop-at/account").Name)
+	assert.Nil(t, findArea(flat, "unknown"))
+}
+
+func TestAreaRouter(t *testing.T) {
+	registry := NewRegistry()
+	registry.HandleGet("checkout.start", nil)
+	registry.MustRoute("/start/:step", "checkout.start")
+
+	areaRouter := &AreaRouter{
+		routers: map[string]*Router{
+			"checkout": {
+				base:           &url.URL{Scheme: "https", Host: "checkout.example.com", Path: "/co"},
+				routerRegistry: registry,
+			},
+		},
+	}
+
+	u, err := areaRouter.Relative("checkout", "checkout.start", map[string]string{"step": "address"})
+	require.NoError(t, err)
+	assert.Equal(t, "/co/start/address", u.String())
+
+	req := CreateRequest(httptest.NewRequest(http.MethodGet, "http://shop.example.com/de/", nil), nil)
+	u, err = areaRouter.Absolute(req, "checkout", "checkout.start", map[string]string{"step": "address"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://checkout.example.com/co/start/address", u.String())
+
+	_, err = areaRouter.Relative("unknown", "checkout.start", nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,59 @@
+package web
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ETag computes a strong entity tag for the given body
+func ETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// WeakETag computes a weak entity tag for the given body
+func WeakETag(body []byte) string {
+	return "W/" + ETag(body)
+}
+
+// NotModified checks the conditional request headers If-None-Match and If-Modified-Since (RFC 7232)
+// against the given validators and returns true if the client already holds the current representation.
+// If-Modified-Since is only evaluated if the request has no If-None-Match header.
+func NotModified(r *http.Request, etag string, lastModified *time.Time) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		if etag == "" {
+			return false
+		}
+		return etagMatches(inm, etag)
+	}
+
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && lastModified != nil {
+		t, err := http.ParseTime(ims)
+		if err != nil {
+			return false
+		}
+		return !lastModified.Truncate(time.Second).After(t)
+	}
+
+	return false
+}
+
+// etagMatches performs the weak comparison of a If-None-Match header against an entity tag
+func etagMatches(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,62 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestETag(t *testing.T) {
+	etag := ETag([]byte("body"))
+	assert.Equal(t, etag, ETag([]byte("body")))
+	assert.NotEqual(t, etag, ETag([]byte("other body")))
+	assert.Equal(t, byte('"'), etag[0])
+	assert.Equal(t, "W/"+etag, WeakETag([]byte("body")))
+}
+
+func TestNotModified(t *testing.T) {
+	lastModified := time.Date(2020, 2, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		method       string
+		header       map[string]string
+		etag         string
+		lastModified *time.Time
+		want         bool
+	}{
+		{name: "no conditional headers", etag: `"a"`, want: false},
+		{name: "matching etag", header: map[string]string{"If-None-Match": `"a"`}, etag: `"a"`, want: true},
+		{name: "matching etag in list", header: map[string]string{"If-None-Match": `"b", "a"`}, etag: `"a"`, want: true},
+		{name: "weak comparison", header: map[string]string{"If-None-Match": `W/"a"`}, etag: `"a"`, want: true},
+		{name: "wildcard", header: map[string]string{"If-None-Match": `*`}, etag: `"a"`, want: true},
+		{name: "different etag", header: map[string]string{"If-None-Match": `"b"`}, etag: `"a"`, want: false},
+		{name: "post request", method: http.MethodPost, header: map[string]string{"If-None-Match": `"a"`}, etag: `"a"`, want: false},
+		{name: "not modified since", header: map[string]string{"If-Modified-Since": lastModified.Format(http.TimeFormat)}, lastModified: &lastModified, want: true},
+		{name: "modified since", header: map[string]string{"If-Modified-Since": lastModified.Add(-time.Hour).Format(http.TimeFormat)}, lastModified: &lastModified, want: false},
+		{
+			name:         "If-None-Match takes precedence",
+			header:       map[string]string{"If-None-Match": `"b"`, "If-Modified-Since": lastModified.Format(http.TimeFormat)},
+			etag:         `"a"`,
+			lastModified: &lastModified,
+			want:         false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			r := httptest.NewRequest(method, "/", nil)
+			for k, v := range tt.header {
+				r.Header.Set(k, v)
+			}
+			assert.Equal(t, tt.want, NotModified(r, tt.etag, tt.lastModified))
+		})
+	}
+}
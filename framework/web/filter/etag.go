@@ -0,0 +1,133 @@
+package filter
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+
+	"flamingo.me/dingo"
+	"flamingo.me/flamingo/v3/framework/web"
+)
+
+type (
+	// ETagModule is a flamingo module to set up a web filter which adds ETags and answers conditional GET requests
+	ETagModule struct{}
+
+	etagFilter struct {
+		Weak bool `inject:"config:flamingo.web.filter.etag.weak,optional"`
+	}
+
+	etagResponse struct {
+		result  web.Result
+		request *http.Request
+		weak    bool
+	}
+
+	bufferedResponseWriter struct {
+		header http.Header
+		status int
+		body   bytes.Buffer
+	}
+)
+
+// Configure the Module
+func (m *ETagModule) Configure(injector *dingo.Injector) {
+	injector.BindMulti((*web.Filter)(nil)).To(etagFilter{})
+}
+
+// CueConfig for the etag filter
+func (m *ETagModule) CueConfig() string {
+	return `flamingo: web: filter: etag: weak: bool | *false`
+}
+
+// Filter wraps GET and HEAD responses to validate them against the conditional request headers
+func (f *etagFilter) Filter(ctx context.Context, r *web.Request, w http.ResponseWriter, chain *web.FilterChain) web.Result {
+	response := chain.Next(ctx, r, w)
+
+	if r.Request().Method != http.MethodGet && r.Request().Method != http.MethodHead {
+		return response
+	}
+
+	return &etagResponse{result: response, request: r.Request(), weak: f.Weak}
+}
+
+// validators returns the controller supplied cache directive, if any
+func validators(result web.Result) *web.CacheDirective {
+	switch result := result.(type) {
+	case *web.RenderResponse:
+		return result.CacheDirective
+	case *web.DataResponse:
+		return result.CacheDirective
+	case *web.Response:
+		return result.CacheDirective
+	}
+
+	return nil
+}
+
+// Apply the response, either short-circuited with a cheap validator, or by hashing the buffered body
+func (r *etagResponse) Apply(ctx context.Context, w http.ResponseWriter) error {
+	if r.result == nil {
+		return nil
+	}
+
+	if cd := validators(r.result); cd != nil && (cd.ETag != "" || cd.LastModifiedSince != nil) {
+		if web.NotModified(r.request, cd.ETag, cd.LastModifiedSince) {
+			cd.ApplyHeaders(w.Header())
+			w.WriteHeader(http.StatusNotModified)
+			return nil
+		}
+
+		return r.result.Apply(ctx, w)
+	}
+
+	buffer := &bufferedResponseWriter{header: make(http.Header), status: http.StatusOK}
+	if err := r.result.Apply(ctx, buffer); err != nil {
+		return err
+	}
+
+	for name, vals := range buffer.header {
+		w.Header()[name] = vals
+	}
+
+	if buffer.status != http.StatusOK || buffer.header.Get("Cache-Control") == "no-store" {
+		w.WriteHeader(buffer.status)
+		_, err := buffer.body.WriteTo(w)
+		return err
+	}
+
+	etag := buffer.header.Get("ETag")
+	if etag == "" {
+		if r.weak {
+			etag = web.WeakETag(buffer.body.Bytes())
+		} else {
+			etag = web.ETag(buffer.body.Bytes())
+		}
+		w.Header().Set("ETag", etag)
+	}
+
+	if web.NotModified(r.request, etag, nil) {
+		w.Header().Del("Content-Length")
+		w.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+
+	w.WriteHeader(buffer.status)
+	_, err := buffer.body.WriteTo(w)
+	return err
+}
+
+// Header returns the buffered header
+func (b *bufferedResponseWriter) Header() http.Header {
+	return b.header
+}
+
+// Write to the buffer
+func (b *bufferedResponseWriter) Write(p []byte) (int, error) {
+	return b.body.Write(p)
+}
+
+// WriteHeader records the status
+func (b *bufferedResponseWriter) WriteHeader(statusCode int) {
+	b.status = statusCode
+}
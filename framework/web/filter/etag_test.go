@@ -0,0 +1,82 @@
+package filter
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"flamingo.me/flamingo/v3/framework/web"
+	"github.com/stretchr/testify/assert"
+)
+
+func applyETagFilter(t *testing.T, r *http.Request, result web.Result) *httptest.ResponseRecorder {
+	t.Helper()
+
+	f := &etagFilter{}
+	chain := web.NewFilterChain(func(ctx context.Context, req *web.Request, w http.ResponseWriter) web.Result {
+		return result
+	})
+	recorder := httptest.NewRecorder()
+	response := f.Filter(context.Background(), web.CreateRequest(r, nil), recorder, chain)
+	assert.NoError(t, response.Apply(context.Background(), recorder))
+
+	return recorder
+}
+
+func TestETagFilter_HashedBody(t *testing.T) {
+	body := []byte("hello world")
+	etag := web.ETag(body)
+
+	recorder := applyETagFilter(t, httptest.NewRequest(http.MethodGet, "/", nil), &web.Response{Body: bytes.NewReader(body)})
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, etag, recorder.Header().Get("ETag"))
+	assert.Equal(t, "hello world", recorder.Body.String())
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("If-None-Match", etag)
+	recorder = applyETagFilter(t, r, &web.Response{Body: bytes.NewReader(body)})
+	assert.Equal(t, http.StatusNotModified, recorder.Code)
+	assert.Equal(t, etag, recorder.Header().Get("ETag"))
+	assert.Empty(t, recorder.Body.String())
+}
+
+type applyCounter struct {
+	web.Response
+	applied int
+}
+
+func (a *applyCounter) Apply(ctx context.Context, w http.ResponseWriter) error {
+	a.applied++
+	return a.Response.Apply(ctx, w)
+}
+
+func TestETagFilter_CheapValidator(t *testing.T) {
+	response := &web.Response{
+		Header:         make(http.Header),
+		CacheDirective: web.CacheDirectiveBuilder{IsReusable: true, ETag: `"v1"`}.Build(),
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("If-None-Match", `"v1"`)
+	recorder := applyETagFilter(t, r, response)
+	assert.Equal(t, http.StatusNotModified, recorder.Code)
+	assert.Equal(t, `"v1"`, recorder.Header().Get("ETag"))
+
+	r.Header.Set("If-None-Match", `"v0"`)
+	recorder = applyETagFilter(t, r, response)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestETagFilter_SkipsNonGetAndErrors(t *testing.T) {
+	counter := &applyCounter{Response: web.Response{Status: http.StatusNotFound, Body: bytes.NewBufferString("not found")}}
+	recorder := applyETagFilter(t, httptest.NewRequest(http.MethodGet, "/", nil), counter)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Empty(t, recorder.Header().Get("ETag"))
+	assert.Equal(t, 1, counter.applied)
+
+	recorder = applyETagFilter(t, httptest.NewRequest(http.MethodPost, "/", nil), &web.Response{Body: bytes.NewBufferString("post")})
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Empty(t, recorder.Header().Get("ETag"))
+}
@@ -29,6 +29,9 @@ type (
 		sessionName  string
 		prefix       string
 		responder    *Responder
+		upload       *uploadConfig
+		metrics      *RequestMetrics
+		area         string
 	}
 
 	panicError struct {
@@ -104,6 +107,11 @@ func (h *handler) ServeHTTP(rw http.ResponseWriter, httpRequest *http.Request) {
 	ctx, span := trace.StartSpan(httpRequest.Context(), "router/ServeHTTP")
 	defer span.End()
 
+	ctx, _ = tag.New(ctx, tag.Insert(opencensus.KeyArea, h.area))
+	recordRequest := h.metrics.start(ctx, h.area)
+	statusWriter := &statusResponseWriter{ResponseWriter: rw}
+	rw = statusWriter
+
 	session, err := h.sessionStore.LoadByRequest(ctx, httpRequest)
 	if err != nil {
 		h.logger.WithContext(ctx).Warn(err)
@@ -112,8 +120,13 @@ func (h *handler) ServeHTTP(rw http.ResponseWriter, httpRequest *http.Request) {
 	_, span = trace.StartSpan(ctx, "router/matchRequest")
 	controller, params, handler := h.routerRegistry.matchRequest(httpRequest)
 
+	if handler != nil && handler.locale != "" {
+		ctx = ContextWithLocale(ctx, handler.locale)
+	}
+
 	if handler != nil {
-		ctx, _ = tag.New(ctx, tag.Upsert(ControllerKey, handler.GetHandlerName()), tag.Insert(opencensus.KeyArea, "-"))
+		trace.FromContext(ctx).AddAttributes(trace.StringAttribute(opencensus.HandlerAttribute, handler.GetHandlerName()))
+		ctx, _ = tag.New(ctx, tag.Upsert(ControllerKey, handler.GetHandlerName()))
 		httpRequest = httpRequest.WithContext(ctx)
 		start := time.Now()
 		defer func() {
@@ -124,6 +137,8 @@ func (h *handler) ServeHTTP(rw http.ResponseWriter, httpRequest *http.Request) {
 	req := &Request{
 		request: *httpRequest,
 		session: Session{s: session.s, sessionSaveMode: session.sessionSaveMode},
+		handler: handler,
+		upload:  h.upload,
 		Params:  params,
 	}
 	ctx = ContextWithRequest(ContextWithSession(ctx, req.Session()), req)
@@ -134,6 +149,14 @@ func (h *handler) ServeHTTP(rw http.ResponseWriter, httpRequest *http.Request) {
 		h.eventRouter.Dispatch(ctx, &OnFinishEvent{OnRequestEvent{req, rw}, finishErr})
 	}()
 
+	defer func() {
+		var handlerName string
+		if handler != nil {
+			handlerName = handler.GetHandlerName()
+		}
+		recordRequest(handlerName, httpRequest.Method, statusWriter.statusCode(), finishErr != nil)
+	}()
+
 	h.eventRouter.Dispatch(ctx, &OnRequestEvent{req, rw})
 
 	span.End() // router/matchRequest
@@ -0,0 +1,264 @@
+package web
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/opencensus"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+type (
+	// RequestMetrics records the rate, errors and duration of the requests by area, handler, method and status code,
+	// and the requests in flight by area
+	RequestMetrics struct {
+		mu          sync.Mutex
+		inflight    map[string]int64
+		maxHandlers int
+		handlers    map[string]struct{}
+	}
+
+	// statusResponseWriter captures the status code of a response
+	statusResponseWriter struct {
+		http.ResponseWriter
+		status int
+	}
+)
+
+// tag values which replace unbounded values
+const (
+	metricsUnmatched = "unmatched"
+	metricsOther     = "other"
+)
+
+var (
+	// durationMeasure records the duration of all requests, the request count is derived from it
+	durationMeasure = stats.Float64("flamingo/router/duration", "Duration of requests", stats.UnitMilliseconds)
+	// errorsMeasure counts requests with a server error or a failed response
+	errorsMeasure = stats.Int64("flamingo/router/errors", "Count of failed requests", stats.UnitDimensionless)
+	// inflightMeasure is the number of requests in flight
+	inflightMeasure = stats.Int64("flamingo/router/inflight", "Requests in flight", stats.UnitDimensionless)
+
+	keyHandler, _    = tag.NewKey("handler")
+	keyMethod, _     = tag.NewKey("method")
+	keyStatusCode, _ = tag.NewKey("status_code")
+
+	defaultDurationBuckets = []float64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+	durationViewMu      sync.Mutex
+	durationViewBuckets []float64
+
+	knownMethods = map[string]struct{}{
+		http.MethodGet:     {},
+		http.MethodHead:    {},
+		http.MethodPost:    {},
+		http.MethodPut:     {},
+		http.MethodPatch:   {},
+		http.MethodDelete:  {},
+		http.MethodConnect: {},
+		http.MethodOptions: {},
+		http.MethodTrace:   {},
+	}
+)
+
+func init() {
+	if err := opencensus.View("flamingo/router/requests", durationMeasure, view.Count(), keyHandler, keyMethod, keyStatusCode); err != nil {
+		panic(err)
+	}
+	if err := opencensus.View("flamingo/router/errors", errorsMeasure, view.Count(), keyHandler, keyMethod, keyStatusCode); err != nil {
+		panic(err)
+	}
+	if err := opencensus.View("flamingo/router/inflight", inflightMeasure, view.LastValue()); err != nil {
+		panic(err)
+	}
+	if err := registerDurationView(defaultDurationBuckets); err != nil {
+		panic(err)
+	}
+}
+
+// registerDurationView replaces the duration view if the buckets changed
+func registerDurationView(buckets []float64) error {
+	durationViewMu.Lock()
+	defer durationViewMu.Unlock()
+
+	if equalBuckets(durationViewBuckets, buckets) {
+		return nil
+	}
+
+	if v := view.Find("flamingo/router/duration"); v != nil {
+		view.Unregister(v)
+	}
+	if err := opencensus.View("flamingo/router/duration", durationMeasure, view.Distribution(buckets...), keyHandler, keyMethod, keyStatusCode); err != nil {
+		return err
+	}
+	durationViewBuckets = buckets
+
+	return nil
+}
+
+func equalBuckets(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Inject configuration, the histogram buckets are shared by all areas
+func (m *RequestMetrics) Inject(cfg *struct {
+	Buckets     config.Slice `inject:"config:flamingo.web.metrics.buckets,optional"`
+	MaxHandlers float64      `inject:"config:flamingo.web.metrics.maxHandlers,optional"`
+}) *RequestMetrics {
+	if cfg == nil {
+		return m
+	}
+
+	var buckets []float64
+	if err := cfg.Buckets.MapInto(&buckets); err != nil {
+		panic(err)
+	}
+	if len(buckets) > 0 {
+		if err := registerDurationView(buckets); err != nil {
+			panic(err)
+		}
+	}
+	m.maxHandlers = int(cfg.MaxHandlers)
+
+	return m
+}
+
+// start of a request, the in flight gauge is increased until the returned function records the request
+func (m *RequestMetrics) start(ctx context.Context, area string) func(handler, method string, status int, failed bool) {
+	if m == nil {
+		return func(string, string, int, bool) {}
+	}
+
+	start := time.Now()
+	m.recordInflight(ctx, area, 1)
+
+	return func(handler, method string, status int, failed bool) {
+		m.recordInflight(ctx, area, -1)
+
+		ctx, _ := tag.New(
+			ctx,
+			tag.Upsert(keyHandler, m.handlerValue(handler)),
+			tag.Upsert(keyMethod, methodValue(method)),
+			tag.Upsert(keyStatusCode, statusValue(status)),
+		)
+		stats.Record(ctx, durationMeasure.M(float64(time.Since(start))/float64(time.Millisecond)))
+		if failed || status >= http.StatusInternalServerError {
+			stats.Record(ctx, errorsMeasure.M(1))
+		}
+	}
+}
+
+func (m *RequestMetrics) recordInflight(ctx context.Context, area string, delta int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.inflight == nil {
+		m.inflight = make(map[string]int64)
+	}
+	m.inflight[area] += delta
+
+	// recorded under the lock, otherwise a concurrent request could overwrite the last value with a stale count
+	stats.Record(ctx, inflightMeasure.M(m.inflight[area]))
+}
+
+// handlerValue is the handler name, the cardinality guard replaces new names with "other" once maxHandlers is reached
+func (m *RequestMetrics) handlerValue(handler string) string {
+	if handler == "" {
+		return metricsUnmatched
+	}
+	if m.maxHandlers <= 0 {
+		return handler
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.handlers[handler]; ok {
+		return handler
+	}
+	if len(m.handlers) >= m.maxHandlers {
+		return metricsOther
+	}
+	if m.handlers == nil {
+		m.handlers = make(map[string]struct{})
+	}
+	m.handlers[handler] = struct{}{}
+
+	return handler
+}
+
+func methodValue(method string) string {
+	if _, ok := knownMethods[method]; ok {
+		return method
+	}
+
+	return metricsOther
+}
+
+func statusValue(status int) string {
+	if status < 100 || status > 599 {
+		return metricsOther
+	}
+
+	return strconv.Itoa(status)
+}
+
+// WriteHeader captures the status code
+func (w *statusResponseWriter) WriteHeader(statusCode int) {
+	if w.status == 0 {
+		w.status = statusCode
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write captures the implicit http.StatusOK
+func (w *statusResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush the wrapped response writer, if supported
+func (w *statusResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack the connection of the wrapped response writer, if supported
+func (w *statusResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hijacker, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return hijacker.Hijack()
+	}
+
+	return nil, nil, errors.New("response writer does not support hijacking")
+}
+
+// statusCode of the response, http.StatusOK if nothing has been written
+func (w *statusResponseWriter) statusCode() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+
+	return w.status
+}
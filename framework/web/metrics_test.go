@@ -0,0 +1,151 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/opencensus"
+)
+
+// rows of a view by the tag values of handler, method and status code
+func metricRows(t *testing.T, name string) map[string]view.AggregationData {
+	t.Helper()
+
+	rows, err := view.RetrieveData(name)
+	require.NoError(t, err)
+
+	result := make(map[string]view.AggregationData)
+	for _, row := range rows {
+		values := make(map[tag.Key]string)
+		for _, t := range row.Tags {
+			values[t.Key] = t.Value
+		}
+		result[values[keyHandler]+" "+values[keyMethod]+" "+values[keyStatusCode]] = row.Data
+	}
+
+	return result
+}
+
+func TestRequestMetrics(t *testing.T) {
+	metrics := new(RequestMetrics).Inject(&struct {
+		Buckets     config.Slice `inject:"config:flamingo.web.metrics.buckets,optional"`
+		MaxHandlers float64      `inject:"config:flamingo.web.metrics.maxHandlers,optional"`
+	}{
+		Buckets:     config.Slice{1.0, 1000.0},
+		MaxHandlers: 2,
+	})
+	defer func() {
+		assert.NoError(t, registerDurationView(defaultDurationBuckets))
+	}()
+
+	registry := NewRegistry()
+	registry.HandleAny(FlamingoNotfound, func(context.Context, *Request) Result {
+		return &Response{Status: http.StatusNotFound}
+	})
+	registry.HandleAny("metrics.ok", func(context.Context, *Request) Result {
+		return &Response{Status: http.StatusOK}
+	})
+	registry.MustRoute("/ok", "metrics.ok")
+	registry.HandleGet("metrics.failed", func(context.Context, *Request) Result {
+		return &Response{Status: http.StatusBadGateway}
+	})
+	registry.MustRoute("/failed", "metrics.failed")
+	registry.HandleGet("metrics.third", func(context.Context, *Request) Result {
+		return &Response{Status: http.StatusOK}
+	})
+	registry.MustRoute("/third", "metrics.third")
+
+	router := &Router{
+		eventRouter:    new(flamingo.DefaultEventRouter),
+		filterProvider: func() []Filter { return nil },
+		routesProvider: func() []RoutesModule { return nil },
+		logger:         flamingo.NullLogger{},
+		Metrics:        metrics,
+	}
+	h := router.Handler()
+	h.(*handler).routerRegistry = registry
+
+	for _, request := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/ok", nil),
+		httptest.NewRequest(http.MethodGet, "/ok", nil),
+		httptest.NewRequest(http.MethodGet, "/failed", nil),
+		httptest.NewRequest(http.MethodGet, "/third", nil),
+		httptest.NewRequest("CUSTOM", "/ok", nil),
+		httptest.NewRequest(http.MethodGet, "/not/routed/123", nil),
+	} {
+		h.ServeHTTP(httptest.NewRecorder(), request)
+	}
+
+	requests := metricRows(t, "flamingo/router/requests")
+	assert.Equal(t, int64(2), requests["metrics.ok GET 200"].(*view.CountData).Value)
+	assert.Equal(t, int64(1), requests["metrics.failed GET 502"].(*view.CountData).Value)
+	assert.Equal(t, int64(1), requests["other GET 200"].(*view.CountData).Value, "the third handler exceeds maxHandlers")
+	assert.Equal(t, int64(1), requests["metrics.ok other 200"].(*view.CountData).Value)
+	assert.Equal(t, int64(1), requests["unmatched GET 404"].(*view.CountData).Value)
+
+	errors := metricRows(t, "flamingo/router/errors")
+	assert.Len(t, errors, 1)
+	assert.Equal(t, int64(1), errors["metrics.failed GET 502"].(*view.CountData).Value)
+
+	duration := metricRows(t, "flamingo/router/duration")
+	assert.Equal(t, []float64{1, 1000}, view.Find("flamingo/router/duration").Aggregation.Buckets)
+	assert.Equal(t, int64(2), duration["metrics.ok GET 200"].(*view.DistributionData).Count)
+
+	inflight, err := view.RetrieveData("flamingo/router/inflight")
+	require.NoError(t, err)
+	require.Len(t, inflight, 1)
+	assert.Equal(t, 0.0, inflight[0].Data.(*view.LastValueData).Value)
+}
+
+func TestRequestMetrics_ConcurrentInflight(t *testing.T) {
+	metrics := new(RequestMetrics)
+	ctx, _ := tag.New(context.Background(), tag.Insert(opencensus.KeyArea, "concurrent"))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			metrics.start(ctx, "concurrent")("metrics.concurrent", http.MethodGet, http.StatusOK, false)
+		}()
+	}
+	wg.Wait()
+
+	rows, err := view.RetrieveData("flamingo/router/inflight")
+	require.NoError(t, err)
+
+	var inflight *view.LastValueData
+	for _, row := range rows {
+		if len(row.Tags) == 1 && row.Tags[0].Value == "concurrent" {
+			inflight = row.Data.(*view.LastValueData)
+		}
+	}
+	require.NotNil(t, inflight)
+	assert.Equal(t, 0.0, inflight.Value, "the last value is the count after the last request")
+}
+
+func TestStatusResponseWriter(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	w := &statusResponseWriter{ResponseWriter: recorder}
+	assert.Equal(t, http.StatusOK, w.statusCode())
+
+	w.WriteHeader(http.StatusNotFound)
+	w.WriteHeader(http.StatusInternalServerError)
+	assert.Equal(t, http.StatusNotFound, w.statusCode(), "the first status is sent")
+
+	w.Flush()
+	assert.True(t, recorder.Flushed)
+
+	_, _, err := w.Hijack()
+	assert.Error(t, err)
+}
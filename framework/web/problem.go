@@ -0,0 +1,266 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go.opencensus.io/trace"
+)
+
+type (
+	// Problem is a RFC 7807 problem detail, it can be returned as an error by controllers
+	Problem struct {
+		Type     string
+		Title    string
+		Status   uint
+		Detail   string
+		Instance string
+		TraceID  string
+		// Extensions are additional members which are serialized next to the standard members
+		Extensions map[string]interface{}
+
+		cause error
+	}
+
+	// ProblemResponse renders a Problem as application/problem+json
+	ProblemResponse struct {
+		Response
+		Problem *Problem
+	}
+)
+
+// ContentTypeProblemJSON is the media type of RFC 7807 problem details
+const ContentTypeProblemJSON = "application/problem+json"
+
+var _ error = new(Problem)
+
+// NewProblem creates a new problem with the given status and detail, the title defaults to the status text
+func NewProblem(status uint, detail string) *Problem {
+	return &Problem{
+		Status: status,
+		Title:  http.StatusText(int(status)),
+		Detail: detail,
+	}
+}
+
+// Wrap sets the underlying cause of the problem
+func (p *Problem) Wrap(err error) *Problem {
+	p.cause = err
+	return p
+}
+
+// WithType sets the problem type URI
+func (p *Problem) WithType(typeURI string) *Problem {
+	p.Type = typeURI
+	return p
+}
+
+// WithTitle sets the problem title
+func (p *Problem) WithTitle(title string) *Problem {
+	p.Title = title
+	return p
+}
+
+// WithExtension adds an extension member
+func (p *Problem) WithExtension(key string, value interface{}) *Problem {
+	if p.Extensions == nil {
+		p.Extensions = make(map[string]interface{})
+	}
+	p.Extensions[key] = value
+	return p
+}
+
+// Error returns the title and detail of the problem
+func (p *Problem) Error() string {
+	msg := p.Title
+	if p.Detail != "" {
+		msg += ": " + p.Detail
+	}
+	if p.cause != nil {
+		msg += ": " + p.cause.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the cause
+func (p *Problem) Unwrap() error {
+	return p.cause
+}
+
+// MarshalJSON serializes the standard members together with the extension members
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	members := make(map[string]interface{}, len(p.Extensions)+6)
+	for k, v := range p.Extensions {
+		members[k] = v
+	}
+
+	members["type"] = p.Type
+	if p.Type == "" {
+		members["type"] = "about:blank"
+	}
+	if p.Title != "" {
+		members["title"] = p.Title
+	}
+	if p.Status != 0 {
+		members["status"] = p.Status
+	}
+	if p.Detail != "" {
+		members["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		members["instance"] = p.Instance
+	}
+	if p.TraceID != "" {
+		members["traceId"] = p.TraceID
+	}
+
+	return json.Marshal(members)
+}
+
+// Problem creates a problem+json response
+func (r *Responder) Problem(problem *Problem) *ProblemResponse {
+	status := problem.Status
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+
+	return &ProblemResponse{
+		Problem: problem,
+		Response: Response{
+			Status: status,
+			Header: make(http.Header),
+		},
+	}
+}
+
+// Apply response
+func (r *ProblemResponse) Apply(c context.Context, w http.ResponseWriter) error {
+	problem := new(Problem)
+	if r.Problem != nil {
+		*problem = *r.Problem
+	}
+
+	if problem.Status == 0 {
+		problem.Status = r.Response.Status
+	}
+	if problem.Status == 0 {
+		problem.Status = http.StatusInternalServerError
+	}
+	if problem.Title == "" {
+		problem.Title = http.StatusText(int(problem.Status))
+	}
+	if req := RequestFromContext(c); req != nil && problem.Instance == "" {
+		problem.Instance = req.Request().URL.RequestURI()
+	}
+	if span := trace.FromContext(c); span != nil && problem.TraceID == "" && span.SpanContext().TraceID != (trace.TraceID{}) {
+		problem.TraceID = span.SpanContext().TraceID.String()
+	}
+
+	body, err := json.Marshal(problem)
+	if err != nil {
+		return err
+	}
+
+	if r.Response.Header == nil {
+		r.Response.Header = make(http.Header)
+	}
+	r.Response.Header.Set("Content-Type", ContentTypeProblemJSON)
+	r.Response.Status = problem.Status
+	r.Response.Body = bytes.NewReader(body)
+
+	return r.Response.Apply(c, w)
+}
+
+// SetNoCache helper
+func (r *ProblemResponse) SetNoCache() *ProblemResponse {
+	r.Response.SetNoCache()
+	return r
+}
+
+// problemFromError returns the problem of an error chain, or a new problem derived from the status
+func problemFromError(err error, status uint, detail string) *Problem {
+	var problem *Problem
+	if errors.As(err, &problem) {
+		return problem
+	}
+
+	return NewProblem(status, detail).Wrap(err)
+}
+
+// wantsProblem decides if an error should be rendered as problem+json instead of a template:
+// either the matched route is flagged as API route, the path matches a configured API path prefix
+// or the client prefers JSON over HTML
+func wantsProblem(req *Request, negotiate bool, apiPaths []string) bool {
+	if req == nil {
+		return false
+	}
+
+	if req.handler != nil && req.handler.api {
+		return true
+	}
+
+	for _, prefix := range apiPaths {
+		if strings.HasPrefix(req.request.URL.Path, prefix) {
+			return true
+		}
+	}
+
+	return negotiate && prefersJSON(req.request.Header.Get("Accept"))
+}
+
+// prefersJSON evaluates the Accept header, more specific media ranges win on equal quality
+func prefersJSON(accept string) bool {
+	if accept == "" {
+		return false
+	}
+
+	var jsonQ, htmlQ float64
+	var jsonSpecificity, htmlSpecificity int
+
+	for _, mediaRange := range strings.Split(accept, ",") {
+		parts := strings.Split(mediaRange, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(parts[0]))
+		q := 1.0
+		for _, param := range parts[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+
+		var matchesJSON, matchesHTML bool
+		var specificity int
+		switch {
+		case mediaType == "*/*":
+			matchesJSON, matchesHTML, specificity = true, true, 1
+		case mediaType == "application/*":
+			matchesJSON, specificity = true, 2
+		case mediaType == "text/*":
+			matchesHTML, specificity = true, 2
+		case mediaType == "application/json" || mediaType == ContentTypeProblemJSON || strings.HasSuffix(mediaType, "+json"):
+			matchesJSON, specificity = true, 3
+		case mediaType == "text/html" || mediaType == "application/xhtml+xml":
+			matchesHTML, specificity = true, 3
+		}
+
+		if matchesJSON && specificity > jsonSpecificity {
+			jsonQ, jsonSpecificity = q, specificity
+		}
+		if matchesHTML && specificity > htmlSpecificity {
+			htmlQ, htmlSpecificity = q, specificity
+		}
+	}
+
+	if jsonQ == 0 {
+		return false
+	}
+
+	return jsonQ > htmlQ || (jsonQ == htmlQ && jsonSpecificity > htmlSpecificity)
+}
@@ -0,0 +1,101 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrefersJSON(t *testing.T) {
+	for accept, want := range map[string]bool{
+		"": false,
+		"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8": false,
+		"application/json, text/plain, */*":                               true,
+		"application/problem+json":                                        true,
+		"application/json;q=0.5, text/html":                               false,
+		"text/html;q=0.5, application/json":                               true,
+		"*/*":                                                             false,
+		"application/*":                                                   true,
+		"application/json;q=0":                                            false,
+	} {
+		assert.Equal(t, want, prefersJSON(accept), accept)
+	}
+}
+
+func TestProblemResponse_Apply(t *testing.T) {
+	problem := NewProblem(http.StatusConflict, "the cart has been modified").
+		WithType("https://example.com/problems/cart-modified").
+		WithExtension("cartVersion", 3)
+
+	req := CreateRequest(httptest.NewRequest(http.MethodPost, "/cart/update?x=1", nil), nil)
+	recorder := httptest.NewRecorder()
+	require.NoError(t, new(Responder).Problem(problem).Apply(ContextWithRequest(context.Background(), req), recorder))
+
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Equal(t, ContentTypeProblemJSON, recorder.Header().Get("Content-Type"))
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	assert.Equal(t, map[string]interface{}{
+		"type":        "https://example.com/problems/cart-modified",
+		"title":       "Conflict",
+		"status":      float64(http.StatusConflict),
+		"detail":      "the cart has been modified",
+		"instance":    "/cart/update?x=1",
+		"cartVersion": float64(3),
+	}, body)
+}
+
+func TestServerErrorResponse_Problem(t *testing.T) {
+	responder := &Responder{problemNegotiation: true, problemAPIPaths: []string{"/api/"}}
+
+	apply := func(result Result, r *http.Request, handler *Handler) *httptest.ResponseRecorder {
+		req := CreateRequest(r, nil)
+		req.handler = handler
+		recorder := httptest.NewRecorder()
+		assert.NoError(t, result.Apply(ContextWithRequest(context.Background(), req), recorder))
+		return recorder
+	}
+
+	t.Run("html clients get the template response", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/page", nil)
+		r.Header.Set("Accept", "text/html")
+		recorder := apply(responder.NotFound(errors.New("not here")), r, nil)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+		assert.Equal(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
+	})
+
+	t.Run("json clients get a problem", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/page", nil)
+		r.Header.Set("Accept", "application/json")
+		recorder := apply(responder.NotFound(errors.New("not here")), r, nil)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+		assert.Equal(t, ContentTypeProblemJSON, recorder.Header().Get("Content-Type"))
+		assert.Contains(t, recorder.Body.String(), `"detail":"not here"`)
+	})
+
+	t.Run("server error details are hidden", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/api/orders", nil)
+		recorder := apply(responder.ServerError(errors.New("database password wrong")), r, nil)
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+		assert.Equal(t, ContentTypeProblemJSON, recorder.Header().Get("Content-Type"))
+		assert.NotContains(t, recorder.Body.String(), "password")
+	})
+
+	t.Run("api routes and typed problems", func(t *testing.T) {
+		handler := (&Handler{}).API()
+		problem := NewProblem(http.StatusUnprocessableEntity, "invalid quantity").WithExtension("field", "qty")
+		recorder := apply(responder.ServerError(fmt.Errorf("update cart: %w", problem)), httptest.NewRequest(http.MethodPost, "/cart", nil), handler)
+		assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+		assert.Equal(t, ContentTypeProblemJSON, recorder.Header().Get("Content-Type"))
+		assert.Contains(t, recorder.Body.String(), `"field":"qty"`)
+		assert.Contains(t, recorder.Body.String(), `"detail":"invalid quantity"`)
+	})
+}
@@ -30,6 +30,8 @@ type (
 		handler  string
 		params   map[string]*param
 		catchall bool
+		api      bool
+		locale   string
 	}
 
 	handlerAction struct {
@@ -309,6 +311,12 @@ func parseParams(list string) (params map[string]*param, catchall bool) {
 
 // Reverse builds the path from a named route with params
 func (registry *RouterRegistry) Reverse(name string, params map[string]string) (string, error) {
+	return registry.ReverseLocalized("", name, params)
+}
+
+// ReverseLocalized builds the path from a named route with params, preferring the route variant of the given locale.
+// Routes without locale are used as fallback.
+func (registry *RouterRegistry) ReverseLocalized(locale, name string, params map[string]string) (string, error) {
 	if alias, ok := registry.alias[name]; ok {
 		name = alias.handler
 		if params == nil {
@@ -319,19 +327,61 @@ func (registry *RouterRegistry) Reverse(name string, params map[string]string) (
 		}
 	}
 
-	var keys = make([]string, len(params))
-	var i = 0
-	for k := range params {
-		keys[i] = k
-		i++
+	return reverseRoutes(registry.candidates(name, locale), name, params)
+}
+
+// Alternates returns the paths of all locale variants of a handler, by locale
+func (registry *RouterRegistry) Alternates(name string, params map[string]string) map[string]string {
+	byLocale := make(map[string][]*Handler)
+	for _, handler := range registry.routes {
+		if handler.handler == name && handler.locale != "" {
+			byLocale[handler.locale] = append(byLocale[handler.locale], handler)
+		}
 	}
-	sort.Strings(keys)
 
-routeloop:
+	alternates := make(map[string]string, len(byLocale))
+	for locale, routes := range byLocale {
+		p := make(map[string]string, len(params))
+		for k, v := range params {
+			p[k] = v
+		}
+		if path, err := reverseRoutes(routes, name, p); err == nil {
+			alternates[locale] = path
+		}
+	}
+
+	return alternates
+}
+
+// candidates returns the routes of a handler, the variants of the given locale first
+func (registry *RouterRegistry) candidates(name, locale string) []*Handler {
+	var localized, others []*Handler
 	for _, handler := range registry.routes {
 		if handler.handler != name {
 			continue
 		}
+		if locale != "" && handler.locale == locale {
+			localized = append(localized, handler)
+		} else {
+			others = append(others, handler)
+		}
+	}
+
+	if locale == "" {
+		return others
+	}
+
+	// prefer routes without locale over variants of other locales
+	sort.SliceStable(others, func(i, j int) bool {
+		return others[i].locale == "" && others[j].locale != ""
+	})
+
+	return append(localized, others...)
+}
+
+func reverseRoutes(routes []*Handler, name string, params map[string]string) (string, error) {
+routeloop:
+	for _, handler := range routes {
 		var renderparams = make(map[string]string, len(handler.params)+len(params))
 		var usedValues = make(map[string]struct{}, len(handler.params))
 
@@ -373,8 +423,8 @@ routeloop:
 	}
 
 catchallrouteloop:
-	for _, handler := range registry.routes {
-		if handler.handler != name || !handler.catchall {
+	for _, handler := range routes {
+		if !handler.catchall {
 			continue
 		}
 		var renderparams = make(map[string]string, len(handler.params)+len(params))
@@ -518,3 +568,25 @@ func (handler *Handler) Normalize(params ...string) *Handler {
 	}
 	return handler
 }
+
+// API marks the route as API route, errors are then rendered as application/problem+json
+func (handler *Handler) API() *Handler {
+	handler.api = true
+	return handler
+}
+
+// Localized marks the route as variant of its handler for the given locale
+func (handler *Handler) Localized(locale string) *Handler {
+	handler.locale = locale
+	return handler
+}
+
+// Locale returns the locale of a localized route, it is empty for routes without locale
+func (handler *Handler) Locale() string {
+	return handler.locale
+}
+
+// IsAPI checks if the route is marked as API route
+func (handler *Handler) IsAPI() bool {
+	return handler.api
+}
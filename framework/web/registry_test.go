@@ -3,6 +3,7 @@ package web
 import (
 	"context"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -224,4 +225,40 @@ func TestRegistry(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, "/page2/Test+%26+123+-+test", path)
 	})
+
+	t.Run("Localized routes", func(t *testing.T) {
+		registry := NewRegistry()
+		registry.HandleAny("product.view", testController)
+		_, err := registry.Route("/product/:id", "product.view")
+		assert.NoError(t, err)
+		route, err := registry.Route("/de/produkt/:id", "product.view")
+		assert.NoError(t, err)
+		route.Localized("de")
+		registry.MustRoute("/en/product/:id", "product.view").Localized("en")
+
+		assert.Equal(t, "de", route.Locale())
+
+		path, err := registry.Reverse("product.view", map[string]string{"id": "1"})
+		assert.NoError(t, err)
+		assert.Equal(t, "/product/1", path)
+
+		path, err = registry.ReverseLocalized("de", "product.view", map[string]string{"id": "1"})
+		assert.NoError(t, err)
+		assert.Equal(t, "/de/produkt/1", path)
+
+		path, err = registry.ReverseLocalized("en", "product.view", map[string]string{"id": "1"})
+		assert.NoError(t, err)
+		assert.Equal(t, "/en/product/1", path)
+
+		path, err = registry.ReverseLocalized("fr", "product.view", map[string]string{"id": "1"})
+		assert.NoError(t, err)
+		assert.Equal(t, "/product/1", path)
+
+		assert.Equal(t, map[string]string{"de": "/de/produkt/1", "en": "/en/product/1"}, registry.Alternates("product.view", map[string]string{"id": "1"}))
+
+		req := httptest.NewRequest(http.MethodGet, "/de/produkt/2", nil)
+		_, params, handler := registry.matchRequest(req)
+		assert.Equal(t, "de", handler.Locale())
+		assert.Equal(t, "2", params["id"])
+	})
 }
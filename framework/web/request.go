@@ -14,6 +14,8 @@ type (
 	Request struct {
 		request http.Request
 		session Session
+		handler *Handler
+		upload  *uploadConfig
 		Params  RequestParams
 		Values  sync.Map
 	}
@@ -24,7 +26,10 @@ type (
 	contextKeyType string
 )
 
-const contextRequest contextKeyType = "request"
+const (
+	contextRequest contextKeyType = "request"
+	contextLocale  contextKeyType = "locale"
+)
 
 var (
 	// ErrFormNotFound is returned for unknown form values
@@ -64,6 +69,17 @@ func ContextWithRequest(ctx context.Context, r *Request) context.Context {
 	return context.WithValue(ctx, contextRequest, r)
 }
 
+// LocaleFromContext returns the locale of the matched localized route, or an empty string
+func LocaleFromContext(ctx context.Context) string {
+	locale, _ := ctx.Value(contextLocale).(string)
+	return locale
+}
+
+// ContextWithLocale stores the locale in a new context, the locale is used to pick localized route variants
+func ContextWithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, contextLocale, locale)
+}
+
 // Request getter
 func (r *Request) Request() *http.Request {
 	return &r.request
@@ -74,6 +90,11 @@ func (r *Request) Session() *Session {
 	return &r.session
 }
 
+// Handler returns the matched route handler, or nil if the request has not been routed
+func (r *Request) Handler() *Handler {
+	return r.handler
+}
+
 // RemoteAddress get the requests real remote address
 func (r *Request) RemoteAddress() []string {
 	var remoteAddress []string
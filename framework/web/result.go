@@ -15,7 +15,9 @@ import (
 	"strings"
 	"time"
 
+	"flamingo.me/flamingo/v3/framework/config"
 	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/redact"
 )
 
 type (
@@ -36,6 +38,9 @@ type (
 		templateNotFound      string
 		templateUnavailable   string
 		templateErrorWithCode string
+
+		problemNegotiation bool
+		problemAPIPaths    []string
 	}
 
 	// Response contains a status and a body
@@ -75,10 +80,15 @@ type (
 	}
 
 	// ServerErrorResponse returns a server error, by default http 500
+	// It is rendered as application/problem+json for API routes and clients preferring JSON
 	ServerErrorResponse struct {
 		RenderResponse
 		Error     error
 		ErrString string
+
+		debug              bool
+		problemNegotiation bool
+		problemAPIPaths    []string
 	}
 
 	// CacheDirectiveBuilder constructs a CacheDirective with the most commonly used options
@@ -132,6 +142,8 @@ func (r *Responder) Inject(router *Router, logger flamingo.Logger, cfg *struct {
 	TemplateNotFound      string                  `inject:"config:flamingo.template.err404"`
 	TemplateUnavailable   string                  `inject:"config:flamingo.template.err503"`
 	TemplateErrorWithCode string                  `inject:"config:flamingo.template.errWithCode"`
+	ProblemNegotiation    bool                    `inject:"config:flamingo.web.problem.negotiate,optional"`
+	ProblemAPIPaths       config.Slice            `inject:"config:flamingo.web.problem.apiPaths,optional"`
 }) *Responder {
 	r.engine = cfg.Engine
 	r.router = router
@@ -141,6 +153,8 @@ func (r *Responder) Inject(router *Router, logger flamingo.Logger, cfg *struct {
 	r.templateErrorWithCode = cfg.TemplateErrorWithCode
 	r.logger = logger.WithField("module", "framework.web").WithField("category", "responder")
 	r.debug = cfg.Debug
+	r.problemNegotiation = cfg.ProblemNegotiation
+	_ = cfg.ProblemAPIPaths.MapInto(&r.problemAPIPaths)
 	return r
 }
 
@@ -203,7 +217,7 @@ func (r *RouteRedirectResponse) Apply(c context.Context, w http.ResponseWriter)
 		return errors.New("no reverserouter available")
 	}
 
-	to, err := r.router.Relative(r.To, r.Data)
+	to, err := r.router.RelativeLocalized(LocaleFromContext(c), r.To, r.Data)
 	if err != nil {
 		return err
 	}
@@ -384,6 +398,10 @@ func (r *ServerErrorResponse) Apply(c context.Context, w http.ResponseWriter) er
 		r.RenderResponse.DataResponse.Response.Status = http.StatusInternalServerError
 	}
 
+	if wantsProblem(RequestFromContext(c), r.problemNegotiation, r.problemAPIPaths) {
+		return r.problemResponse().Apply(c, w)
+	}
+
 	if err := r.RenderResponse.Apply(c, w); err != nil {
 		http.Error(w, r.ErrString, int(r.RenderResponse.DataResponse.Response.Status))
 	}
@@ -391,7 +409,28 @@ func (r *ServerErrorResponse) Apply(c context.Context, w http.ResponseWriter) er
 	return nil
 }
 
+// problemResponse converts the error response to a problem+json response,
+// details of server errors are only exposed in debug mode
+func (r *ServerErrorResponse) problemResponse() *ProblemResponse {
+	status := r.RenderResponse.DataResponse.Response.Status
+
+	detail := r.ErrString
+	if status >= http.StatusInternalServerError && !r.debug {
+		detail = ""
+	}
+
+	return &ProblemResponse{
+		Problem: problemFromError(r.Error, status, detail),
+		Response: Response{
+			Status:         status,
+			Header:         r.RenderResponse.DataResponse.Response.Header,
+			CacheDirective: r.RenderResponse.DataResponse.Response.CacheDirective,
+		},
+	}
+}
+
 // ServerErrorWithCodeAndTemplate error response with template and http status code
+// If the error is, or wraps, a *Problem with a status, the problem status is used
 func (r *Responder) ServerErrorWithCodeAndTemplate(err error, tpl string, status uint) *ServerErrorResponse {
 	var errstr string
 
@@ -399,6 +438,11 @@ func (r *Responder) ServerErrorWithCodeAndTemplate(err error, tpl string, status
 		err = errors.New("")
 	}
 
+	var problem *Problem
+	if errors.As(err, &problem) && problem.Status != 0 {
+		status = problem.Status
+	}
+
 	if r.debug {
 		errstr = fmt.Sprintf("%+v", err)
 	} else {
@@ -406,8 +450,11 @@ func (r *Responder) ServerErrorWithCodeAndTemplate(err error, tpl string, status
 	}
 
 	return &ServerErrorResponse{
-		Error:     err,
-		ErrString: errstr,
+		Error:              err,
+		ErrString:          errstr,
+		debug:              r.debug,
+		problemNegotiation: r.problemNegotiation,
+		problemAPIPaths:    r.problemAPIPaths,
 		RenderResponse: RenderResponse{
 			Template: tpl,
 			engine:   r.engine,
@@ -471,7 +518,7 @@ func (r *Responder) getLogger() flamingo.Logger {
 	if r.logger != nil {
 		return r.logger
 	}
-	return &flamingo.StdLogger{Logger: *log.New(os.Stdout, "flamingo", log.LstdFlags)}
+	return &flamingo.StdLogger{Logger: *log.New(os.Stdout, "flamingo", log.LstdFlags), Redactor: redact.Default()}
 }
 
 func (r *Responder) completeResult(result Result) Result {
@@ -7,8 +7,10 @@ import (
 	"net/url"
 	"path"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 
 	"flamingo.me/flamingo/v3/framework/config"
 	"flamingo.me/flamingo/v3/framework/flamingo"
@@ -40,10 +42,23 @@ type (
 		routesProvider    routesProvider
 		logger            flamingo.Logger
 		routerRegistry    *RouterRegistry
+		registryOnce      sync.Once
+		registryErr       error
 		configArea        *config.Area
 		sessionStore      *SessionStore
 		sessionName       string
 		responderProvider responderProvider
+
+		// Upload limits of the requests, injected as field next to Inject; the defaults apply without them
+		Upload *UploadConfig `inject:""`
+		// Metrics of the requests, nil disables them
+		Metrics *RequestMetrics `inject:",optional"`
+	}
+
+	// Alternate is a locale variant of a route, e.g. for hreflang links
+	Alternate struct {
+		Locale string
+		URL    string
 	}
 
 	// AreaRoutedEvent is dispatched when the router initializes the Handler
@@ -104,25 +119,8 @@ func (r *Router) Inject(
 
 // Handler creates and returns new instance of http.Handler interface
 func (r *Router) Handler() http.Handler {
-	r.routerRegistry = NewRegistry()
-
-	if r.configArea != nil {
-		for _, route := range r.configArea.Routes {
-			r.routerRegistry.MustRoute(route.Path, route.Controller)
-			if route.Name != "" {
-				r.routerRegistry.Alias(route.Name, route.Controller)
-			}
-		}
-	}
-
-	for _, m := range r.routesProvider() {
-		m.Routes(r.routerRegistry)
-	}
-
-	for _, handler := range r.routerRegistry.routes {
-		if _, ok := r.routerRegistry.handler[handler.handler]; !ok {
-			panic(fmt.Errorf("the handler %q has no controller, registered for path %q", handler.handler, handler.path.path))
-		}
+	if _, err := r.registry(); err != nil {
+		panic(err)
 	}
 
 	if r.responderProvider == nil {
@@ -133,6 +131,16 @@ func (r *Router) Handler() http.Handler {
 		r.eventRouter.Dispatch(context.Background(), &AreaRoutedEvent{ConfigArea: r.configArea})
 	}
 
+	area := "-"
+	if r.configArea != nil {
+		area = r.configArea.Name
+	}
+
+	upload := defaultUploadConfig
+	if r.Upload != nil {
+		upload = r.Upload.config
+	}
+
 	return &handler{
 		routerRegistry: r.routerRegistry,
 		filter:         r.filterProvider(),
@@ -142,7 +150,51 @@ func (r *Router) Handler() http.Handler {
 		sessionName:    r.sessionName,
 		prefix:         strings.TrimRight(r.Base().Path, "/"),
 		responder:      r.responderProvider(),
+		upload:         &upload,
+		metrics:        r.Metrics,
+		area:           area,
+	}
+}
+
+// registerRoutes registers the configured routes and the routes of all RoutesModules in a new registry
+func (r *Router) registerRoutes() *RouterRegistry {
+	registry := NewRegistry()
+
+	if r.configArea != nil {
+		for _, route := range r.configArea.Routes {
+			registry.MustRoute(route.Path, route.Controller).Localized(route.Locale)
+			if route.Name != "" {
+				registry.Alias(route.Name, route.Controller)
+			}
+		}
 	}
+
+	for _, m := range r.routesProvider() {
+		m.Routes(registry)
+	}
+
+	return registry
+}
+
+// registry is built once from the registered routes, it fails if a route has no controller.
+// It can be called concurrently, e.g. by the AreaRouter of another area.
+func (r *Router) registry() (*RouterRegistry, error) {
+	r.registryOnce.Do(func() {
+		if r.routerRegistry != nil {
+			return
+		}
+
+		registry := r.registerRoutes()
+		for _, handler := range registry.routes {
+			if _, ok := registry.handler[handler.handler]; !ok {
+				r.registryErr = fmt.Errorf("the handler %q has no controller, registered for path %q", handler.handler, handler.path.path)
+				return
+			}
+		}
+		r.routerRegistry = registry
+	})
+
+	return r.routerRegistry, r.registryErr
 }
 
 // ListenAndServe starts flamingo server
@@ -167,7 +219,7 @@ func (r *Router) URL(to string, params map[string]string) (*url.URL, error) {
 	return r.Relative(to, params)
 }
 
-func (r *Router) relative(to string, params map[string]string) (string, error) {
+func (r *Router) relative(locale, to string, params map[string]string) (string, error) {
 	if to == "" {
 		return "", nil
 	}
@@ -176,12 +228,17 @@ func (r *Router) relative(to string, params map[string]string) (string, error) {
 		return to, nil
 	}
 
-	p, err := r.routerRegistry.Reverse(to, params)
+	p, err := r.routerRegistry.ReverseLocalized(locale, to, params)
 	return strings.TrimLeft(p, "/"), err
 }
 
 // Relative returns a root-relative URL, starting with `/`
 func (r *Router) Relative(to string, params map[string]string) (*url.URL, error) {
+	return r.RelativeLocalized("", to, params)
+}
+
+// RelativeLocalized returns a root-relative URL, starting with `/`, for the route variant of the given locale
+func (r *Router) RelativeLocalized(locale, to string, params map[string]string) (*url.URL, error) {
 	if to == "" {
 		relativePath := r.Base().Path
 		if r.external != nil {
@@ -193,7 +250,7 @@ func (r *Router) Relative(to string, params map[string]string) (*url.URL, error)
 		}, nil
 	}
 
-	p, err := r.relative(to, params)
+	p, err := r.relative(locale, to, params)
 	if err != nil {
 		return nil, err
 	}
@@ -207,11 +264,21 @@ func (r *Router) Relative(to string, params map[string]string) (*url.URL, error)
 }
 
 // Absolute returns an absolute URL, with scheme and host.
-// It takes the request to construct as many information as possible
+// It takes the request to construct as many information as possible, including the locale of the matched route
 func (r *Router) Absolute(req *Request, to string, params map[string]string) (*url.URL, error) {
+	var locale string
+	if req != nil && req.handler != nil {
+		locale = req.handler.locale
+	}
+
+	return r.AbsoluteLocalized(req, locale, to, params)
+}
+
+// AbsoluteLocalized returns an absolute URL, with scheme and host, for the route variant of the given locale
+func (r *Router) AbsoluteLocalized(req *Request, locale, to string, params map[string]string) (*url.URL, error) {
 	if r.external != nil {
 		e := *r.external
-		p, err := r.relative(to, params)
+		p, err := r.relative(locale, to, params)
 		if err != nil {
 			return nil, err
 		}
@@ -234,7 +301,7 @@ func (r *Router) Absolute(req *Request, to string, params map[string]string) (*u
 		host = req.request.Host
 	}
 
-	u, err := r.Relative(to, params)
+	u, err := r.RelativeLocalized(locale, to, params)
 	if err != nil {
 		return u, err
 	}
@@ -244,6 +311,29 @@ func (r *Router) Absolute(req *Request, to string, params map[string]string) (*u
 	return u, nil
 }
 
+// Alternates returns the absolute URLs of all locale variants of the route matched by the request
+func (r *Router) Alternates(req *Request) []Alternate {
+	if req == nil || req.handler == nil || req.handler.locale == "" {
+		return nil
+	}
+
+	paths := r.routerRegistry.Alternates(req.handler.handler, req.Params)
+	alternates := make([]Alternate, 0, len(paths))
+	for locale, p := range paths {
+		u, err := r.AbsoluteLocalized(req, locale, p, nil)
+		if err != nil {
+			continue
+		}
+		alternates = append(alternates, Alternate{Locale: locale, URL: u.String()})
+	}
+
+	sort.Slice(alternates, func(i, j int) bool {
+		return alternates[i].Locale < alternates[j].Locale
+	})
+
+	return alternates
+}
+
 func dataParams(params map[interface{}]interface{}) RequestParams {
 	vars := make(map[string]string, len(params))
 
@@ -7,6 +7,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -522,3 +524,75 @@ func TestRouterRelativeAndAbsolute(t *testing.T) {
 		assert.Equal(t, "http://external.domain/external-path/test", absoluteURL.String())
 	})
 }
+
+func TestRouterLocalized(t *testing.T) {
+	registry := NewRegistry()
+	registry.HandleGet("product.view", func(context.Context, *Request) Result { return nil })
+	registry.MustRoute("/de/produkt/:id", "product.view").Localized("de")
+	registry.MustRoute("/en/product/:id", "product.view").Localized("en")
+
+	router := &Router{
+		base:           &url.URL{Scheme: "https", Host: "example.com", Path: "/shop"},
+		routerRegistry: registry,
+	}
+
+	u, err := router.RelativeLocalized("en", "product.view", map[string]string{"id": "1"})
+	require.NoError(t, err)
+	assert.Equal(t, "/shop/en/product/1", u.String())
+
+	req := CreateRequest(httptest.NewRequest(http.MethodGet, "/de/produkt/1", nil), nil)
+	_, req.Params, req.handler = registry.matchRequest(req.Request())
+
+	u, err = router.Absolute(req, "product.view", map[string]string{"id": "2"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/shop/de/produkt/2", u.String())
+
+	assert.Equal(t, []Alternate{
+		{Locale: "de", URL: "https://example.com/shop/de/produkt/1"},
+		{Locale: "en", URL: "https://example.com/shop/en/product/1"},
+	}, router.Alternates(req))
+}
+
+type countingRoutes struct {
+	calls      int32
+	controller bool
+}
+
+func (m *countingRoutes) Routes(registry *RouterRegistry) {
+	atomic.AddInt32(&m.calls, 1)
+	if m.controller {
+		registry.HandleGet("counting", func(context.Context, *Request) Result { return nil })
+	}
+	registry.MustRoute("/counting", "counting")
+}
+
+func TestRouter_Registry(t *testing.T) {
+	t.Run("built once", func(t *testing.T) {
+		routes := &countingRoutes{controller: true}
+		router := &Router{routesProvider: func() []RoutesModule { return []RoutesModule{routes} }}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := router.registry()
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), atomic.LoadInt32(&routes.calls))
+		u, err := router.Relative("counting", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "/counting", u.String())
+	})
+
+	t.Run("missing controller", func(t *testing.T) {
+		router := &Router{routesProvider: func() []RoutesModule { return []RoutesModule{new(countingRoutes)} }}
+
+		_, err := router.registry()
+		assert.Error(t, err)
+		assert.Panics(t, func() { router.Handler() })
+	})
+}
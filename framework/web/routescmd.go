@@ -23,6 +23,8 @@ func RoutesCmd(router *Router, area *config.Area) *cobra.Command {
 		},
 	}
 
+	cmd.AddCommand(routesLintCmd(area))
+
 	return cmd
 }
 
@@ -55,6 +57,9 @@ func dumpRoutes(router *Router, area *config.Area) {
 	fmt.Println("****************************************************************************")
 	for _, routeHandler := range router.routerRegistry.routes {
 		routePath := routeHandler.path.path + "(" + strings.Join(routeHandler.path.params, ";") + ")"
+		if routeHandler.locale != "" {
+			routePath += " [" + routeHandler.locale + "]"
+		}
 		spaceAmount1 := int(math.Max(0, float64(60-len(routePath))))
 		fmt.Printf("    %s%s| %s\n", routePath, strings.Repeat(" ", spaceAmount1), routeHandler.handler)
 	}
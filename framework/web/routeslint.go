@@ -0,0 +1,296 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"flamingo.me/flamingo/v3/framework/config"
+	"github.com/spf13/cobra"
+)
+
+type (
+	// RouteIssue is a routing problem found by the routes linter
+	RouteIssue struct {
+		Area    string
+		Path    string
+		Handler string
+		Message string
+	}
+)
+
+// lintSampleValue is used to build a concrete request path from a route to check which routes match it
+const lintSampleValue = "flamingo-lint-sample"
+
+// String formats the issue for the lint output
+func (i RouteIssue) String() string {
+	var location []string
+	if i.Path != "" {
+		location = append(location, "path "+i.Path)
+	}
+	if i.Handler != "" {
+		location = append(location, "handler "+i.Handler)
+	}
+
+	return fmt.Sprintf("[%s] %s: %s", i.Area, strings.Join(location, ", "), i.Message)
+}
+
+func routesLintCmd(area *config.Area) *cobra.Command {
+	return &cobra.Command{
+		Use:          "lint",
+		Short:        "Check the routes of all config areas for routing problems",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := area
+			for root.Parent != nil {
+				root = root.Parent
+			}
+
+			issues, err := lintAreas(root)
+			if err != nil {
+				return err
+			}
+
+			return printIssues(cmd.OutOrStdout(), issues)
+		},
+	}
+}
+
+func printIssues(w io.Writer, issues []RouteIssue) error {
+	for _, issue := range issues {
+		_, _ = fmt.Fprintln(w, issue.String())
+	}
+
+	if len(issues) > 0 {
+		return fmt.Errorf("%d routing problems found", len(issues))
+	}
+
+	_, _ = fmt.Fprintln(w, "no routing problems found")
+	return nil
+}
+
+// lintAreas initializes the router of every config area and lints its routes
+func lintAreas(root *config.Area) ([]RouteIssue, error) {
+	flat, err := root.Flat()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(flat))
+	for name := range flat {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var issues []RouteIssue
+	for _, name := range names {
+		registry, err := areaRegistry(flat[name])
+		if err != nil {
+			issues = append(issues, RouteIssue{Area: name, Message: err.Error()})
+			continue
+		}
+
+		for _, issue := range lintRegistry(registry) {
+			issue.Area = name
+			issues = append(issues, issue)
+		}
+	}
+
+	return issues, nil
+}
+
+// areaRegistry registers the routes of an area without validating them, panics of routes modules are returned as error
+func areaRegistry(area *config.Area) (registry *RouterRegistry, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("registering routes failed: %v", p)
+		}
+	}()
+
+	injector, err := area.GetInitializedInjector()
+	if err != nil {
+		return nil, err
+	}
+
+	i, err := injector.GetInstance(Router{})
+	if err != nil {
+		return nil, err
+	}
+
+	return i.(*Router).registerRoutes(), nil
+}
+
+// lintRegistry checks a registry for routing problems
+func lintRegistry(registry *RouterRegistry) []RouteIssue {
+	var issues []RouteIssue
+
+	routed := make(map[string]bool, len(registry.handler))
+	for _, route := range registry.routes {
+		routed[route.handler] = true
+	}
+
+	for i, route := range registry.routes {
+		issue := func(format string, args ...interface{}) {
+			issues = append(issues, RouteIssue{Path: route.path.path, Handler: route.handler, Message: fmt.Sprintf(format, args...)})
+		}
+
+		action, ok := registry.handler[route.handler]
+		switch {
+		case !ok:
+			issue("handler has no controller")
+			continue
+		case action.any == nil && len(action.method) == 0:
+			issue("handler has only a data action, which can not serve HTTP requests")
+		}
+
+		for _, p := range route.path.params {
+			if _, ok := route.params[p]; !ok {
+				issue("path param %q is not part of the handler params and will be dropped", p)
+			}
+		}
+
+		for _, name := range sortedParams(route.params) {
+			if param := route.params[name]; !param.optional && param.value == "" && !containsString(route.path.params, name) {
+				issue("handler param %q is not part of the path and must be passed as query parameter", name)
+			}
+		}
+
+		for _, earlier := range registry.routes[:i] {
+			if earlier.path.path == route.path.path && earlier.handler == route.handler {
+				if sameDefaults(earlier, route) {
+					issue("duplicate route")
+				} else {
+					issue("duplicate path with conflicting defaults")
+				}
+				break
+			}
+
+			if shadows(registry, earlier, route) {
+				issue("shadowed by earlier route %s (%s)", earlier.path.path, earlier.handler)
+				break
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(registry.alias) {
+		alias := registry.alias[name]
+		if _, ok := registry.handler[alias.handler]; !ok {
+			issues = append(issues, RouteIssue{Handler: name, Message: fmt.Sprintf("alias points to unknown handler %q", alias.handler)})
+		} else if !routed[alias.handler] {
+			issues = append(issues, RouteIssue{Handler: name, Message: fmt.Sprintf("alias points to handler %q which has no route", alias.handler)})
+		}
+	}
+
+	for _, name := range getSortedMapKeys(registry.handler) {
+		action := registry.handler[name]
+		// the generic framework handlers are only routed on demand
+		if routed[name] || strings.HasPrefix(name, "flamingo.") {
+			continue
+		}
+		if action.any != nil || len(action.method) > 0 {
+			issues = append(issues, RouteIssue{Handler: name, Message: "handler is registered without route"})
+		}
+	}
+
+	return issues
+}
+
+// shadows checks if the earlier route matches all requests of the route, for all methods the route handles
+func shadows(registry *RouterRegistry, earlier, route *Handler) bool {
+	sample, err := samplePath(route.path)
+	if err != nil || earlier.path.Match(sample) == nil {
+		return false
+	}
+
+	// routes with required query params fall through to later routes if the param is missing
+	for name, param := range earlier.params {
+		if !param.optional && param.value == "" && !containsString(earlier.path.params, name) {
+			return false
+		}
+	}
+
+	earlierAction := registry.handler[earlier.handler]
+	if earlierAction.any != nil {
+		return true
+	}
+
+	action := registry.handler[route.handler]
+	if action.any != nil || len(earlierAction.method) == 0 {
+		return false
+	}
+
+	for method := range action.method {
+		if _, ok := earlierAction.method[method]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// samplePath renders a concrete request path for a route, regex params can not be sampled
+func samplePath(path *Path) (string, error) {
+	var sample string
+	for _, part := range path.parts {
+		switch part := part.(type) {
+		case *partFixed:
+			sample += "/" + part.part
+		case *partParam:
+			sample += "/" + lintSampleValue + part.suffix
+		case *partWildcard:
+			sample += "/" + lintSampleValue
+		default:
+			return "", errors.New("path can not be sampled")
+		}
+	}
+
+	if sample == "" {
+		sample = "/"
+	}
+
+	return sample, nil
+}
+
+func sameDefaults(a, b *Handler) bool {
+	if len(a.params) != len(b.params) {
+		return false
+	}
+
+	for name, param := range a.params {
+		other, ok := b.params[name]
+		if !ok || *other != *param {
+			return false
+		}
+	}
+
+	return true
+}
+
+func sortedParams(params map[string]*param) []string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedKeys(m map[string]*Handler) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLintRegistry(t *testing.T) {
+	registry := NewRegistry()
+	registry.HandleAny("page.view", testController)
+	registry.HandleGet("product.view", testController)
+	registry.HandleGet("product.special", testController)
+	registry.HandleAny("unrouted", testController)
+	registry.HandleData("teaser", func(context.Context, *Request, RequestParams) interface{} { return nil })
+	registry.HandleAny("flamingo.redirect", testController)
+
+	registry.MustRoute("/page/:name", "page.view")
+	registry.MustRoute("/page/:name", `page.view(name="x")`)
+	registry.MustRoute("/product/:id", "product.view")
+	registry.MustRoute("/product/:sku", "product.special")
+	registry.MustRoute("/product/$id<[0-9]+>/detail", "product.view")
+	registry.MustRoute("/category/:code", "product.view(id)")
+	registry.MustRoute("/teaser", "teaser")
+	registry.MustRoute("/missing", "missing")
+	registry.Alias("home", "unknown")
+
+	var messages []string
+	for _, issue := range lintRegistry(registry) {
+		messages = append(messages, issue.Message)
+	}
+
+	assert.ElementsMatch(t, []string{
+		"duplicate path with conflicting defaults",
+		"shadowed by earlier route /product/:id (product.view)",
+		`path param "code" is not part of the handler params and will be dropped`,
+		`handler param "id" is not part of the path and must be passed as query parameter`,
+		"handler has only a data action, which can not serve HTTP requests",
+		"handler has no controller",
+		`alias points to unknown handler "unknown"`,
+		"handler is registered without route",
+	}, messages)
+}
+
+func TestPrintIssues(t *testing.T) {
+	buf := new(bytes.Buffer)
+	assert.NoError(t, printIssues(buf, nil))
+	assert.Equal(t, "no routing problems found\n", buf.String())
+
+	buf.Reset()
+	err := printIssues(buf, []RouteIssue{{Area: "root", Path: "/a", Handler: "a", Message: "handler has no controller"}})
+	assert.EqualError(t, err, "1 routing problems found")
+	assert.Equal(t, "[root] path /a, handler a: handler has no controller\n", buf.String())
+}
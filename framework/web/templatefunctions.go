@@ -96,3 +96,21 @@ func (c *IsExternalURL) Func(ctx context.Context) interface{} {
 		return false
 	}
 }
+
+// HreflangFunc is exported as a template function
+type HreflangFunc struct {
+	router *Router
+}
+
+// Inject dependencies
+func (h *HreflangFunc) Inject(router *Router) *HreflangFunc {
+	h.router = router
+	return h
+}
+
+// Func returns the locale variants of the current route, to render hreflang alternate links
+func (h *HreflangFunc) Func(ctx context.Context) interface{} {
+	return func() []Alternate {
+		return h.router.Alternates(RequestFromContext(ctx))
+	}
+}
@@ -0,0 +1,375 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+)
+
+type (
+	// UploadedFile is a file of a multipart request, stored in temporary storage until the request is finished
+	UploadedFile struct {
+		Field       string
+		Filename    string
+		ContentType string
+		Size        int64
+		Header      textproto.MIMEHeader
+		path        string
+	}
+
+	// UploadError describes a rejected upload, it wraps one of the ErrUpload* errors
+	UploadError struct {
+		Field       string
+		Filename    string
+		ContentType string
+		Limit       int64
+		err         error
+	}
+
+	// UploadCleanupSubscriber removes temporary upload files once the request is finished
+	UploadCleanupSubscriber struct{}
+
+	// UploadConfig holds the configured limits of the upload helpers, the Router passes them to its requests
+	UploadConfig struct {
+		config uploadConfig
+	}
+
+	uploadConfig struct {
+		maxBodySize  int64
+		maxFileSize  int64
+		maxValueSize int64
+		allowedTypes []string
+		tempDir      string
+	}
+
+	uploads struct {
+		mu    sync.Mutex
+		files map[string][]*UploadedFile
+		err   error
+	}
+
+	limitedBody struct {
+		r        io.Reader
+		n        int64
+		exceeded bool
+	}
+)
+
+const uploadsKey contextKeyType = "uploads"
+
+var (
+	// ErrNotMultipart is returned if the request is not a multipart/form-data request
+	ErrNotMultipart = errors.New("request is not multipart/form-data")
+	// ErrFileNotFound is returned if no file has been uploaded for a form field
+	ErrFileNotFound = errors.New("uploaded file not found")
+	// ErrUploadBodyTooLarge is returned if the request body exceeds the configured max body size
+	ErrUploadBodyTooLarge = errors.New("upload: request body too large")
+	// ErrUploadFileTooLarge is returned if a file exceeds the configured max file size
+	ErrUploadFileTooLarge = errors.New("upload: file too large")
+	// ErrUploadValueTooLarge is returned if a non-file form value exceeds the configured max value size
+	ErrUploadValueTooLarge = errors.New("upload: form value too large")
+	// ErrUploadTypeNotAllowed is returned if the sniffed content type of a file is not allowed
+	ErrUploadTypeNotAllowed = errors.New("upload: content type not allowed")
+
+	defaultUploadConfig = uploadConfig{
+		maxBodySize:  32 << 20,
+		maxFileSize:  10 << 20,
+		maxValueSize: 1 << 20,
+	}
+)
+
+// Error message
+func (e *UploadError) Error() string {
+	msg := e.err.Error()
+	if e.Field != "" {
+		msg += fmt.Sprintf(" (field %q", e.Field)
+		if e.Filename != "" {
+			msg += fmt.Sprintf(", file %q", e.Filename)
+		}
+		msg += ")"
+	}
+	if e.ContentType != "" {
+		msg += ": " + e.ContentType
+	}
+	if e.Limit > 0 {
+		msg += fmt.Sprintf(": limit is %d bytes", e.Limit)
+	}
+	return msg
+}
+
+// Unwrap the underlying ErrUpload* error
+func (e *UploadError) Unwrap() error {
+	return e.err
+}
+
+// Open the uploaded file for reading
+func (f *UploadedFile) Open() (*os.File, error) {
+	return os.Open(f.path)
+}
+
+// Path of the temporary file, the file is removed once the request is finished
+func (f *UploadedFile) Path() string {
+	return f.path
+}
+
+// File returns the first uploaded file of the given form field
+func (r *Request) File(name string) (*UploadedFile, error) {
+	files, err := r.Files()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(files[name]) == 0 {
+		return nil, ErrFileNotFound
+	}
+
+	return files[name][0], nil
+}
+
+// Files returns all uploaded files by form field.
+// The multipart body is parsed once, files are streamed to temporary storage and the non-file
+// values are available via Form afterwards.
+func (r *Request) Files() (map[string][]*UploadedFile, error) {
+	u := new(uploads)
+	if existing, loaded := r.Values.LoadOrStore(uploadsKey, u); loaded {
+		u = existing.(*uploads)
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.files == nil {
+		u.files = make(map[string][]*UploadedFile)
+		u.err = r.readMultipart(u)
+		if u.err != nil {
+			u.cleanup()
+		}
+	}
+
+	if u.err != nil {
+		return nil, u.err
+	}
+
+	return u.files, nil
+}
+
+// Inject configuration, unset limits keep the defaults
+func (c *UploadConfig) Inject(cfg *struct {
+	MaxBodySize  float64      `inject:"config:flamingo.web.upload.maxBodySize,optional"`
+	MaxFileSize  float64      `inject:"config:flamingo.web.upload.maxFileSize,optional"`
+	MaxValueSize float64      `inject:"config:flamingo.web.upload.maxValueSize,optional"`
+	AllowedTypes config.Slice `inject:"config:flamingo.web.upload.allowedTypes,optional"`
+	TempDir      string       `inject:"config:flamingo.web.upload.tempDir,optional"`
+}) *UploadConfig {
+	c.config = defaultUploadConfig
+	if cfg == nil {
+		return c
+	}
+
+	if cfg.MaxBodySize > 0 {
+		c.config.maxBodySize = int64(cfg.MaxBodySize)
+	}
+	if cfg.MaxFileSize > 0 {
+		c.config.maxFileSize = int64(cfg.MaxFileSize)
+	}
+	if cfg.MaxValueSize > 0 {
+		c.config.maxValueSize = int64(cfg.MaxValueSize)
+	}
+	_ = cfg.AllowedTypes.MapInto(&c.config.allowedTypes)
+	c.config.tempDir = cfg.TempDir
+
+	return c
+}
+
+func (r *Request) uploadConfig() uploadConfig {
+	if r.upload == nil {
+		return defaultUploadConfig
+	}
+	return *r.upload
+}
+
+func (r *Request) readMultipart(u *uploads) error {
+	cfg := r.uploadConfig()
+
+	if r.request.Body == nil {
+		return ErrNotMultipart
+	}
+
+	body := &limitedBody{r: r.request.Body, n: cfg.maxBodySize}
+	r.request.Body = ioutil.NopCloser(body)
+
+	reader, err := r.request.MultipartReader()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrNotMultipart, err)
+	}
+
+	values := make(url.Values)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if body.exceeded {
+				return &UploadError{Limit: cfg.maxBodySize, err: ErrUploadBodyTooLarge}
+			}
+			return err
+		}
+
+		if part.FileName() == "" {
+			value, err := ioutil.ReadAll(io.LimitReader(part, cfg.maxValueSize+1))
+			if err != nil {
+				if body.exceeded {
+					return &UploadError{Field: part.FormName(), Limit: cfg.maxBodySize, err: ErrUploadBodyTooLarge}
+				}
+				return err
+			}
+			if int64(len(value)) > cfg.maxValueSize {
+				return &UploadError{Field: part.FormName(), Limit: cfg.maxValueSize, err: ErrUploadValueTooLarge}
+			}
+			values.Add(part.FormName(), string(value))
+			continue
+		}
+
+		file, err := storeUpload(part, cfg)
+		if file != nil {
+			u.files[file.Field] = append(u.files[file.Field], file)
+		}
+		if err != nil {
+			if body.exceeded {
+				return &UploadError{Field: part.FormName(), Filename: part.FileName(), Limit: cfg.maxBodySize, err: ErrUploadBodyTooLarge}
+			}
+			return err
+		}
+	}
+
+	r.request.PostForm = values
+	if r.request.Form == nil {
+		r.request.Form = make(url.Values)
+	}
+	for k, v := range r.request.URL.Query() {
+		r.request.Form[k] = append(r.request.Form[k], v...)
+	}
+	for k, v := range values {
+		r.request.Form[k] = append(r.request.Form[k], v...)
+	}
+
+	return nil
+}
+
+// storeUpload sniffs the content type and streams the part into a temporary file.
+// The returned file is set as soon as a temporary file exists, so it can be cleaned up on errors.
+func storeUpload(part *multipart.Part, cfg uploadConfig) (*UploadedFile, error) {
+	filename := path.Base(strings.Replace(part.FileName(), `\`, "/", -1))
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(part, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	head = head[:n]
+
+	contentType := http.DetectContentType(head)
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		contentType = mediaType
+	}
+
+	if !typeAllowed(contentType, cfg.allowedTypes) {
+		return nil, &UploadError{Field: part.FormName(), Filename: filename, ContentType: contentType, err: ErrUploadTypeNotAllowed}
+	}
+
+	tmp, err := ioutil.TempFile(cfg.tempDir, "flamingo-upload-")
+	if err != nil {
+		return nil, err
+	}
+	defer tmp.Close()
+
+	file := &UploadedFile{
+		Field:       part.FormName(),
+		Filename:    filename,
+		ContentType: contentType,
+		Header:      part.Header,
+		path:        tmp.Name(),
+	}
+
+	file.Size, err = io.Copy(tmp, io.MultiReader(bytes.NewReader(head), io.LimitReader(part, cfg.maxFileSize-int64(len(head))+1)))
+	if err != nil {
+		return file, err
+	}
+
+	if file.Size > cfg.maxFileSize {
+		return file, &UploadError{Field: file.Field, Filename: filename, Limit: cfg.maxFileSize, err: ErrUploadFileTooLarge}
+	}
+
+	return file, nil
+}
+
+// typeAllowed matches a media type against an allowlist, entries like "image/*" match all subtypes
+func typeAllowed(contentType string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+
+	for _, a := range allowed {
+		if a == contentType || (strings.HasSuffix(a, "/*") && strings.HasPrefix(contentType, strings.TrimSuffix(a, "*"))) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (u *uploads) cleanup() {
+	for _, files := range u.files {
+		for _, file := range files {
+			_ = os.Remove(file.path)
+		}
+	}
+}
+
+// Read from the body until the limit is exceeded
+func (l *limitedBody) Read(p []byte) (int, error) {
+	if l.exceeded {
+		return 0, ErrUploadBodyTooLarge
+	}
+
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	if l.n < 0 {
+		l.exceeded = true
+		return n, ErrUploadBodyTooLarge
+	}
+
+	return n, err
+}
+
+// Notify removes the uploaded files of finished requests
+func (s *UploadCleanupSubscriber) Notify(_ context.Context, event flamingo.Event) {
+	if e, ok := event.(*OnFinishEvent); ok && e.Request != nil {
+		if u, ok := e.Request.Values.Load(uploadsKey); ok {
+			u := u.(*uploads)
+			u.mu.Lock()
+			u.cleanup()
+			u.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"flamingo.me/flamingo/v3/framework/config"
+)
+
+func multipartRequest(t *testing.T, values map[string]string, files map[string][]byte) *http.Request {
+	t.Helper()
+
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	for k, v := range values {
+		require.NoError(t, writer.WriteField(k, v))
+	}
+	for name, content := range files {
+		part, err := writer.CreateFormFile(name, "../"+name+".bin")
+		require.NoError(t, err)
+		_, err = part.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/upload?q=1", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestRequest_File(t *testing.T) {
+	dir, err := ioutil.TempDir("", "upload-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cfg := defaultUploadConfig
+	cfg.tempDir = dir
+
+	req := CreateRequest(multipartRequest(t, map[string]string{"title": "hello"}, map[string][]byte{"doc": []byte("plain text content")}), nil)
+	req.upload = &cfg
+
+	file, err := req.File("doc")
+	require.NoError(t, err)
+	assert.Equal(t, "doc", file.Field)
+	assert.Equal(t, "doc.bin", file.Filename)
+	assert.Equal(t, "text/plain", file.ContentType)
+	assert.Equal(t, int64(18), file.Size)
+	assert.True(t, strings.HasPrefix(file.Path(), dir))
+
+	f, err := file.Open()
+	require.NoError(t, err)
+	content, err := ioutil.ReadAll(f)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	assert.Equal(t, "plain text content", string(content))
+
+	title, err := req.Form1("title")
+	require.NoError(t, err)
+	assert.Equal(t, "hello", title)
+	q, err := req.Form1("q")
+	require.NoError(t, err)
+	assert.Equal(t, "1", q)
+
+	_, err = req.File("missing")
+	assert.Equal(t, ErrFileNotFound, err)
+
+	new(UploadCleanupSubscriber).Notify(context.Background(), &OnFinishEvent{OnRequestEvent: OnRequestEvent{Request: req}})
+	_, err = os.Stat(file.Path())
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRequest_FileLimits(t *testing.T) {
+	png := append([]byte("\x89PNG\x0D\x0A\x1A\x0A"), make([]byte, 100)...)
+
+	t.Run("file too large", func(t *testing.T) {
+		cfg := defaultUploadConfig
+		cfg.maxFileSize = 10
+
+		req := CreateRequest(multipartRequest(t, nil, map[string][]byte{"doc": []byte("plain text content")}), nil)
+		req.upload = &cfg
+
+		_, err := req.File("doc")
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrUploadFileTooLarge))
+		uploadErr := new(UploadError)
+		require.True(t, errors.As(err, &uploadErr))
+		assert.Equal(t, "doc", uploadErr.Field)
+		assert.Equal(t, int64(10), uploadErr.Limit)
+	})
+
+	t.Run("body too large", func(t *testing.T) {
+		cfg := defaultUploadConfig
+		cfg.maxBodySize = 64
+
+		req := CreateRequest(multipartRequest(t, nil, map[string][]byte{"doc": png}), nil)
+		req.upload = &cfg
+
+		_, err := req.Files()
+		assert.True(t, errors.Is(err, ErrUploadBodyTooLarge))
+	})
+
+	t.Run("value too large", func(t *testing.T) {
+		cfg := defaultUploadConfig
+		cfg.maxValueSize = 4
+
+		req := CreateRequest(multipartRequest(t, map[string]string{"comment": "too long"}, nil), nil)
+		req.upload = &cfg
+
+		_, err := req.Files()
+		assert.True(t, errors.Is(err, ErrUploadValueTooLarge))
+		uploadErr := new(UploadError)
+		require.True(t, errors.As(err, &uploadErr))
+		assert.Equal(t, "comment", uploadErr.Field)
+		assert.Equal(t, int64(4), uploadErr.Limit)
+	})
+
+	t.Run("type not allowed", func(t *testing.T) {
+		cfg := defaultUploadConfig
+		cfg.allowedTypes = []string{"image/*"}
+
+		req := CreateRequest(multipartRequest(t, nil, map[string][]byte{"doc": []byte("plain text content")}), nil)
+		req.upload = &cfg
+
+		_, err := req.File("doc")
+		assert.True(t, errors.Is(err, ErrUploadTypeNotAllowed))
+
+		req = CreateRequest(multipartRequest(t, nil, map[string][]byte{"image": png}), nil)
+		req.upload = &cfg
+
+		file, err := req.File("image")
+		require.NoError(t, err)
+		assert.Equal(t, "image/png", file.ContentType)
+		new(UploadCleanupSubscriber).Notify(context.Background(), &OnFinishEvent{OnRequestEvent: OnRequestEvent{Request: req}})
+	})
+
+	t.Run("not multipart", func(t *testing.T) {
+		req := CreateRequest(httptest.NewRequest(http.MethodPost, "/", strings.NewReader("a=b")), nil)
+		_, err := req.Files()
+		assert.True(t, errors.Is(err, ErrNotMultipart))
+	})
+}
+
+func TestUploadConfig_Inject(t *testing.T) {
+	cfg := new(UploadConfig).Inject(&struct {
+		MaxBodySize  float64      `inject:"config:flamingo.web.upload.maxBodySize,optional"`
+		MaxFileSize  float64      `inject:"config:flamingo.web.upload.maxFileSize,optional"`
+		MaxValueSize float64      `inject:"config:flamingo.web.upload.maxValueSize,optional"`
+		AllowedTypes config.Slice `inject:"config:flamingo.web.upload.allowedTypes,optional"`
+		TempDir      string       `inject:"config:flamingo.web.upload.tempDir,optional"`
+	}{
+		MaxFileSize:  1024,
+		AllowedTypes: config.Slice{"image/*"},
+		TempDir:      "/tmp/uploads",
+	})
+
+	assert.Equal(t, uploadConfig{
+		maxBodySize:  defaultUploadConfig.maxBodySize,
+		maxFileSize:  1024,
+		maxValueSize: defaultUploadConfig.maxValueSize,
+		allowedTypes: []string{"image/*"},
+		tempDir:      "/tmp/uploads",
+	}, cfg.config)
+	assert.Equal(t, defaultUploadConfig, new(UploadConfig).Inject(nil).config)
+}
@@ -1,6 +1,6 @@
 module flamingo.me/flamingo/v3
 
-go 1.13
+go 1.21
 
 require (
 	contrib.go.opencensus.io/exporter/jaeger v0.2.0
@@ -42,6 +42,11 @@ require (
 	github.com/vektra/mockery v0.0.0-20181123154057-e78b021dcbb5 // indirect
 	github.com/zemirco/memorystore v0.0.0-20160308183530-ecd57e5134f6
 	go.opencensus.io v0.22.3
+	go.opentelemetry.io/otel v1.27.0
+	go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp v1.27.0
+	go.opentelemetry.io/otel/sdk v1.27.0
+	go.opentelemetry.io/otel/trace v1.27.0
+	go.opentelemetry.io/proto/otlp v1.2.0
 	go.uber.org/automaxprocs v1.3.0
 	go.uber.org/multierr v1.5.0 // indirect
 	go.uber.org/zap v1.14.0
@@ -52,6 +57,7 @@ require (
 	golang.org/x/tools v0.0.0-20200225230052-807dcd883420 // indirect
 	google.golang.org/api v0.19.0 // indirect
 	google.golang.org/appengine v1.6.1 // indirect
+	google.golang.org/protobuf v1.34.1
 	gopkg.in/square/go-jose.v2 v2.1.9 // indirect
 	gopkg.in/yaml.v2 v2.2.8 // indirect
 )